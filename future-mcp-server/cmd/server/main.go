@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
@@ -10,48 +11,119 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/future-mcp/future-mcp-server/internal/auth"
+	"github.com/future-mcp/future-mcp-server/internal/database"
 	"github.com/future-mcp/future-mcp-server/internal/handler"
 	"github.com/future-mcp/future-mcp-server/internal/middleware"
 	"github.com/future-mcp/future-mcp-server/internal/repository"
 	"github.com/future-mcp/future-mcp-server/internal/service"
 	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/spf13/viper"
 )
 
 func main() {
+	devTokenRole := flag.String("dev-token", "", "签发指定角色（如teacher）的开发用JWT并退出")
+	flag.Parse()
+
 	// 初始化配置
-	if err := initConfig(); err != nil {
+	configFound, err := initConfig()
+	if err != nil {
 		log.Fatalf("Failed to initialize config: %v", err)
 	}
 
+	// 开发环境以随机用户ID签发令牌，代替内置示例用户
+	if *devTokenRole != "" {
+		authService := auth.NewService(viper.GetString("auth.jwt_secret"))
+		token, err := authService.GenerateToken(uuid.New(), "dev-"+*devTokenRole, *devTokenRole, time.Duration(viper.GetInt("auth.jwt_expire"))*time.Second)
+		if err != nil {
+			log.Fatalf("Failed to generate dev token: %v", err)
+		}
+		fmt.Println(token)
+		return
+	}
+
 	// 初始化日志
 	if err := logger.Init(); err != nil {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
 
+	if !configFound {
+		logger.Warn("Config file not found, using defaults")
+	}
+
 	logger.Info("Starting TALink MCP Server...")
 
 	// 初始化缓存服务 (暂时使用内存实现)
 	cacheService := service.NewMemoryCacheService()
 
-	// 初始化存储库 (暂时使用内存实现)
-	materialRepo := repository.NewMemoryMaterialRepository()
+	// 初始化存储库 (根据database.backend选择内存或PostgreSQL实现)
+	materialRepo, err := newMaterialRepository()
+	if err != nil {
+		logger.Fatal("Failed to initialize material repository", logger.Any("error", err))
+	}
+	// 素材写操作发布变更事件，用于使已缓存的工具结果失效
+	materialEvents := repository.NewObservableMaterialRepository(materialRepo)
 	// TODO: 实现其他仓库
 	repos := &repository.Repositories{
-		Material: materialRepo,
+		Material: materialEvents,
 	}
 
-	// 认证服务暂时未实现
-	// authService := auth.NewService(viper.GetString("auth.jwt_secret"))
+	// 认证服务：MCP路由从Bearer JWT中获取调用者的用户ID与角色
+	authService := auth.NewService(viper.GetString("auth.jwt_secret"))
+
+	// 分页游标编解码器，素材搜索与MCP列表共用
+	cursorCodec := mcp.NewCursorCodec(viper.GetString("mcp.cursor_secret"))
 
 	// 初始化素材服务
-	materialService := service.NewMaterialService(repos.Material, cacheService)
+	materialService := service.NewMaterialService(repos.Material, cacheService, cursorCodec)
+
+	// 按工具名设置的执行超时、并发上限与结果缓存时间
+	var toolTimeouts map[string]time.Duration
+	if err := viper.UnmarshalKey("mcp.tool_timeouts", &toolTimeouts); err != nil {
+		logger.Fatal("Invalid mcp.tool_timeouts", logger.Any("error", err))
+	}
+	var toolConcurrencyLimits map[string]int
+	if err := viper.UnmarshalKey("mcp.tool_concurrency_limits", &toolConcurrencyLimits); err != nil {
+		logger.Fatal("Invalid mcp.tool_concurrency_limits", logger.Any("error", err))
+	}
+	var toolCacheTTLs map[string]time.Duration
+	if err := viper.UnmarshalKey("mcp.tool_cache_ttls", &toolCacheTTLs); err != nil {
+		logger.Fatal("Invalid mcp.tool_cache_ttls", logger.Any("error", err))
+	}
 
 	// 初始化MCP服务
-	mcpService := service.NewMCPService(&service.MCPServiceConfig{
+	mcpService, err := service.NewMCPService(&service.MCPServiceConfig{
 		MaterialService: materialService,
+		EnforceRoles:    true,
+		MaterialFiles:   newMaterialFileReader(),
+		Cache:           cacheService,
+		ToolCacheTTLs:   toolCacheTTLs,
+		MaterialEvents:  materialEvents,
+		Server: mcp.ServerConfig{
+			BatchMaxSize:     viper.GetInt("mcp.batch_max_size"),
+			BatchConcurrency: viper.GetInt("mcp.batch_concurrency"),
+
+			ListChangedDebounce: viper.GetDuration("mcp.list_changed_debounce"),
+
+			Cursors:      cursorCodec,
+			ListPageSize: viper.GetInt("mcp.list_page_size"),
+
+			ToolTimeout:  viper.GetDuration("mcp.tool_timeout"),
+			ToolTimeouts: toolTimeouts,
+
+			ToolConcurrency:       viper.GetInt("mcp.tool_concurrency"),
+			ToolConcurrencyLimits: toolConcurrencyLimits,
+			ToolQueueTimeout:      viper.GetDuration("mcp.tool_queue_timeout"),
+
+			ElicitationTimeout: viper.GetDuration("mcp.elicitation_timeout"),
+		},
 	})
+	if err != nil {
+		logger.Fatal("Failed to initialize MCP service", logger.Any("error", err))
+	}
 
 	// 初始化工具服务
 	toolService := service.NewToolService(mcpService)
@@ -62,7 +134,7 @@ func main() {
 	}
 
 	// 初始化Gin路由
-	r := setupRouter(mcpService)
+	r := setupRouter(mcpService, authService)
 
 	// 获取服务器配置
 	host := viper.GetString("server.host")
@@ -96,11 +168,12 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Fatal("Server forced to shutdown", logger.Any("error", err))
 	}
+	mcpService.Close()
 
 	logger.Info("Server exited")
 }
 
-func initConfig() error {
+func initConfig() (bool, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath("./config")
@@ -111,13 +184,12 @@ func initConfig() error {
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			logger.Warn("Config file not found, using defaults")
-		} else {
-			return fmt.Errorf("failed to read config: %w", err)
+			return false, nil
 		}
+		return false, fmt.Errorf("failed to read config: %w", err)
 	}
 
-	return nil
+	return true, nil
 }
 
 func setDefaults() {
@@ -127,6 +199,7 @@ func setDefaults() {
 	viper.SetDefault("server.mode", "release")
 
 	// 数据库配置
+	viper.SetDefault("database.backend", "memory")
 	viper.SetDefault("database.host", "localhost")
 	viper.SetDefault("database.port", 5432)
 	viper.SetDefault("database.user", "future_mcp")
@@ -160,6 +233,23 @@ func setDefaults() {
 	viper.SetDefault("storage.endpoint", "")
 	viper.SetDefault("storage.access_key", "")
 	viper.SetDefault("storage.secret_key", "")
+	viper.SetDefault("storage.local_path", "./storage")
+
+	// 功能开关
+	viper.SetDefault("features.enable_websocket", true)
+
+	// MCP协议配置
+	viper.SetDefault("mcp.batch_max_size", 10)
+	viper.SetDefault("mcp.batch_concurrency", 4)
+	viper.SetDefault("mcp.list_changed_debounce", "200ms")
+	viper.SetDefault("mcp.list_page_size", 100)
+	viper.SetDefault("mcp.cursor_secret", "")
+	viper.SetDefault("mcp.tool_timeout", "30s")
+	viper.SetDefault("mcp.tool_concurrency", 16)
+	viper.SetDefault("mcp.tool_queue_timeout", "5s")
+	viper.SetDefault("mcp.elicitation_timeout", "5m")
+	viper.SetDefault("mcp.websocket_allowed_origins", []string{})
+	viper.SetDefault("mcp.websocket_max_inflight", 32)
 
 	// 日志配置
 	viper.SetDefault("log.level", "info")
@@ -167,7 +257,31 @@ func setDefaults() {
 	viper.SetDefault("log.output", "stdout")
 }
 
-func setupRouter(mcpService *service.MCPService) *gin.Engine {
+// newMaterialFileReader 根据存储配置创建素材文件读取器，目前只支持本地存储，其他存储返回nil
+func newMaterialFileReader() service.MaterialFileReader {
+	if viper.GetString("storage.provider") != "local" {
+		return nil
+	}
+	return service.NewLocalMaterialFileReader(viper.GetString("storage.local_path"))
+}
+
+// newMaterialRepository 根据配置创建素材仓库
+func newMaterialRepository() (repository.MaterialRepository, error) {
+	switch backend := viper.GetString("database.backend"); backend {
+	case "", "memory":
+		return repository.NewMemoryMaterialRepository(), nil
+	case "postgres":
+		db, err := database.InitDB()
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewPostgresMaterialRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported database backend: %s", backend)
+	}
+}
+
+func setupRouter(mcpService *mcp.Server, authService *auth.Service) *gin.Engine {
 	if viper.GetString("server.mode") == "release" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -197,13 +311,24 @@ func setupRouter(mcpService *service.MCPService) *gin.Engine {
 
 	// MCP协议路由
 	mcpGroup := r.Group("/mcp")
+	mcpGroup.Use(middleware.Authenticate(authService))
 	{
 		mcpGroup.POST("/jsonrpc", handler.MCPHandler(mcpService))
 		mcpGroup.GET("/sse", handler.MCPSSEHandler(mcpService))
+
+		// Streamable HTTP传输：POST发送消息，GET打开推送流，DELETE终止会话
+		mcpGroup.POST("", handler.MCPStreamableHandler(mcpService))
+		mcpGroup.GET("", handler.MCPSSEHandler(mcpService))
+		mcpGroup.DELETE("", handler.MCPSessionDeleteHandler(mcpService))
 	}
 
-	// WebSocket路由（用于实时通信）
-	// r.GET("/ws", handler.WebSocketHandler(services)) // 暂时移除
+	// WebSocket路由（用于实时双向通信）
+	if viper.GetBool("features.enable_websocket") {
+		mcpGroup.GET("/ws", handler.MCPWebSocketHandler(mcpService, handler.WebSocketConfig{
+			AllowedOrigins: viper.GetStringSlice("mcp.websocket_allowed_origins"),
+			MaxInFlight:    viper.GetInt("mcp.websocket_max_inflight"),
+		}))
+	}
 
 	return r
 }
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/auth"
+	"github.com/future-mcp/future-mcp-server/internal/database"
+	"github.com/future-mcp/future-mcp-server/internal/repository"
+	"github.com/future-mcp/future-mcp-server/internal/service"
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+)
+
+// stdio传输入口：桌面助手、编辑器插件以子进程方式启动MCP服务器，
+// 通过stdin/stdout按行交换JSON-RPC消息，日志输出到stderr。
+func main() {
+	configFile := flag.String("config", "", "配置文件路径")
+	flag.Parse()
+
+	// 初始化配置
+	configFound, err := initConfig(*configFile)
+	if err != nil {
+		log.Fatalf("Failed to initialize config: %v", err)
+	}
+
+	// 初始化日志
+	if err := logger.Init(); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+
+	if !configFound {
+		logger.Warn("Config file not found, using defaults")
+	}
+
+	logger.Info("Starting TALink MCP Server (stdio)...")
+
+	// 初始化缓存服务 (暂时使用内存实现)
+	cacheService := service.NewMemoryCacheService()
+
+	// 初始化存储库
+	materialRepo, err := newMaterialRepository()
+	if err != nil {
+		logger.Fatal("Failed to initialize material repository", logger.Any("error", err))
+	}
+	// 素材写操作发布变更事件，用于使已缓存的工具结果失效
+	materialEvents := repository.NewObservableMaterialRepository(materialRepo)
+
+	// 分页游标编解码器，素材搜索与MCP列表共用
+	cursorCodec := mcp.NewCursorCodec(viper.GetString("mcp.cursor_secret"))
+
+	// 初始化素材服务
+	materialService := service.NewMaterialService(materialEvents, cacheService, cursorCodec)
+
+	// 按工具名设置的执行超时、并发上限与结果缓存时间
+	var toolTimeouts map[string]time.Duration
+	if err := viper.UnmarshalKey("mcp.tool_timeouts", &toolTimeouts); err != nil {
+		logger.Fatal("Invalid mcp.tool_timeouts", logger.Any("error", err))
+	}
+	var toolConcurrencyLimits map[string]int
+	if err := viper.UnmarshalKey("mcp.tool_concurrency_limits", &toolConcurrencyLimits); err != nil {
+		logger.Fatal("Invalid mcp.tool_concurrency_limits", logger.Any("error", err))
+	}
+	var toolCacheTTLs map[string]time.Duration
+	if err := viper.UnmarshalKey("mcp.tool_cache_ttls", &toolCacheTTLs); err != nil {
+		logger.Fatal("Invalid mcp.tool_cache_ttls", logger.Any("error", err))
+	}
+
+	// 初始化MCP服务，与HTTP服务器共用同一套工具和资源注册
+	mcpService, err := service.NewMCPService(&service.MCPServiceConfig{
+		MaterialService: materialService,
+		EnforceRoles:    true,
+		MaterialFiles:   newMaterialFileReader(),
+		Cache:           cacheService,
+		ToolCacheTTLs:   toolCacheTTLs,
+		MaterialEvents:  materialEvents,
+		Server: mcp.ServerConfig{
+			ListChangedDebounce: viper.GetDuration("mcp.list_changed_debounce"),
+			Cursors:             cursorCodec,
+			ListPageSize:        viper.GetInt("mcp.list_page_size"),
+			ToolTimeout:         viper.GetDuration("mcp.tool_timeout"),
+			ToolTimeouts:        toolTimeouts,
+
+			ToolConcurrency:       viper.GetInt("mcp.tool_concurrency"),
+			ToolConcurrencyLimits: toolConcurrencyLimits,
+			ToolQueueTimeout:      viper.GetDuration("mcp.tool_queue_timeout"),
+
+			ElicitationTimeout: viper.GetDuration("mcp.elicitation_timeout"),
+		},
+	})
+	if err != nil {
+		logger.Fatal("Failed to initialize MCP service", logger.Any("error", err))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// stdio由本地用户启动，以配置的用户身份与角色调用工具，未配置用户时按访客处理
+	if configured := viper.GetString("auth.stdio_user_id"); configured != "" {
+		userID, err := uuid.Parse(configured)
+		if err != nil {
+			logger.Fatal("Invalid auth.stdio_user_id", logger.Any("error", err))
+		}
+		ctx = mcp.ContextWithUserID(ctx, userID)
+		ctx = auth.ContextWithRole(ctx, types.UserRole(viper.GetString("auth.stdio_user_role")))
+	}
+
+	if err := serveStdio(ctx, mcpService, os.Stdin, os.Stdout); err != nil {
+		logger.Error("Stdio transport stopped", logger.Any("error", err))
+	}
+	mcpService.Close()
+
+	logger.Info("Server exited")
+}
+
+func initConfig(configFile string) (bool, error) {
+	if configFile != "" {
+		viper.SetConfigFile(configFile)
+	} else {
+		viper.SetConfigName("config")
+		viper.SetConfigType("yaml")
+		viper.AddConfigPath("./config")
+		viper.AddConfigPath(".")
+	}
+
+	// 设置默认值
+	setDefaults()
+
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	// stdout用于协议通信，日志不能写入stdout
+	if viper.GetString("log.output") != "file" {
+		viper.Set("log.output", "stderr")
+	}
+
+	return true, nil
+}
+
+func setDefaults() {
+	// 存储后端: memory/postgres
+	viper.SetDefault("database.backend", "memory")
+	viper.SetDefault("database.host", "localhost")
+	viper.SetDefault("database.port", 5432)
+	viper.SetDefault("database.user", "future_mcp")
+	viper.SetDefault("database.password", "password")
+	viper.SetDefault("database.dbname", "future_mcp")
+	viper.SetDefault("database.sslmode", "disable")
+	viper.SetDefault("database.max_idle_conns", 10)
+	viper.SetDefault("database.max_open_conns", 100)
+
+	// MCP协议配置
+	viper.SetDefault("mcp.list_changed_debounce", "200ms")
+	viper.SetDefault("mcp.list_page_size", 100)
+	viper.SetDefault("mcp.cursor_secret", "")
+	viper.SetDefault("mcp.tool_timeout", "30s")
+	viper.SetDefault("mcp.tool_concurrency", 16)
+	viper.SetDefault("mcp.tool_queue_timeout", "5s")
+	viper.SetDefault("mcp.elicitation_timeout", "5m")
+
+	// 存储配置
+	viper.SetDefault("storage.provider", "local")
+	viper.SetDefault("storage.local_path", "./storage")
+
+	// 日志配置
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("log.format", "json")
+	viper.SetDefault("log.output", "stderr")
+}
+
+// newMaterialFileReader 根据存储配置创建素材文件读取器，目前只支持本地存储，其他存储返回nil
+func newMaterialFileReader() service.MaterialFileReader {
+	if viper.GetString("storage.provider") != "local" {
+		return nil
+	}
+	return service.NewLocalMaterialFileReader(viper.GetString("storage.local_path"))
+}
+
+// newMaterialRepository 根据配置创建素材仓库
+func newMaterialRepository() (repository.MaterialRepository, error) {
+	switch backend := viper.GetString("database.backend"); backend {
+	case "", "memory":
+		return repository.NewMemoryMaterialRepository(), nil
+	case "postgres":
+		db, err := database.InitDB()
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewPostgresMaterialRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported database backend: %s", backend)
+	}
+}
+
+// serveStdio 在stdin/stdout上运行换行分隔的JSON-RPC传输，直到输入结束或上下文取消
+func serveStdio(ctx context.Context, mcpService *mcp.Server, in io.Reader, out io.Writer) error {
+	// stdio没有断线补发机制，stdout长时间阻塞导致事件积压时结束服务而不是丢弃通知
+	session := mcpService.Sessions().Create()
+	session.Pin()
+	session.DisableReplay()
+
+	// 先关闭会话，使等待客户端响应的请求退出，再等待处理中的请求完成
+	var inflight sync.WaitGroup
+	defer func() {
+		session.Close()
+		inflight.Wait()
+	}()
+
+	var writeMu sync.Mutex
+	encoder := json.NewEncoder(out)
+	write := func(message interface{}) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if err := encoder.Encode(message); err != nil {
+			logger.Error("Failed to write stdio message", logger.Any("error", err))
+		}
+	}
+
+	// 与请求相关的通知（如进度）直接写出，保证先于该请求的响应到达
+	ctx = mcp.ContextWithRequestNotifier(mcp.ContextWithSession(ctx, session), write)
+
+	// 将会话推送的通知写到stdout
+	go func() {
+		for {
+			select {
+			case <-session.Done():
+				return
+			case event := <-session.Events():
+				write(event.Message)
+			}
+		}
+	}()
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		reader := bufio.NewReaderSize(in, 64*1024)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) > 0 {
+				lines <- line
+			}
+			if err != nil {
+				readErr <- err
+				close(lines)
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-session.Done():
+			return session.Err()
+		case line, ok := <-lines:
+			if !ok {
+				if err := <-readErr; err != io.EOF {
+					return err
+				}
+				return nil
+			}
+			message, errResponse := decodeStdioMessage(line)
+			if errResponse != nil {
+				write(errResponse)
+				continue
+			}
+			session.Touch()
+
+			// 客户端对服务端请求的响应直接投递，不经过请求处理
+			if message.IsResponse() {
+				if !session.DeliverResponse(message.Response()) {
+					logger.Warn("Unmatched client response", logger.Any("id", message.ID))
+				}
+				continue
+			}
+
+			// 工具调用可能长时间运行并等待客户端响应（采样、征询），交给独立goroutine，
+			// 使读取循环继续投递响应和取消通知；其余请求与通知按到达顺序处理
+			request := message.Request()
+			if request.Method == types.MCPMethodToolsCall && request.ID != nil {
+				inflight.Add(1)
+				go func() {
+					defer inflight.Done()
+					if response := handleStdioRequest(ctx, mcpService, request); response != nil {
+						write(response)
+					}
+				}()
+				continue
+			}
+			if response := handleStdioRequest(ctx, mcpService, request); response != nil {
+				write(response)
+			}
+		}
+	}
+}
+
+// decodeStdioMessage 解析一条stdio消息，消息无效时返回应写回客户端的错误响应
+func decodeStdioMessage(line []byte) (*types.MCPRawMessage, *types.MCPResponse) {
+	var message types.MCPRawMessage
+	if err := json.Unmarshal(line, &message); err != nil {
+		logger.Warn("Failed to parse MCP message", logger.Any("error", err))
+		return nil, &types.MCPResponse{
+			MCPMessage: types.MCPMessage{
+				JSONRPC: "2.0",
+			},
+			Error: &types.MCPError{
+				Code:    types.MCPParseError,
+				Message: "Parse error",
+			},
+		}
+	}
+
+	if message.JSONRPC != "2.0" {
+		return nil, &types.MCPResponse{
+			MCPMessage: types.MCPMessage{
+				JSONRPC: "2.0",
+				ID:      message.ID,
+			},
+			Error: &types.MCPError{
+				Code:    types.MCPInvalidRequest,
+				Message: "Unsupported JSON-RPC version",
+			},
+		}
+	}
+
+	return &message, nil
+}
+
+// handleStdioRequest 处理一条请求或通知，通知返回nil
+func handleStdioRequest(ctx context.Context, mcpService *mcp.Server, request *types.MCPRequest) *types.MCPResponse {
+	response, err := mcpService.HandleRequest(ctx, request)
+	if err != nil {
+		logger.Error("Failed to handle MCP request",
+			logger.Any("method", request.Method),
+			logger.Any("error", err))
+
+		response = &types.MCPResponse{
+			MCPMessage: types.MCPMessage{
+				JSONRPC: "2.0",
+				ID:      request.ID,
+			},
+			Error: &types.MCPError{
+				Code:    types.MCPInternalError,
+				Message: "Internal server error",
+			},
+		}
+	}
+
+	// 通知不返回响应
+	if request.ID == nil {
+		return nil
+	}
+
+	return response
+}
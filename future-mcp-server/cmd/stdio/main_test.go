@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+	"github.com/spf13/viper"
+)
+
+func TestMain(m *testing.M) {
+	viper.Set("log.level", "error")
+	if err := logger.Init(); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// runStdio 以给定输入运行stdio传输直到输入结束，返回写到stdout的消息
+func runStdio(t *testing.T, input ...string) []types.MCPResponse {
+	t.Helper()
+
+	mcpService := mcp.NewServer(mcp.ServerConfig{})
+	var out bytes.Buffer
+	if err := serveStdio(context.Background(), mcpService, strings.NewReader(strings.Join(input, "\n")+"\n"), &out); err != nil {
+		t.Fatalf("serveStdio: %v", err)
+	}
+
+	var responses []types.MCPResponse
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var response types.MCPResponse
+		if err := decoder.Decode(&response); err != nil {
+			t.Fatalf("decode output: %v", err)
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
+
+func TestServeStdio(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []string
+		wantIDs   []interface{}
+		wantCodes []int
+	}{
+		{
+			name: "requests in order",
+			input: []string{
+				`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1.0"}}}`,
+				`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+				`{"jsonrpc":"2.0","id":2,"method":"ping"}`,
+				`{"jsonrpc":"2.0","id":"three","method":"ping"}`,
+			},
+			wantIDs:   []interface{}{float64(1), float64(2), "three"},
+			wantCodes: []int{0, 0, 0},
+		},
+		{
+			name: "notifications are applied before later requests",
+			input: []string{
+				`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1.0"}}}`,
+				`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+				`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+			},
+			wantIDs:   []interface{}{float64(1), float64(2)},
+			wantCodes: []int{0, 0},
+		},
+		{
+			name:      "blank lines are skipped",
+			input:     []string{"", `{"jsonrpc":"2.0","id":1,"method":"ping"}`, "   "},
+			wantIDs:   []interface{}{float64(1)},
+			wantCodes: []int{0},
+		},
+		{
+			name:      "parse error",
+			input:     []string{`{"jsonrpc":`},
+			wantIDs:   []interface{}{nil},
+			wantCodes: []int{types.MCPParseError},
+		},
+		{
+			name:      "unsupported version",
+			input:     []string{`{"jsonrpc":"1.0","id":7,"method":"ping"}`},
+			wantIDs:   []interface{}{float64(7)},
+			wantCodes: []int{types.MCPInvalidRequest},
+		},
+		{
+			name:      "request before initialize",
+			input:     []string{`{"jsonrpc":"2.0","id":8,"method":"tools/list"}`},
+			wantIDs:   []interface{}{float64(8)},
+			wantCodes: []int{types.MCPInvalidRequest},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := runStdio(t, tt.input...)
+			if len(responses) != len(tt.wantIDs) {
+				t.Fatalf("got %d responses, want %d: %+v", len(responses), len(tt.wantIDs), responses)
+			}
+
+			// 除工具调用外按到达顺序处理，响应顺序与请求一致
+			for i, response := range responses {
+				if fmt.Sprint(response.ID) != fmt.Sprint(tt.wantIDs[i]) {
+					t.Fatalf("response %d id = %v, want %v", i, response.ID, tt.wantIDs[i])
+				}
+				code := 0
+				if response.Error != nil {
+					code = response.Error.Code
+				}
+				if code != tt.wantCodes[i] {
+					t.Fatalf("response %v error code = %d, want %d", response.ID, code, tt.wantCodes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestServeStdioToolCallHandOff(t *testing.T) {
+	mcpService := mcp.NewServer(mcp.ServerConfig{})
+	mcpService.Tools().RegisterTool(&types.ToolDefinition{
+		Name:        "ask",
+		InputSchema: map[string]interface{}{"type": "object"},
+		Handler: func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+			result, err := mcp.Elicit(ctx.Context, &types.ElicitRequest{
+				Message:         "请填写年级",
+				RequestedSchema: map[string]interface{}{"type": "object"},
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &types.ToolsCallResponse{Content: []types.Content{mcp.TextContent(fmt.Sprint(result.Content["grade"]))}}, nil
+		},
+	})
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- serveStdio(context.Background(), mcpService, inReader, outWriter)
+		outWriter.Close()
+	}()
+
+	send := func(line string) {
+		t.Helper()
+		if _, err := io.WriteString(inWriter, line+"\n"); err != nil {
+			t.Fatalf("write input: %v", err)
+		}
+	}
+	output := bufio.NewReader(outReader)
+	receive := func() types.MCPRawMessage {
+		t.Helper()
+		line, err := output.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("read output: %v", err)
+		}
+		var message types.MCPRawMessage
+		if err := json.Unmarshal(line, &message); err != nil {
+			t.Fatalf("decode %q: %v", line, err)
+		}
+		return message
+	}
+
+	send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{"elicitation":{}},"clientInfo":{"name":"test","version":"1.0"}}}`)
+	if message := receive(); fmt.Sprint(message.ID) != "1" || message.Error != nil {
+		t.Fatalf("initialize response = %+v", message)
+	}
+	send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	send(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"ask"}}`)
+
+	elicitation := receive()
+	if elicitation.Method != types.MCPMethodElicitationCreate {
+		t.Fatalf("got %+v, want elicitation request", elicitation)
+	}
+
+	// 工具等待客户端响应期间，读取循环继续处理其他请求
+	send(`{"jsonrpc":"2.0","id":3,"method":"ping"}`)
+	if message := receive(); fmt.Sprint(message.ID) != "3" || message.Error != nil {
+		t.Fatalf("ping response = %+v, want id 3 while tool is running", message)
+	}
+
+	reply, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      elicitation.ID,
+		"result":  types.ElicitResult{Action: types.ElicitActionAccept, Content: map[string]interface{}{"grade": "grade_8"}},
+	})
+	send(string(reply))
+	result := receive()
+	if fmt.Sprint(result.ID) != "2" || result.Error != nil || !strings.Contains(fmt.Sprint(result.Result), "grade_8") {
+		t.Fatalf("tools/call response = %+v", result)
+	}
+
+	inWriter.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("serveStdio: %v", err)
+	}
+}
+
+func TestNewMaterialRepository(t *testing.T) {
+	tests := []struct {
+		backend string
+		wantErr bool
+	}{
+		{"", false},
+		{"memory", false},
+		{"mysql", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.backend, func(t *testing.T) {
+			viper.Set("database.backend", tt.backend)
+			t.Cleanup(func() { viper.Set("database.backend", "") })
+
+			repo, err := newMaterialRepository()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && repo == nil {
+				t.Fatal("repository is nil")
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -80,3 +81,18 @@ func (s *Service) ValidateAPIKey(apiKey string) (*types.User, error) {
 		Role:     types.UserRoleDeveloper,
 	}, nil
 }
+
+type roleContextKey struct{}
+
+// ContextWithRole 在上下文中设置认证后调用者的角色，由传输层在认证后调用
+func ContextWithRole(ctx context.Context, role types.UserRole) context.Context {
+	return context.WithValue(ctx, roleContextKey{}, role)
+}
+
+// RoleFromContext 获取认证后调用者的角色，未认证的请求返回访客角色
+func RoleFromContext(ctx context.Context) types.UserRole {
+	if role, ok := ctx.Value(roleContextKey{}).(types.UserRole); ok && role != "" {
+		return role
+	}
+	return types.UserRoleGuest
+}
@@ -1,54 +1,55 @@
 package handler
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 
-	"github.com/future-mcp/future-mcp-server/internal/service"
 	"github.com/future-mcp/future-mcp-server/internal/types"
 	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
 	"github.com/gin-gonic/gin"
 )
 
 // MCPHandler MCP JSON-RPC处理器
-func MCPHandler(mcpService *service.MCPService) gin.HandlerFunc {
+// 请求体可以是单条消息或消息数组（批量请求）。没有id的消息视为通知，不返回响应；
+// 仅包含通知时返回202。
+func MCPHandler(mcpService *mcp.Server) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		body, err := c.GetRawData()
+		if err != nil {
+			logger.Warn("Failed to read MCP request", logger.Any("error", err))
+			c.JSON(http.StatusBadRequest, newMCPErrorResponse(nil, types.MCPParseError, "Parse error"))
+			return
+		}
+
+		// 获取用户上下文
+		ctx := extractUserContext(c)
+
+		// 生成类工具可能长时间等待采样或征询，征询受mcp.elicitation_timeout、执行受工具超时约束，取消服务器写超时
+		clearWriteDeadline(c)
+
+		body = bytes.TrimSpace(body)
+		if len(body) > 0 && body[0] == '[' {
+			handleBatchMCPRequest(c, ctx, mcpService, body)
+			return
+		}
+
 		// 解析请求
 		var request types.MCPRequest
-		if err := c.ShouldBindJSON(&request); err != nil {
+		if err := json.Unmarshal(body, &request); err != nil {
 			logger.Warn("Failed to parse MCP request", logger.Any("error", err))
-			c.JSON(http.StatusBadRequest, types.MCPResponse{
-				MCPMessage: types.MCPMessage{
-					JSONRPC: "2.0",
-					ID:      request.ID,
-				},
-				Error: &types.MCPError{
-					Code:    types.MCPInvalidRequest,
-					Message: "Invalid JSON-RPC request",
-				},
-			})
+			c.JSON(http.StatusBadRequest, newMCPErrorResponse(nil, types.MCPParseError, "Parse error"))
 			return
 		}
 
-		// 验证协议版本
-		if request.JSONRPC != "2.0" {
-			c.JSON(http.StatusBadRequest, types.MCPResponse{
-				MCPMessage: types.MCPMessage{
-					JSONRPC: "2.0",
-					ID:      request.ID,
-				},
-				Error: &types.MCPError{
-					Code:    types.MCPInvalidRequest,
-					Message: "Unsupported JSON-RPC version",
-				},
-			})
+		if response := validateMCPRequest(&request); response != nil {
+			c.JSON(http.StatusBadRequest, response)
 			return
 		}
 
-		// 获取用户上下文
-		ctx := extractUserContext(c)
-
 		// 处理请求
 		response, err := mcpService.HandleRequest(ctx, &request)
 		if err != nil {
@@ -56,16 +57,13 @@ func MCPHandler(mcpService *service.MCPService) gin.HandlerFunc {
 				logger.Any("method", request.Method),
 				logger.Any("error", err))
 
-			c.JSON(http.StatusInternalServerError, types.MCPResponse{
-				MCPMessage: types.MCPMessage{
-					JSONRPC: "2.0",
-					ID:      request.ID,
-				},
-				Error: &types.MCPError{
-					Code:    types.MCPInternalError,
-					Message: "Internal server error",
-				},
-			})
+			c.JSON(http.StatusInternalServerError, newMCPErrorResponse(request.ID, types.MCPInternalError, "Internal server error"))
+			return
+		}
+
+		// 通知或已取消的请求不返回响应
+		if request.ID == nil || response == nil {
+			c.Status(http.StatusAccepted)
 			return
 		}
 
@@ -73,42 +71,85 @@ func MCPHandler(mcpService *service.MCPService) gin.HandlerFunc {
 	}
 }
 
-// MCPSSEHandler MCP SSE处理器
-func MCPSSEHandler(mcpService *service.MCPService) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// 设置SSE头
-		c.Header("Content-Type", "text/event-stream")
-		c.Header("Cache-Control", "no-cache")
-		c.Header("Connection", "keep-alive")
-		c.Header("Access-Control-Allow-Origin", "*")
+// handleBatchMCPRequest 处理批量请求
+// 无法解析或不合法的消息返回错误响应，其余消息在并发上限内同时执行。
+// 错误响应排在执行结果之前，JSON-RPC批量响应不要求与请求顺序一致，客户端按id匹配。
+func handleBatchMCPRequest(c *gin.Context, ctx context.Context, mcpService *mcp.Server, body []byte) {
+	var messages []json.RawMessage
+	if err := json.Unmarshal(body, &messages); err != nil {
+		logger.Warn("Failed to parse batch MCP requests", logger.Any("error", err))
+		c.JSON(http.StatusBadRequest, newMCPErrorResponse(nil, types.MCPParseError, "Parse error"))
+		return
+	}
 
-		// 获取用户上下文
-		ctx := extractUserContext(c)
+	if len(messages) == 0 {
+		c.JSON(http.StatusBadRequest, newMCPErrorResponse(nil, types.MCPInvalidRequest, "Empty batch request"))
+		return
+	}
 
-		// 升级为SSE连接
-		c.Writer.Flush()
+	// 限制批量请求数量
+	if maxSize := mcpService.BatchMaxSize(); len(messages) > maxSize {
+		c.JSON(http.StatusBadRequest, newMCPErrorResponse(nil, types.MCPInvalidRequest,
+			fmt.Sprintf("Too many requests in batch (max %d)", maxSize)))
+		return
+	}
 
-		// 处理SSE请求（这里简化实现）
-		// 实际应该解析SSE格式的请求并返回流式响应
+	var responses []*types.MCPResponse
+	requests := make([]*types.MCPRequest, 0, len(messages))
+	for _, message := range messages {
+		var request types.MCPRequest
+		if err := json.Unmarshal(message, &request); err != nil {
+			responses = append(responses, newMCPErrorResponse(nil, types.MCPInvalidRequest, "Invalid JSON-RPC request"))
+			continue
+		}
+		if response := validateMCPRequest(&request); response != nil {
+			responses = append(responses, response)
+			continue
+		}
+		requests = append(requests, &request)
+	}
 
-		// 发送初始连接确认
-		fmt.Fprintf(c.Writer, "data: %s\n\n", `{"type": "connected", "message": "TALink MCP Server connected"}`)
-		c.Writer.Flush()
+	responses = append(responses, mcpService.HandleBatch(ctx, requests)...)
 
-		// 保持连接（简化实现）
-		<-ctx.Done()
+	// 仅包含通知
+	if len(responses) == 0 {
+		c.Status(http.StatusAccepted)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// validateMCPRequest 校验JSON-RPC请求，不合法时返回错误响应
+func validateMCPRequest(request *types.MCPRequest) *types.MCPResponse {
+	if request.JSONRPC != "2.0" {
+		return newMCPErrorResponse(request.ID, types.MCPInvalidRequest, "Unsupported JSON-RPC version")
+	}
+	if request.Method == "" {
+		return newMCPErrorResponse(request.ID, types.MCPInvalidRequest, "Invalid JSON-RPC request")
+	}
+	return nil
+}
+
+// newMCPErrorResponse 创建JSON-RPC错误响应
+func newMCPErrorResponse(id interface{}, code int, message string) *types.MCPResponse {
+	return &types.MCPResponse{
+		MCPMessage: types.MCPMessage{
+			JSONRPC: "2.0",
+			ID:      id,
+		},
+		Error: &types.MCPError{
+			Code:    code,
+			Message: message,
+		},
 	}
 }
 
 // extractUserContext 从Gin上下文中提取用户上下文
+// 用户ID与角色由认证中间件写入请求上下文，未认证的请求按匿名用户处理
 func extractUserContext(c *gin.Context) context.Context {
 	ctx := c.Request.Context()
 
-	// 从请求头中提取用户信息（如果有的话）
-	if userID := c.GetHeader("X-User-ID"); userID != "" {
-		ctx = context.WithValue(ctx, "user_id", userID)
-	}
-
 	if sessionID := c.GetHeader("X-Session-ID"); sessionID != "" {
 		ctx = context.WithValue(ctx, "session_id", sessionID)
 	}
@@ -120,87 +161,8 @@ func extractUserContext(c *gin.Context) context.Context {
 	return ctx
 }
 
-// BatchMCPHandler 批量MCP请求处理器
-func BatchMCPHandler(mcpService *service.MCPService) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// 解析批量请求
-		var requests []types.MCPRequest
-		if err := c.ShouldBindJSON(&requests); err != nil {
-			logger.Warn("Failed to parse batch MCP requests", logger.Any("error", err))
-			c.JSON(http.StatusBadRequest, types.MCPResponse{
-				Error: &types.MCPError{
-					Code:    types.MCPInvalidRequest,
-					Message: "Invalid batch request",
-				},
-			})
-			return
-		}
-
-		// 限制批量请求数量
-		if len(requests) > 10 {
-			c.JSON(http.StatusBadRequest, types.MCPResponse{
-				Error: &types.MCPError{
-					Code:    types.MCPInvalidRequest,
-					Message: "Too many requests in batch (max 10)",
-				},
-			})
-			return
-		}
-
-		// 获取用户上下文
-		ctx := extractUserContext(c)
-
-		// 处理批量请求
-		responses := make([]types.MCPResponse, len(requests))
-		for i, request := range requests {
-			response, err := mcpService.HandleRequest(ctx, &request)
-			if err != nil {
-				logger.Error("Failed to handle batch MCP request",
-					logger.Any("method", request.Method),
-					logger.Any("error", err))
-
-				responses[i] = types.MCPResponse{
-					MCPMessage: types.MCPMessage{
-						JSONRPC: "2.0",
-						ID:      request.ID,
-					},
-					Error: &types.MCPError{
-						Code:    types.MCPInternalError,
-						Message: "Internal server error",
-					},
-				}
-			} else {
-				responses[i] = *response
-			}
-		}
-
-		c.JSON(http.StatusOK, responses)
-	}
-}
-
-// MCPWebSocketHandler WebSocket处理器（暂未实现）
-func MCPWebSocketHandler(mcpService *service.MCPService) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// 暂未实现WebSocket支持
-		c.JSON(http.StatusNotImplemented, gin.H{
-			"error": "WebSocket support not implemented yet",
-		})
-	}
-}
-// upgradeToWebSocket 升级为WebSocket连接（这里需要导入gorilla/websocket）
-// 注意：实际实现需要导入相应的WebSocket库
-func upgradeToWebSocket(c *gin.Context) (interface{}, error) {
-	// 这里是简化实现，实际需要使用WebSocket库
-	return nil, fmt.Errorf("WebSocket not implemented")
-}
-
-// isNormalClose 检查是否是正常关闭 (暂时未使用)
-func isNormalClose(err error) bool {
-	return false // 简化实现
-}
-
 // MCPHealthHandler MCP健康检查处理器
-func MCPHealthHandler(mcpService *service.MCPService) gin.HandlerFunc {
+func MCPHealthHandler(mcpService *mcp.Server) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 检查MCP服务状态
 		health := map[string]interface{}{
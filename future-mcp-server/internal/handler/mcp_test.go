@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/auth"
+	"github.com/future-mcp/future-mcp-server/internal/middleware"
+	"github.com/future-mcp/future-mcp-server/internal/repository"
+	"github.com/future-mcp/future-mcp-server/internal/service"
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// postJSONRPC 向挂载MCPHandler的路由发送请求体，返回状态码和响应体
+func postJSONRPC(t *testing.T, mcpService *mcp.Server, body string) (int, []byte) {
+	t.Helper()
+
+	r := gin.New()
+	r.POST("/mcp/jsonrpc", MCPHandler(mcpService))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/mcp/jsonrpc", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	data, err := io.ReadAll(w.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	return w.Code, data
+}
+
+func TestMCPHandlerSingle(t *testing.T) {
+	mcpService := mcp.NewServer(mcp.ServerConfig{})
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   int
+	}{
+		{"request", `{"jsonrpc":"2.0","id":1,"method":"ping"}`, http.StatusOK, 0},
+		{"notification", `{"jsonrpc":"2.0","method":"notifications/initialized"}`, http.StatusAccepted, 0},
+		{"parse error", `{"jsonrpc":`, http.StatusBadRequest, types.MCPParseError},
+		{"unsupported version", `{"jsonrpc":"1.0","id":1,"method":"ping"}`, http.StatusBadRequest, types.MCPInvalidRequest},
+		{"missing method", `{"jsonrpc":"2.0","id":1}`, http.StatusBadRequest, types.MCPInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := postJSONRPC(t, mcpService, tt.body)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", status, tt.wantStatus, body)
+			}
+			if status == http.StatusAccepted {
+				if len(body) != 0 {
+					t.Fatalf("notification body = %q, want empty", body)
+				}
+				return
+			}
+			response := decodeResponse(t, body)
+			code := 0
+			if response.Error != nil {
+				code = response.Error.Code
+			}
+			if code != tt.wantCode {
+				t.Fatalf("error code = %d, want %d", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestMCPHandlerBatch(t *testing.T) {
+	mcpService := mcp.NewServer(mcp.ServerConfig{BatchMaxSize: 3})
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantIDs    []interface{}
+		wantCodes  []int
+	}{
+		{
+			name:       "responses keep request order",
+			body:       `[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","id":"b","method":"tools/list"},{"jsonrpc":"2.0","id":3,"method":"unknown/method"}]`,
+			wantStatus: http.StatusOK,
+			wantIDs:    []interface{}{float64(1), "b", float64(3)},
+			wantCodes:  []int{0, 0, types.MCPMethodNotFound},
+		},
+		{
+			name:       "notifications are omitted",
+			body:       `[{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":2,"method":"ping"}]`,
+			wantStatus: http.StatusOK,
+			wantIDs:    []interface{}{float64(2)},
+			wantCodes:  []int{0},
+		},
+		{
+			name:       "only notifications",
+			body:       `[{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","method":"notifications/initialized"}]`,
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:       "invalid entry",
+			body:       `[1,{"jsonrpc":"2.0","id":2,"method":"ping"}]`,
+			wantStatus: http.StatusOK,
+			wantIDs:    []interface{}{nil, float64(2)},
+			wantCodes:  []int{types.MCPInvalidRequest, 0},
+		},
+		{
+			name:       "empty batch",
+			body:       `[]`,
+			wantStatus: http.StatusBadRequest,
+			wantIDs:    []interface{}{nil},
+			wantCodes:  []int{types.MCPInvalidRequest},
+		},
+		{
+			name:       "too many requests",
+			body:       `[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","id":2,"method":"ping"},{"jsonrpc":"2.0","id":3,"method":"ping"},{"jsonrpc":"2.0","id":4,"method":"ping"}]`,
+			wantStatus: http.StatusBadRequest,
+			wantIDs:    []interface{}{nil},
+			wantCodes:  []int{types.MCPInvalidRequest},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := postJSONRPC(t, mcpService, tt.body)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", status, tt.wantStatus, body)
+			}
+			if status == http.StatusAccepted {
+				if len(body) != 0 {
+					t.Fatalf("notification-only body = %q, want empty", body)
+				}
+				return
+			}
+
+			var responses []types.MCPResponse
+			if status == http.StatusOK {
+				if err := json.Unmarshal(body, &responses); err != nil {
+					t.Fatalf("decode batch %q: %v", body, err)
+				}
+			} else {
+				responses = append(responses, *decodeResponse(t, body))
+			}
+			if len(responses) != len(tt.wantIDs) {
+				t.Fatalf("got %d responses, want %d: %s", len(responses), len(tt.wantIDs), body)
+			}
+			for i, response := range responses {
+				if response.ID != tt.wantIDs[i] {
+					t.Fatalf("response %d id = %v, want %v", i, response.ID, tt.wantIDs[i])
+				}
+				code := 0
+				if response.Error != nil {
+					code = response.Error.Code
+				}
+				if code != tt.wantCodes[i] {
+					t.Fatalf("response %d error code = %d, want %d", i, code, tt.wantCodes[i])
+				}
+			}
+		})
+	}
+}
+
+// testJWTSecret 测试令牌的签名密钥
+const testJWTSecret = "test-secret"
+
+// newTestRouter 以内存仓库组装TALink MCP服务，MCP路由经过认证中间件
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+
+	cache := service.NewMemoryCacheService()
+	materialService := service.NewMaterialService(repository.NewMemoryMaterialRepository(), cache, nil)
+	server, err := service.NewMCPService(&service.MCPServiceConfig{
+		MaterialService: materialService,
+		EnforceRoles:    true,
+	})
+	if err != nil {
+		t.Fatalf("NewMCPService: %v", err)
+	}
+
+	r := gin.New()
+	r.POST("/mcp/jsonrpc", middleware.Authenticate(auth.NewService(testJWTSecret)), MCPHandler(server))
+	return r
+}
+
+// testToken 签发指定角色的测试令牌
+func testToken(t *testing.T, secret string, role types.UserRole) string {
+	t.Helper()
+
+	token, err := auth.NewService(secret).GenerateToken(uuid.New(), "test", string(role), time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	return token
+}
+
+// callTool 以给定请求头通过/mcp/jsonrpc调用工具，返回状态码与响应
+func callTool(t *testing.T, r *gin.Engine, header http.Header, name string, arguments interface{}) (int, *types.MCPResponse) {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  types.MCPMethodToolsCall,
+		"params":  map[string]interface{}{"name": name, "arguments": arguments},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/mcp/jsonrpc", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		return rec.Code, nil
+	}
+
+	var response types.MCPResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return rec.Code, &response
+}
+
+func TestToolPermissionByToken(t *testing.T) {
+	r := newTestRouter(t)
+	lessonPlan := map[string]interface{}{
+		"material_ids": []string{"m1"},
+		"objectives":   []string{"掌握一元二次方程"},
+		"grade":        "grade_8",
+	}
+	search := map[string]interface{}{"query": "方程"}
+	bearer := func(token string) http.Header {
+		return http.Header{"Authorization": []string{"Bearer " + token}}
+	}
+
+	tests := []struct {
+		name       string
+		header     http.Header
+		tool       string
+		arguments  interface{}
+		wantStatus int
+		wantDenied bool
+	}{
+		{"student cannot generate", bearer(testToken(t, testJWTSecret, types.UserRoleStudent)), "generate_lesson_plan", lessonPlan, http.StatusOK, true},
+		{"anonymous cannot generate", nil, "generate_lesson_plan", lessonPlan, http.StatusOK, true},
+		{"user id header is not trusted", http.Header{"X-User-Id": []string{uuid.NewString()}}, "generate_lesson_plan", lessonPlan, http.StatusOK, true},
+		{"teacher can generate", bearer(testToken(t, testJWTSecret, types.UserRoleTeacher)), "generate_lesson_plan", lessonPlan, http.StatusOK, false},
+		{"admin can generate", bearer(testToken(t, testJWTSecret, types.UserRoleAdmin)), "generate_lesson_plan", lessonPlan, http.StatusOK, false},
+		{"student can search", bearer(testToken(t, testJWTSecret, types.UserRoleStudent)), "search_teaching_materials", search, http.StatusOK, false},
+		{"anonymous can search", nil, "search_teaching_materials", search, http.StatusOK, false},
+		{"token signed with another secret", bearer(testToken(t, "other-secret", types.UserRoleTeacher)), "search_teaching_materials", search, http.StatusUnauthorized, false},
+		{"malformed token", bearer("not-a-jwt"), "search_teaching_materials", search, http.StatusUnauthorized, false},
+		{"unsupported scheme", http.Header{"Authorization": []string{"Basic dXNlcjpwYXNz"}}, "search_teaching_materials", search, http.StatusUnauthorized, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, response := callTool(t, r, tt.header, tt.tool, tt.arguments)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if response == nil {
+				return
+			}
+			denied := response.Error != nil && response.Error.Code == types.MCPPermissionDenied
+			if denied != tt.wantDenied {
+				t.Fatalf("denied = %v, want %v (error = %+v)", denied, tt.wantDenied, response.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+	"github.com/gin-gonic/gin"
+)
+
+// MCP Streamable HTTP 传输
+// 参考: https://modelcontextprotocol.io/specification/2025-03-26/basic/transports
+
+const (
+	// MCPSessionIDHeader 会话ID请求/响应头
+	MCPSessionIDHeader = "Mcp-Session-Id"
+	// MCPProtocolVersionHeader 初始化后客户端随请求携带的协商协议版本
+	MCPProtocolVersionHeader = "Mcp-Protocol-Version"
+	// lastEventIDHeader SSE断线续传请求头
+	lastEventIDHeader = "Last-Event-ID"
+	// sseKeepAliveInterval SSE保活间隔
+	sseKeepAliveInterval = 15 * time.Second
+)
+
+// MCPStreamableHandler MCP Streamable HTTP POST处理器
+// 客户端通过POST发送JSON-RPC消息，initialize请求会创建会话并在响应头中返回Mcp-Session-Id，
+// 后续消息必须携带该请求头。请求的响应根据Accept头以JSON或SSE事件返回。
+func MCPStreamableHandler(mcpService *mcp.Server) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var message types.MCPRawMessage
+		if err := json.NewDecoder(c.Request.Body).Decode(&message); err != nil {
+			logger.Warn("Failed to parse MCP message", logger.Any("error", err))
+			c.JSON(http.StatusBadRequest, types.MCPResponse{
+				MCPMessage: types.MCPMessage{
+					JSONRPC: "2.0",
+				},
+				Error: &types.MCPError{
+					Code:    types.MCPParseError,
+					Message: "Parse error",
+				},
+			})
+			return
+		}
+
+		if message.JSONRPC != "2.0" {
+			c.JSON(http.StatusBadRequest, types.MCPResponse{
+				MCPMessage: types.MCPMessage{
+					JSONRPC: "2.0",
+					ID:      message.ID,
+				},
+				Error: &types.MCPError{
+					Code:    types.MCPInvalidRequest,
+					Message: "Unsupported JSON-RPC version",
+				},
+			})
+			return
+		}
+
+		// initialize必须是带ID的请求，以通知形式发送时拒绝，避免创建无人持有的会话
+		request := message.Request()
+		if request.Method == types.MCPMethodInitialize && request.ID == nil {
+			c.JSON(http.StatusBadRequest, types.MCPResponse{
+				MCPMessage: types.MCPMessage{
+					JSONRPC: "2.0",
+				},
+				Error: &types.MCPError{
+					Code:    types.MCPInvalidRequest,
+					Message: "initialize must be sent as a request",
+				},
+			})
+			return
+		}
+
+		// 获取或创建会话
+		var session *mcp.Session
+		if request.Method == types.MCPMethodInitialize {
+			session = mcpService.Sessions().Create()
+			c.Header(MCPSessionIDHeader, session.ID)
+		} else {
+			var ok bool
+			if session, ok = lookupSession(c, mcpService); !ok {
+				return
+			}
+		}
+		session.Touch()
+
+		ctx := mcp.ContextWithSession(extractUserContext(c), session)
+
+		// 客户端对服务端请求的响应
+		if message.IsResponse() {
+			if !session.DeliverResponse(message.Response()) {
+				logger.Warn("Unmatched client response", logger.Any("id", message.ID))
+			}
+			c.Status(http.StatusAccepted)
+			return
+		}
+
+		// 通知不需要返回结果
+		if request.ID == nil {
+			if request.Method != "" {
+				if _, err := mcpService.HandleRequest(ctx, request); err != nil {
+					logger.Warn("Failed to handle MCP notification",
+						logger.Any("method", request.Method),
+						logger.Any("error", err))
+				}
+			}
+			c.Status(http.StatusAccepted)
+			return
+		}
+
+		// 工具调用可能长时间等待采样或征询，征询受mcp.elicitation_timeout、执行受工具超时约束，取消服务器写超时
+		clearWriteDeadline(c)
+
+		// 接受SSE的客户端在同一事件流中接收与该请求相关的通知（如进度）和最终响应
+		var stream *sseResponseStream
+		if acceptsEventStream(c) {
+			stream = &sseResponseStream{c: c}
+			ctx = mcp.ContextWithRequestNotifier(ctx, stream.send)
+		}
+
+		response, err := mcpService.HandleRequest(ctx, request)
+		if err != nil {
+			logger.Error("Failed to handle MCP request",
+				logger.Any("method", request.Method),
+				logger.Any("session_id", session.ID),
+				logger.Any("error", err))
+
+			response = &types.MCPResponse{
+				MCPMessage: types.MCPMessage{
+					JSONRPC: "2.0",
+					ID:      request.ID,
+				},
+				Error: &types.MCPError{
+					Code:    types.MCPInternalError,
+					Message: "Internal server error",
+				},
+			}
+		}
+
+		// 请求已被客户端取消，不返回响应
+		if response == nil {
+			if stream == nil || !stream.abort() {
+				c.Status(http.StatusAccepted)
+			}
+			return
+		}
+
+		// 初始化失败时不保留会话
+		if request.Method == types.MCPMethodInitialize && response.Error != nil {
+			mcpService.Sessions().Remove(session.ID)
+			c.Writer.Header().Del(MCPSessionIDHeader)
+		}
+
+		if stream == nil {
+			c.JSON(http.StatusOK, response)
+			return
+		}
+
+		if err := stream.finish(response); err != nil {
+			logger.Warn("Failed to write SSE response", logger.Any("error", err))
+		}
+	}
+}
+
+// sseResponseStream POST请求的SSE响应流，首次写入时发送响应头，写入最终响应后关闭
+type sseResponseStream struct {
+	c       *gin.Context
+	started bool
+	closed  bool
+	mu      sync.Mutex
+}
+
+// send 写入一条请求相关的通知，流已关闭时丢弃
+func (s *sseResponseStream) send(message interface{}) {
+	if err := s.write(message, false); err != nil {
+		logger.Warn("Failed to write SSE notification", logger.Any("error", err))
+	}
+}
+
+// finish 写入最终响应并关闭流
+func (s *sseResponseStream) finish(response interface{}) error {
+	return s.write(response, true)
+}
+
+// abort 不写入响应直接关闭流，返回流是否已经开始输出
+func (s *sseResponseStream) abort() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return s.started
+}
+
+func (s *sseResponseStream) write(message interface{}, last bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	if last {
+		s.closed = true
+	}
+
+	if !s.started {
+		setSSEHeaders(s.c)
+		s.c.Status(http.StatusOK)
+		s.started = true
+	}
+
+	if err := writeSSEEvent(s.c.Writer, 0, message); err != nil {
+		return err
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+// MCPSSEHandler MCP SSE处理器
+// 客户端通过GET打开服务端推送流，接收通知与服务端请求。
+// 每个事件携带单调递增的事件ID，断线重连时可通过Last-Event-ID请求头补发遗漏的事件。
+func MCPSSEHandler(mcpService *mcp.Server) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session, ok := lookupSession(c, mcpService)
+		if !ok {
+			return
+		}
+
+		var lastEventID int64
+		if header := c.GetHeader(lastEventIDHeader); header != "" {
+			if id, err := strconv.ParseInt(header, 10, 64); err == nil {
+				lastEventID = id
+			}
+		}
+
+		// SSE为长连接，取消服务器写超时
+		clearWriteDeadline(c)
+
+		setSSEHeaders(c)
+		c.Header(MCPSessionIDHeader, session.ID)
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+
+		// 补发断线期间的事件
+		for _, event := range session.EventsAfter(lastEventID) {
+			if err := writeSSEEvent(c.Writer, event.ID, event.Message); err != nil {
+				return
+			}
+			lastEventID = event.ID
+		}
+		c.Writer.Flush()
+
+		ticker := time.NewTicker(sseKeepAliveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case <-session.Done():
+				return
+			case event := <-session.Events():
+				if event.ID <= lastEventID {
+					continue
+				}
+				if err := writeSSEEvent(c.Writer, event.ID, event.Message); err != nil {
+					logger.Warn("Failed to write SSE event",
+						logger.Any("session_id", session.ID),
+						logger.Any("error", err))
+					return
+				}
+				lastEventID = event.ID
+				c.Writer.Flush()
+			case <-ticker.C:
+				if _, err := io.WriteString(c.Writer, ": keepalive\n\n"); err != nil {
+					return
+				}
+				c.Writer.Flush()
+				session.Touch()
+			}
+		}
+	}
+}
+
+// MCPSessionDeleteHandler 终止MCP会话
+func MCPSessionDeleteHandler(mcpService *mcp.Server) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session, ok := lookupSession(c, mcpService)
+		if !ok {
+			return
+		}
+
+		mcpService.Sessions().Remove(session.ID)
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// lookupSession 根据请求头查找会话并校验协议版本头，失败时写入错误响应
+func lookupSession(c *gin.Context, mcpService *mcp.Server) (*mcp.Session, bool) {
+	sessionID := c.GetHeader(MCPSessionIDHeader)
+	if sessionID == "" {
+		// 浏览器EventSource无法设置请求头，允许通过查询参数传递
+		sessionID = c.Query("session_id")
+	}
+
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing Mcp-Session-Id header",
+		})
+		return nil, false
+	}
+
+	session := mcpService.Sessions().Get(sessionID)
+	if session == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Session not found",
+		})
+		return nil, false
+	}
+
+	if version := c.GetHeader(MCPProtocolVersionHeader); version != "" && !types.IsSupportedProtocolVersion(version) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Unsupported Mcp-Protocol-Version: " + version,
+		})
+		return nil, false
+	}
+
+	return session, true
+}
+
+// acceptsEventStream 检查客户端是否接受SSE响应
+func acceptsEventStream(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), "text/event-stream")
+}
+
+// clearWriteDeadline 取消服务器写超时，用于长连接与可能长时间等待的响应
+func clearWriteDeadline(c *gin.Context) {
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		logger.Warn("Failed to clear write deadline", logger.Any("error", err))
+	}
+}
+
+// setSSEHeaders 设置SSE响应头
+func setSSEHeaders(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+}
+
+// writeSSEEvent 写入一个SSE message事件，eventID为0时不输出id字段
+func writeSSEEvent(w io.Writer, eventID int64, message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal SSE message: %w", err)
+	}
+
+	if eventID > 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", eventID); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+	return err
+}
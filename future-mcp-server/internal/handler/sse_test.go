@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+func TestMain(m *testing.M) {
+	viper.Set("log.level", "error")
+	if err := logger.Init(); err != nil {
+		panic(err)
+	}
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// newStreamableServer 启动挂载Streamable HTTP传输的测试服务器
+func newStreamableServer(t *testing.T) (*mcp.Server, *httptest.Server) {
+	t.Helper()
+
+	mcpService := mcp.NewServer(mcp.ServerConfig{})
+	r := gin.New()
+	r.POST("/mcp", MCPStreamableHandler(mcpService))
+	r.GET("/mcp", MCPSSEHandler(mcpService))
+	r.DELETE("/mcp", MCPSessionDeleteHandler(mcpService))
+
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+	return mcpService, ts
+}
+
+// postMCP 向/mcp发送一条JSON-RPC消息，sessionID为空时不携带会话头
+func postMCP(t *testing.T, ts *httptest.Server, sessionID, accept, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/mcp", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if sessionID != "" {
+		req.Header.Set(MCPSessionIDHeader, sessionID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST /mcp: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// initializeSession 发送initialize请求并返回新会话ID
+func initializeSession(t *testing.T, ts *httptest.Server) string {
+	t.Helper()
+
+	resp := postMCP(t, ts, "", "application/json", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1.0"}}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("initialize status = %d, want 200", resp.StatusCode)
+	}
+	sessionID := resp.Header.Get(MCPSessionIDHeader)
+	if sessionID == "" {
+		t.Fatal("initialize response has no Mcp-Session-Id header")
+	}
+	return sessionID
+}
+
+// decodeResponse 解析JSON-RPC响应
+func decodeResponse(t *testing.T, data []byte) *types.MCPResponse {
+	t.Helper()
+
+	var response types.MCPResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatalf("decode response %q: %v", data, err)
+	}
+	return &response
+}
+
+// sseEvent 解析出的SSE事件
+type sseEvent struct {
+	id   string
+	data string
+}
+
+// readSSEEvents 从事件流中读取count个事件
+func readSSEEvents(t *testing.T, scanner *bufio.Scanner, count int) []sseEvent {
+	t.Helper()
+
+	var (
+		events  []sseEvent
+		current sseEvent
+	)
+	for len(events) < count && scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			current.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			current.data = strings.TrimPrefix(line, "data: ")
+		case line == "" && current.data != "":
+			events = append(events, current)
+			current = sseEvent{}
+		}
+	}
+	if len(events) < count {
+		t.Fatalf("read %d SSE events, want %d: %v", len(events), count, scanner.Err())
+	}
+	return events
+}
+
+func TestStreamableSessionHeader(t *testing.T) {
+	mcpService, ts := newStreamableServer(t)
+	sessionID := initializeSession(t, ts)
+
+	if mcpService.Sessions().Get(sessionID) == nil {
+		t.Fatalf("session %s not registered", sessionID)
+	}
+
+	tests := []struct {
+		name       string
+		sessionID  string
+		wantStatus int
+	}{
+		{"missing session header", "", http.StatusBadRequest},
+		{"unknown session", "unknown", http.StatusNotFound},
+		{"known session", sessionID, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := postMCP(t, ts, tt.sessionID, "application/json", `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestStreamableNotificationAccepted(t *testing.T) {
+	_, ts := newStreamableServer(t)
+	sessionID := initializeSession(t, ts)
+
+	resp := postMCP(t, ts, sessionID, "application/json", `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202", resp.StatusCode)
+	}
+}
+
+func TestStreamableInitializeNotificationRejected(t *testing.T) {
+	mcpService, ts := newStreamableServer(t)
+
+	resp := postMCP(t, ts, "", "application/json", `{"jsonrpc":"2.0","method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1.0"}}}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.StatusCode)
+	}
+	if resp.Header.Get(MCPSessionIDHeader) != "" {
+		t.Fatal("initialize notification returned a session header")
+	}
+	if sessions := mcpService.Sessions().List(); len(sessions) != 0 {
+		t.Fatalf("initialize notification created %d sessions", len(sessions))
+	}
+}
+
+func TestStreamableEventStreamResponse(t *testing.T) {
+	_, ts := newStreamableServer(t)
+	sessionID := initializeSession(t, ts)
+
+	resp := postMCP(t, ts, sessionID, "application/json, text/event-stream", `{"jsonrpc":"2.0","id":"p1","method":"ping"}`)
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	events := readSSEEvents(t, bufio.NewScanner(resp.Body), 1)
+	response := decodeResponse(t, []byte(events[0].data))
+	if response.ID != "p1" || response.Error != nil {
+		t.Fatalf("response = %+v, want result for p1", response)
+	}
+}
+
+func TestSSEReplayAfterLastEventID(t *testing.T) {
+	mcpService, ts := newStreamableServer(t)
+	sessionID := initializeSession(t, ts)
+	session := mcpService.Sessions().Get(sessionID)
+
+	for i := 1; i <= 3; i++ {
+		session.Send(map[string]interface{}{"jsonrpc": "2.0", "method": "notifications/message", "params": map[string]int{"seq": i}})
+	}
+	// 清空待推送通道，只能通过历史补发取得事件
+	for i := 0; i < 3; i++ {
+		<-session.Events()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/mcp", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set(MCPSessionIDHeader, sessionID)
+	req.Header.Set(lastEventIDHeader, "1")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /mcp: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get(MCPSessionIDHeader) != sessionID {
+		t.Fatalf("stream session header = %q, want %q", resp.Header.Get(MCPSessionIDHeader), sessionID)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	events := readSSEEvents(t, scanner, 2)
+	if events[0].id != "2" || events[1].id != "3" {
+		t.Fatalf("replayed event ids = %s,%s, want 2,3", events[0].id, events[1].id)
+	}
+	if !strings.Contains(events[1].data, `"seq":3`) {
+		t.Fatalf("event data = %s", events[1].data)
+	}
+
+	// 重放之后新推送的事件继续实时送达
+	session.Send(map[string]interface{}{"jsonrpc": "2.0", "method": "notifications/message", "params": map[string]int{"seq": 4}})
+	if live := readSSEEvents(t, scanner, 1); live[0].id != "4" {
+		t.Fatalf("live event id = %s, want 4", live[0].id)
+	}
+}
+
+func TestSessionDelete(t *testing.T) {
+	mcpService, ts := newStreamableServer(t)
+	sessionID := initializeSession(t, ts)
+
+	req, err := http.NewRequest(http.MethodDelete, ts.URL+"/mcp", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set(MCPSessionIDHeader, sessionID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE /mcp: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("DELETE status = %d, want 204", resp.StatusCode)
+	}
+
+	if mcpService.Sessions().Get(sessionID) != nil {
+		t.Fatal("session still registered after DELETE")
+	}
+	if resp := postMCP(t, ts, sessionID, "application/json", `{"jsonrpc":"2.0","id":3,"method":"ping"}`); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("status after DELETE = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestStreamableProtocolVersionHeader(t *testing.T) {
+	_, ts := newStreamableServer(t)
+	sessionID := initializeSession(t, ts)
+
+	tests := []struct {
+		version    string
+		wantStatus int
+	}{
+		{"", http.StatusOK},
+		{types.MCPProtocolVersion20250326, http.StatusOK},
+		{"1999-01-01", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, ts.URL+"/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":2,"method":"ping"}`))
+			if err != nil {
+				t.Fatalf("new request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(MCPSessionIDHeader, sessionID)
+			if tt.version != "" {
+				req.Header.Set(MCPProtocolVersionHeader, tt.version)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("POST /mcp: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
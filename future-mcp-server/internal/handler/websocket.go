@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// wsWriteWait 单次写入超时
+	wsWriteWait = 10 * time.Second
+	// wsPongWait 等待客户端pong的超时，超时视为连接失活
+	wsPongWait = 60 * time.Second
+	// wsPingInterval ping发送间隔，必须小于wsPongWait
+	wsPingInterval = (wsPongWait * 9) / 10
+	// wsMaxMessageSize 单条消息最大字节数
+	wsMaxMessageSize = 4 * 1024 * 1024
+	// wsOutboundBufferSize 待发送响应缓冲区大小，写满后请求处理将阻塞等待
+	wsOutboundBufferSize = 64
+	// wsDefaultMaxInFlight 单个连接默认同时处理的请求数
+	wsDefaultMaxInFlight = 32
+)
+
+// WebSocketConfig WebSocket传输配置
+type WebSocketConfig struct {
+	// AllowedOrigins 允许建立连接的浏览器来源（如https://app.example.com），"*"允许任意来源；
+	// 为空时只允许同源页面。未携带Origin请求头的非浏览器客户端不受限制
+	AllowedOrigins []string
+	// MaxInFlight 单个连接同时处理的请求数上限，超出时直接返回繁忙错误，为0时使用默认值
+	MaxInFlight int
+}
+
+// MCPWebSocketHandler WebSocket处理器
+// 每个连接对应一个MCP会话，在同一连接上复用请求、响应、服务端通知与取消通知。
+func MCPWebSocketHandler(mcpService *mcp.Server, config WebSocketConfig) gin.HandlerFunc {
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  4096,
+		WriteBufferSize: 4096,
+		Subprotocols:    []string{"mcp"},
+		CheckOrigin:     originChecker(config.AllowedOrigins),
+	}
+	maxInFlight := config.MaxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = wsDefaultMaxInFlight
+	}
+
+	return func(c *gin.Context) {
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			logger.Warn("Failed to upgrade WebSocket connection", logger.Any("error", err))
+			return
+		}
+
+		// WebSocket没有断线补发机制，事件积压时断开慢速客户端而不是丢弃通知
+		session := mcpService.Sessions().Create()
+		session.Pin()
+		session.DisableReplay()
+
+		ctx, cancel := context.WithCancel(mcp.ContextWithSession(extractUserContext(c), session))
+		wsConn := &mcpWebSocketConn{
+			conn:       conn,
+			mcpService: mcpService,
+			session:    session,
+			outbound:   make(chan interface{}, wsOutboundBufferSize),
+			slots:      make(chan struct{}, maxInFlight),
+		}
+
+		logger.Info("WebSocket session opened", logger.Any("session_id", session.ID))
+
+		go wsConn.writeLoop(ctx, cancel)
+		wsConn.readLoop(ctx)
+
+		cancel()
+		wsConn.inflight.Wait()
+		session.Close()
+		logger.Info("WebSocket session closed", logger.Any("session_id", session.ID))
+	}
+}
+
+// mcpWebSocketConn 单个WebSocket连接
+type mcpWebSocketConn struct {
+	conn       *websocket.Conn
+	mcpService *mcp.Server
+	session    *mcp.Session
+	outbound   chan interface{}
+	inflight   sync.WaitGroup
+	// slots 处理中请求的信号量
+	slots chan struct{}
+}
+
+// readLoop 读取客户端消息，请求在信号量限制内并发处理，通知按序处理，响应交付给等待中的服务端请求
+func (wc *mcpWebSocketConn) readLoop(ctx context.Context) {
+	wc.conn.SetReadLimit(wsMaxMessageSize)
+	_ = wc.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	wc.conn.SetPongHandler(func(string) error {
+		wc.session.Touch()
+		return wc.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		var message types.MCPRawMessage
+		if err := wc.conn.ReadJSON(&message); err != nil {
+			if !isNormalClose(err) {
+				logger.Warn("WebSocket read failed",
+					logger.Any("session_id", wc.session.ID),
+					logger.Any("error", err))
+			}
+			return
+		}
+
+		wc.session.Touch()
+
+		if message.JSONRPC != "2.0" {
+			wc.enqueue(ctx, &types.MCPResponse{
+				MCPMessage: types.MCPMessage{
+					JSONRPC: "2.0",
+					ID:      message.ID,
+				},
+				Error: &types.MCPError{
+					Code:    types.MCPInvalidRequest,
+					Message: "Unsupported JSON-RPC version",
+				},
+			})
+			continue
+		}
+
+		if message.IsResponse() {
+			if !wc.session.DeliverResponse(message.Response()) {
+				logger.Warn("Unmatched client response", logger.Any("id", message.ID))
+			}
+			continue
+		}
+
+		request := message.Request()
+		if request.ID == nil {
+			wc.handleRequest(ctx, request)
+			continue
+		}
+
+		// 不阻塞读取循环等待名额：处理中的请求可能正等待客户端响应，读取停止会导致死锁
+		select {
+		case wc.slots <- struct{}{}:
+		default:
+			wc.enqueue(ctx, &types.MCPResponse{
+				MCPMessage: types.MCPMessage{
+					JSONRPC: "2.0",
+					ID:      request.ID,
+				},
+				Error: &types.MCPError{
+					Code:    types.MCPServerBusy,
+					Message: "Too many in-flight requests",
+				},
+			})
+			continue
+		}
+
+		wc.inflight.Add(1)
+		go func() {
+			defer func() {
+				<-wc.slots
+				wc.inflight.Done()
+			}()
+			wc.handleRequest(ctx, request)
+		}()
+	}
+}
+
+// handleRequest 处理单个请求或通知
+func (wc *mcpWebSocketConn) handleRequest(ctx context.Context, request *types.MCPRequest) {
+	// 与请求相关的通知（如进度）经发送队列按序写出，保证先于该请求的响应到达
+	requestCtx := mcp.ContextWithRequestNotifier(ctx, func(message interface{}) {
+		wc.enqueue(ctx, message)
+	})
+
+	response, err := wc.mcpService.HandleRequest(requestCtx, request)
+	if err != nil {
+		logger.Error("Failed to handle MCP request",
+			logger.Any("method", request.Method),
+			logger.Any("session_id", wc.session.ID),
+			logger.Any("error", err))
+
+		response = &types.MCPResponse{
+			MCPMessage: types.MCPMessage{
+				JSONRPC: "2.0",
+				ID:      request.ID,
+			},
+			Error: &types.MCPError{
+				Code:    types.MCPInternalError,
+				Message: "Internal server error",
+			},
+		}
+	}
+
+	// 通知不返回响应
+	if request.ID == nil || response == nil {
+		return
+	}
+
+	wc.enqueue(ctx, response)
+}
+
+// enqueue 将响应放入发送队列，队列已满时阻塞以形成背压
+func (wc *mcpWebSocketConn) enqueue(ctx context.Context, message interface{}) {
+	select {
+	case wc.outbound <- message:
+	case <-ctx.Done():
+	}
+}
+
+// writeLoop 唯一的写goroutine：发送响应、会话通知和ping
+func (wc *mcpWebSocketConn) writeLoop(ctx context.Context, cancel context.CancelFunc) {
+	ticker := time.NewTicker(wsPingInterval)
+	defer func() {
+		ticker.Stop()
+		cancel()
+		wc.conn.Close()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = wc.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(wsWriteWait))
+			return
+		case <-wc.session.Done():
+			// 事件缓冲区溢出时告知客户端稍后重连
+			if errors.Is(wc.session.Err(), mcp.ErrSessionOverflow) {
+				_ = wc.conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, mcp.ErrSessionOverflow.Error()),
+					time.Now().Add(wsWriteWait))
+			}
+			return
+		case message := <-wc.outbound:
+			if err := wc.writeJSON(message); err != nil {
+				return
+			}
+		case event := <-wc.session.Events():
+			if err := wc.writeJSON(event.Message); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = wc.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := wc.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				logger.Warn("WebSocket ping failed",
+					logger.Any("session_id", wc.session.ID),
+					logger.Any("error", err))
+				return
+			}
+		}
+	}
+}
+
+// writeJSON 写入一条JSON消息
+func (wc *mcpWebSocketConn) writeJSON(message interface{}) error {
+	_ = wc.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	if err := wc.conn.WriteJSON(message); err != nil {
+		logger.Warn("WebSocket write failed",
+			logger.Any("session_id", wc.session.ID),
+			logger.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+// originChecker 按允许来源列表校验浏览器发起的握手
+func originChecker(allowed []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		if len(allowed) == 0 {
+			u, err := url.Parse(origin)
+			return err == nil && strings.EqualFold(u.Host, r.Host)
+		}
+		for _, candidate := range allowed {
+			if candidate == "*" || strings.EqualFold(strings.TrimSuffix(candidate, "/"), origin) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// isNormalClose 检查是否是正常关闭
+func isNormalClose(err error) bool {
+	return websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived)
+}
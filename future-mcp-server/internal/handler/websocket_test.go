@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// serveWebSocket 启动挂载WebSocket传输的测试服务器，返回HTTP地址与ws地址
+func serveWebSocket(t *testing.T, mcpService *mcp.Server, config WebSocketConfig) (string, string) {
+	t.Helper()
+
+	r := gin.New()
+	r.GET("/mcp/ws", MCPWebSocketHandler(mcpService, config))
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+	return ts.URL, "ws" + strings.TrimPrefix(ts.URL, "http") + "/mcp/ws"
+}
+
+// dialWebSocket 启动挂载WebSocket传输的测试服务器并建立连接
+func dialWebSocket(t *testing.T) (*mcp.Server, *websocket.Conn) {
+	t.Helper()
+
+	mcpService := mcp.NewServer(mcp.ServerConfig{})
+	_, wsURL := serveWebSocket(t, mcpService, WebSocketConfig{})
+	return mcpService, dial(t, wsURL)
+}
+
+// dial 建立WebSocket连接并设置读超时
+func dial(t *testing.T, wsURL string) *websocket.Conn {
+	t.Helper()
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+// readWebSocketMessage 读取一条消息
+func readWebSocketMessage(t *testing.T, conn *websocket.Conn) *types.MCPRawMessage {
+	t.Helper()
+
+	var message types.MCPRawMessage
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return &message
+}
+
+func TestWebSocketRequests(t *testing.T) {
+	_, conn := dialWebSocket(t)
+
+	tests := []struct {
+		name     string
+		message  string
+		wantID   interface{}
+		wantCode int
+	}{
+		{"initialize", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1.0"}}}`, float64(1), 0},
+		{"ping", `{"jsonrpc":"2.0","id":"p","method":"ping"}`, "p", 0},
+		{"unsupported version", `{"jsonrpc":"1.0","id":3,"method":"ping"}`, float64(3), types.MCPInvalidRequest},
+		{"unknown method", `{"jsonrpc":"2.0","id":4,"method":"unknown/method"}`, float64(4), types.MCPMethodNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(tt.message)); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			response := readWebSocketMessage(t, conn).Response()
+			if response.ID != tt.wantID {
+				t.Fatalf("response id = %v, want %v", response.ID, tt.wantID)
+			}
+			code := 0
+			if response.Error != nil {
+				code = response.Error.Code
+			}
+			if code != tt.wantCode {
+				t.Fatalf("error code = %d, want %d", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestWebSocketServerRequest(t *testing.T) {
+	mcpService, conn := dialWebSocket(t)
+
+	// 连接建立后会话才注册，先用一次ping确认连接就绪
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	readWebSocketMessage(t, conn)
+
+	sessions := mcpService.Sessions().List()
+	if len(sessions) != 1 {
+		t.Fatalf("got %d sessions, want 1", len(sessions))
+	}
+
+	type result struct {
+		response *types.MCPResponse
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		response, err := sessions[0].Request(ctx, "roots/list", nil)
+		done <- result{response, err}
+	}()
+
+	request := readWebSocketMessage(t, conn)
+	if request.Method != "roots/list" || request.ID == nil {
+		t.Fatalf("server request = %+v, want roots/list with id", request)
+	}
+	reply := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": map[string]interface{}{"roots": []interface{}{}}}
+	if err := conn.WriteJSON(reply); err != nil {
+		t.Fatalf("write reply: %v", err)
+	}
+
+	got := <-done
+	if got.err != nil {
+		t.Fatalf("Request: %v", got.err)
+	}
+	if got.response.ID != request.ID || got.response.Error != nil {
+		t.Fatalf("response = %+v", got.response)
+	}
+}
+
+func TestWebSocketOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string // same表示与服务同源
+		wantOK  bool
+	}{
+		{"non-browser client", nil, "", true},
+		{"same origin by default", nil, "same", true},
+		{"foreign origin rejected by default", nil, "https://evil.example.com", false},
+		{"allowlisted origin", []string{"https://app.example.com/"}, "https://app.example.com", true},
+		{"origin not in allowlist", []string{"https://app.example.com"}, "https://evil.example.com", false},
+		{"wildcard", []string{"*"}, "https://evil.example.com", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpURL, wsURL := serveWebSocket(t, mcp.NewServer(mcp.ServerConfig{}), WebSocketConfig{AllowedOrigins: tt.allowed})
+
+			header := http.Header{}
+			switch tt.origin {
+			case "":
+			case "same":
+				header.Set("Origin", httpURL)
+			default:
+				header.Set("Origin", tt.origin)
+			}
+
+			conn, resp, err := websocket.DefaultDialer.Dial(wsURL, header)
+			if conn != nil {
+				conn.Close()
+			}
+			if ok := err == nil; ok != tt.wantOK {
+				t.Fatalf("dial err = %v, want ok = %v", err, tt.wantOK)
+			}
+			if !tt.wantOK && (resp == nil || resp.StatusCode != http.StatusForbidden) {
+				t.Fatalf("rejected handshake response = %+v, want 403", resp)
+			}
+		})
+	}
+}
+
+func TestWebSocketInFlightLimit(t *testing.T) {
+	mcpService := mcp.NewServer(mcp.ServerConfig{})
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	mcpService.Tools().RegisterTool(&types.ToolDefinition{
+		Name:        "slow",
+		InputSchema: map[string]interface{}{"type": "object"},
+		Handler: func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+			started <- struct{}{}
+			<-release
+			return &types.ToolsCallResponse{Content: []types.Content{mcp.TextContent("done")}}, nil
+		},
+	})
+	_, wsURL := serveWebSocket(t, mcpService, WebSocketConfig{MaxInFlight: 1})
+	conn := dial(t, wsURL)
+
+	write := func(message string) {
+		t.Helper()
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	write(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1.0"}}}`)
+	readWebSocketMessage(t, conn)
+	write(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	write(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"slow"}}`)
+	<-started
+
+	// 名额已满，后续请求立即以繁忙错误返回，读取循环不被阻塞
+	write(`{"jsonrpc":"2.0","id":3,"method":"ping"}`)
+	busy := readWebSocketMessage(t, conn).Response()
+	if busy.ID != float64(3) || busy.Error == nil || busy.Error.Code != types.MCPServerBusy {
+		t.Fatalf("response = %+v, want busy error for id 3", busy)
+	}
+
+	close(release)
+	if response := readWebSocketMessage(t, conn).Response(); response.ID != float64(2) || response.Error != nil {
+		t.Fatalf("tools/call response = %+v", response)
+	}
+
+	// 名额释放后恢复处理
+	write(`{"jsonrpc":"2.0","id":4,"method":"ping"}`)
+	if response := readWebSocketMessage(t, conn).Response(); response.ID != float64(4) || response.Error != nil {
+		t.Fatalf("ping response = %+v", response)
+	}
+}
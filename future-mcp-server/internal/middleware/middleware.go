@@ -3,9 +3,13 @@ package middleware
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/future-mcp/future-mcp-server/internal/auth"
+	"github.com/future-mcp/future-mcp-server/internal/types"
 	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
 	"github.com/gin-gonic/gin"
 )
 
@@ -29,7 +33,8 @@ func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID, Mcp-Session-Id, Mcp-Protocol-Version, Last-Event-ID")
+		c.Header("Access-Control-Expose-Headers", "Mcp-Session-Id")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -40,6 +45,37 @@ func CORS() gin.HandlerFunc {
 	}
 }
 
+// Authenticate 认证中间件，校验Authorization: Bearer JWT并将用户ID与角色写入请求上下文
+// 未携带令牌的请求按匿名用户继续处理，令牌无效时返回401
+func Authenticate(authService *auth.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unsupported authorization scheme"})
+			return
+		}
+		claims, err := authService.ValidateToken(strings.TrimSpace(token))
+		if err != nil {
+			logger.Warn("Invalid access token", logger.Any("error", err))
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			return
+		}
+
+		c.Set("user_id", claims.UserID)
+		ctx := mcp.ContextWithUserID(c.Request.Context(), claims.UserID)
+		ctx = auth.ContextWithRole(ctx, types.UserRole(claims.Role))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
 // Logger 日志中间件
 func Logger() gin.HandlerFunc {
 	return func(c *gin.Context) {
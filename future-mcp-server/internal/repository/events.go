@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"sync"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// MaterialChangeType 素材变更类型
+type MaterialChangeType string
+
+const (
+	MaterialCreated MaterialChangeType = "created"
+	MaterialUpdated MaterialChangeType = "updated"
+	MaterialDeleted MaterialChangeType = "deleted"
+)
+
+// MaterialChangeEvent 素材变更事件
+type MaterialChangeEvent struct {
+	Type        MaterialChangeType
+	MaterialIDs []uuid.UUID
+}
+
+// MaterialChangeListener 素材变更监听器
+type MaterialChangeListener func(event MaterialChangeEvent)
+
+// ObservableMaterialRepository 包装素材仓库，写操作成功后向监听器发布素材变更事件
+type ObservableMaterialRepository struct {
+	MaterialRepository
+
+	listeners []MaterialChangeListener
+	mu        sync.RWMutex
+}
+
+// NewObservableMaterialRepository 创建发布变更事件的素材仓库
+func NewObservableMaterialRepository(repo MaterialRepository) *ObservableMaterialRepository {
+	return &ObservableMaterialRepository{MaterialRepository: repo}
+}
+
+// OnChange 注册素材变更监听器，监听器在写操作所在的goroutine中同步调用
+func (r *ObservableMaterialRepository) OnChange(listener MaterialChangeListener) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, listener)
+}
+
+// CreateMaterial 创建素材
+func (r *ObservableMaterialRepository) CreateMaterial(material *types.TeachingMaterial) error {
+	if err := r.MaterialRepository.CreateMaterial(material); err != nil {
+		return err
+	}
+	r.publish(MaterialCreated, material.ID)
+	return nil
+}
+
+// UpdateMaterial 更新素材
+func (r *ObservableMaterialRepository) UpdateMaterial(material *types.TeachingMaterial) error {
+	if err := r.MaterialRepository.UpdateMaterial(material); err != nil {
+		return err
+	}
+	r.publish(MaterialUpdated, material.ID)
+	return nil
+}
+
+// DeleteMaterial 删除素材
+func (r *ObservableMaterialRepository) DeleteMaterial(id uuid.UUID) error {
+	if err := r.MaterialRepository.DeleteMaterial(id); err != nil {
+		return err
+	}
+	r.publish(MaterialDeleted, id)
+	return nil
+}
+
+// BatchCreateMaterials 批量创建素材
+func (r *ObservableMaterialRepository) BatchCreateMaterials(materials []*types.TeachingMaterial) error {
+	if err := r.MaterialRepository.BatchCreateMaterials(materials); err != nil {
+		return err
+	}
+	r.publish(MaterialCreated, materialIDs(materials)...)
+	return nil
+}
+
+// BatchUpdateMaterials 批量更新素材
+// 批量更新中途失败时部分素材可能已写入，仍按全部素材发布事件
+func (r *ObservableMaterialRepository) BatchUpdateMaterials(materials []*types.TeachingMaterial) error {
+	err := r.MaterialRepository.BatchUpdateMaterials(materials)
+	r.publish(MaterialUpdated, materialIDs(materials)...)
+	return err
+}
+
+// publish 向监听器发布素材变更事件
+func (r *ObservableMaterialRepository) publish(changeType MaterialChangeType, ids ...uuid.UUID) {
+	r.mu.RLock()
+	listeners := r.listeners
+	r.mu.RUnlock()
+
+	event := MaterialChangeEvent{Type: changeType, MaterialIDs: ids}
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// materialIDs 素材ID列表
+func materialIDs(materials []*types.TeachingMaterial) []uuid.UUID {
+	ids := make([]uuid.UUID, len(materials))
+	for i, material := range materials {
+		ids[i] = material.ID
+	}
+	return ids
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -239,9 +240,22 @@ func (r *MemoryMaterialRepository) SearchMaterials(req types.SearchMaterialsRequ
 			continue
 		}
 
+		// 私有素材只对授权用户可见，在分页前过滤
+		if !visibleTo(material, req.ViewerID) {
+			continue
+		}
+
 		results = append(results, *material)
 	}
 
+	// 按创建时间倒序、ID升序排列，保证分页结果稳定
+	sort.Slice(results, func(i, j int) bool {
+		if !results[i].CreatedAt.Equal(results[j].CreatedAt) {
+			return results[i].CreatedAt.After(results[j].CreatedAt)
+		}
+		return results[i].ID.String() < results[j].ID.String()
+	})
+
 	// 分页
 	total := int64(len(results))
 	start := (req.Pagination.Page - 1) * req.Pagination.PageSize
@@ -410,3 +424,16 @@ func containsIgnoreCase(s, substr string) bool {
 	substr = strings.ToLower(substr)
 	return strings.Contains(s, substr)
 }
+
+// visibleTo 素材是否对用户可见：私有素材只对授权用户可见
+func visibleTo(material *types.TeachingMaterial, userID uuid.UUID) bool {
+	if material.Permissions.AccessLevel != "private" {
+		return true
+	}
+	for _, id := range material.Permissions.AllowedUsers {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PostgresMaterialRepository PostgreSQL素材仓库实现
+type PostgresMaterialRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresMaterialRepository 创建PostgreSQL素材仓库
+func NewPostgresMaterialRepository(db *gorm.DB) MaterialRepository {
+	return &PostgresMaterialRepository{db: db}
+}
+
+// CreateMaterial 创建素材
+func (r *PostgresMaterialRepository) CreateMaterial(material *types.TeachingMaterial) error {
+	if err := r.db.Create(material).Error; err != nil {
+		return fmt.Errorf("failed to create material: %w", err)
+	}
+	return nil
+}
+
+// GetMaterialByID 根据ID获取素材
+func (r *PostgresMaterialRepository) GetMaterialByID(id uuid.UUID) (*types.TeachingMaterial, error) {
+	var material types.TeachingMaterial
+	if err := r.db.First(&material, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("material not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get material: %w", err)
+	}
+	return &material, nil
+}
+
+// UpdateMaterial 更新素材
+func (r *PostgresMaterialRepository) UpdateMaterial(material *types.TeachingMaterial) error {
+	result := r.db.Model(material).Where("id = ?", material.ID).Updates(material)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update material: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("material not found: %s", material.ID)
+	}
+	return nil
+}
+
+// DeleteMaterial 删除素材
+func (r *PostgresMaterialRepository) DeleteMaterial(id uuid.UUID) error {
+	result := r.db.Delete(&types.TeachingMaterial{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete material: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("material not found: %s", id)
+	}
+	return nil
+}
+
+// SearchMaterials 搜索素材
+func (r *PostgresMaterialRepository) SearchMaterials(req types.SearchMaterialsRequest) ([]types.TeachingMaterial, int64, error) {
+	query := r.db.Model(&types.TeachingMaterial{})
+
+	// 关键词匹配
+	if req.Query != "" {
+		pattern := "%" + req.Query + "%"
+		query = query.Where("title ILIKE ? OR description ILIKE ?", pattern, pattern)
+	}
+
+	// 年级筛选
+	if len(req.Grade) > 0 {
+		// 以数组字面量绑定为单个参数，普通切片会被展开为 (?,?) 列表
+		query = query.Where("grade_levels && ?", types.TextArray[types.GradeLevel](req.Grade))
+	}
+
+	// 学科、类型、难度筛选
+	if req.Subject != "" {
+		query = query.Where("subject = ?", req.Subject)
+	}
+	if req.Type != "" {
+		query = query.Where("type = ?", req.Type)
+	}
+	if req.Difficulty != "" {
+		query = query.Where("difficulty = ?", req.Difficulty)
+	}
+
+	// 私有素材只对授权用户可见，在计数与分页前过滤
+	query = query.Where("(access_level IS DISTINCT FROM 'private' OR ? = ANY(allowed_users))", req.ViewerID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count materials: %w", err)
+	}
+
+	// 排序
+	if req.Sort.Field != "" {
+		order := "desc"
+		if req.Sort.Order == "asc" {
+			order = "asc"
+		}
+		query = query.Order(fmt.Sprintf("%s %s", sortColumn(req.Sort.Field), order))
+	} else {
+		query = query.Order("created_at desc")
+	}
+	// 以ID作为次级排序，保证分页结果稳定
+	query = query.Order("id")
+
+	// 分页
+	if req.Pagination.PageSize > 0 {
+		page := req.Pagination.Page
+		if page < 1 {
+			page = 1
+		}
+		query = query.Offset((page - 1) * req.Pagination.PageSize).Limit(req.Pagination.PageSize)
+	}
+
+	var materials []types.TeachingMaterial
+	if err := query.Find(&materials).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to search materials: %w", err)
+	}
+
+	return materials, total, nil
+}
+
+// GetRelatedMaterials 获取相关素材
+func (r *PostgresMaterialRepository) GetRelatedMaterials(materialID uuid.UUID, relationType string, limit int) ([]types.TeachingMaterial, error) {
+	material, err := r.GetMaterialByID(materialID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 简单相关性逻辑：相同学科和年级的素材
+	var related []types.TeachingMaterial
+	err = r.db.Where("id <> ? AND subject = ? AND grade_levels && ?", materialID, material.Subject, material.GradeLevels).
+		Limit(limit).
+		Find(&related).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get related materials: %w", err)
+	}
+
+	return related, nil
+}
+
+// GetPopularMaterials 获取热门素材
+func (r *PostgresMaterialRepository) GetPopularMaterials(limit int) ([]types.TeachingMaterial, error) {
+	var materials []types.TeachingMaterial
+	if err := r.db.Order("view_count desc").Limit(limit).Find(&materials).Error; err != nil {
+		return nil, fmt.Errorf("failed to get popular materials: %w", err)
+	}
+	return materials, nil
+}
+
+// GetMaterialsByGrade 按年级获取素材
+func (r *PostgresMaterialRepository) GetMaterialsByGrade(grade types.GradeLevel, limit int) ([]types.TeachingMaterial, error) {
+	var materials []types.TeachingMaterial
+	if err := r.db.Where("? = ANY(grade_levels)", string(grade)).Limit(limit).Find(&materials).Error; err != nil {
+		return nil, fmt.Errorf("failed to get materials by grade: %w", err)
+	}
+	return materials, nil
+}
+
+// GetMaterialsBySubject 按学科获取素材
+func (r *PostgresMaterialRepository) GetMaterialsBySubject(subject types.Subject, limit int) ([]types.TeachingMaterial, error) {
+	var materials []types.TeachingMaterial
+	if err := r.db.Where("subject = ?", subject).Limit(limit).Find(&materials).Error; err != nil {
+		return nil, fmt.Errorf("failed to get materials by subject: %w", err)
+	}
+	return materials, nil
+}
+
+// BatchCreateMaterials 批量创建素材
+func (r *PostgresMaterialRepository) BatchCreateMaterials(materials []*types.TeachingMaterial) error {
+	if len(materials) == 0 {
+		return nil
+	}
+	if err := r.db.Create(materials).Error; err != nil {
+		return fmt.Errorf("failed to batch create materials: %w", err)
+	}
+	return nil
+}
+
+// BatchUpdateMaterials 批量更新素材
+func (r *PostgresMaterialRepository) BatchUpdateMaterials(materials []*types.TeachingMaterial) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		repo := &PostgresMaterialRepository{db: tx}
+		for _, material := range materials {
+			if err := repo.UpdateMaterial(material); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// sortColumn 将排序字段映射为数据库列，未知字段按创建时间排序
+func sortColumn(field string) string {
+	switch field {
+	case "view_count", "download_count", "average_rating", "created_at", "updated_at", "title":
+		return field
+	default:
+		return "created_at"
+	}
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordedQuery 驱动收到的查询及经driver.Valuer转换后的参数
+type recordedQuery struct {
+	sql  string
+	args []driver.Value
+}
+
+// recordingDriver 记录查询并以PostgreSQL文本格式返回预置行的database/sql驱动
+type recordingDriver struct {
+	mu      sync.Mutex
+	queries []recordedQuery
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (d *recordingDriver) Open(name string) (driver.Conn, error) {
+	return &recordingConn{driver: d}, nil
+}
+
+// Connect 实现driver.Connector，经sql.OpenDB使用，无需全局注册驱动（sql.Register同名注册会panic）
+func (d *recordingDriver) Connect(ctx context.Context) (driver.Conn, error) {
+	return d.Open("")
+}
+
+func (d *recordingDriver) Driver() driver.Driver { return d }
+
+func (d *recordingDriver) record(query string, args []driver.NamedValue) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	d.queries = append(d.queries, recordedQuery{sql: query, args: values})
+}
+
+type recordingConn struct {
+	driver *recordingDriver
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) { return c, nil }
+
+func (c *recordingConn) Commit() error { return nil }
+
+func (c *recordingConn) Rollback() error { return nil }
+
+func (c *recordingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.driver.record(query, args)
+	if strings.HasPrefix(query, "SELECT count(*)") {
+		return &recordingRows{columns: []string{"count"}, rows: [][]driver.Value{{int64(len(c.driver.rows))}}}, nil
+	}
+	return &recordingRows{columns: c.driver.columns, rows: c.driver.rows}, nil
+}
+
+type recordingRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *recordingRows) Columns() []string { return r.columns }
+
+func (r *recordingRows) Close() error { return nil }
+
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// newRecordingRepository 创建基于记录驱动的PostgreSQL素材仓库
+func newRecordingRepository(t *testing.T, columns []string, rows ...[]driver.Value) (MaterialRepository, *recordingDriver) {
+	t.Helper()
+
+	recorder := &recordingDriver{columns: columns, rows: rows}
+	sqlDB := sql.OpenDB(recorder)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger:                 logger.Discard,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("open gorm: %v", err)
+	}
+	return NewPostgresMaterialRepository(db), recorder
+}
+
+func TestPostgresSearchMaterialsBindsGradeArray(t *testing.T) {
+	id := uuid.New()
+	userID := uuid.New()
+	repo, recorder := newRecordingRepository(t,
+		[]string{"id", "title", "grade_levels", "tags", "objectives", "allowed_usage", "allowed_users", "embeddings"},
+		[]driver.Value{id.String(), "一元二次方程", []byte("{grade_7,grade_8}"), `{"方程","a \"b\""}`, "{}", "{view}", "{" + userID.String() + "}", nil},
+	)
+
+	materials, total, err := repo.SearchMaterials(types.SearchMaterialsRequest{
+		Grade:    []types.GradeLevel{types.GradeLevel7, types.GradeLevel8},
+		ViewerID: userID,
+	})
+	if err != nil {
+		t.Fatalf("SearchMaterials: %v", err)
+	}
+	if total != 1 || len(materials) != 1 {
+		t.Fatalf("SearchMaterials returned %d materials, total %d", len(materials), total)
+	}
+
+	// 年级数组作为单个参数绑定为数组字面量
+	for _, query := range recorder.queries {
+		if !strings.Contains(query.sql, "grade_levels && $1") {
+			t.Fatalf("query %q does not bind grade_levels as a single array parameter", query.sql)
+		}
+		if want := []driver.Value{`{"grade_7","grade_8"}`}; !reflect.DeepEqual(query.args[:1], want) {
+			t.Fatalf("args = %#v, want %#v", query.args, want)
+		}
+		// 私有素材在计数与分页前按检索用户过滤
+		if !strings.Contains(query.sql, "access_level IS DISTINCT FROM 'private' OR $2 = ANY(allowed_users)") || query.args[1] != userID.String() {
+			t.Fatalf("query %q args %#v do not filter private materials by viewer", query.sql, query.args)
+		}
+	}
+
+	material := materials[0]
+	if material.ID != id {
+		t.Fatalf("ID = %v, want %v", material.ID, id)
+	}
+	if want := (types.TextArray[types.GradeLevel]{types.GradeLevel7, types.GradeLevel8}); !reflect.DeepEqual(material.GradeLevels, want) {
+		t.Fatalf("GradeLevels = %#v, want %#v", material.GradeLevels, want)
+	}
+	if want := (types.TextArray[string]{"方程", `a "b"`}); !reflect.DeepEqual(material.Tags, want) {
+		t.Fatalf("Tags = %#v, want %#v", material.Tags, want)
+	}
+	if len(material.CurriculumAlignment.Objectives) != 0 {
+		t.Fatalf("Objectives = %#v, want empty", material.CurriculumAlignment.Objectives)
+	}
+	if want := (types.TextArray[types.UsageType]{types.UsageTypeView}); !reflect.DeepEqual(material.Permissions.AllowedUsage, want) {
+		t.Fatalf("AllowedUsage = %#v, want %#v", material.Permissions.AllowedUsage, want)
+	}
+	if want := (types.UUIDArray{userID}); !reflect.DeepEqual(material.Permissions.AllowedUsers, want) {
+		t.Fatalf("AllowedUsers = %#v, want %#v", material.Permissions.AllowedUsers, want)
+	}
+	if material.Embeddings != nil {
+		t.Fatalf("Embeddings = %#v, want nil", material.Embeddings)
+	}
+}
+
+func TestPostgresGetRelatedMaterialsBindsGradeArray(t *testing.T) {
+	id := uuid.New()
+	repo, recorder := newRecordingRepository(t,
+		[]string{"id", "subject", "grade_levels"},
+		[]driver.Value{id.String(), "math", "{grade_9}"},
+	)
+
+	if _, err := repo.GetRelatedMaterials(id, "similar", 5); err != nil {
+		t.Fatalf("GetRelatedMaterials: %v", err)
+	}
+
+	if len(recorder.queries) != 2 {
+		t.Fatalf("executed %d queries, want 2", len(recorder.queries))
+	}
+	related := recorder.queries[1]
+	if !strings.Contains(related.sql, "grade_levels && $3") {
+		t.Fatalf("query %q does not bind grade_levels as a single array parameter", related.sql)
+	}
+	if len(related.args) != 3 || related.args[2] != `{"grade_9"}` {
+		t.Fatalf("args = %#v, want grade array literal as third argument", related.args)
+	}
+}
+
+func TestPostgresCreateMaterialEncodesArrays(t *testing.T) {
+	repo, recorder := newRecordingRepository(t, []string{"id"}, []driver.Value{uuid.New().String()})
+
+	material := &types.TeachingMaterial{
+		Title:       "勾股定理",
+		GradeLevels: []types.GradeLevel{types.GradeLevel8},
+		Tags:        []string{"几何"},
+		Embeddings:  types.Vector{0.5, 1},
+	}
+	if err := repo.CreateMaterial(material); err != nil {
+		t.Fatalf("CreateMaterial: %v", err)
+	}
+
+	args := recorder.queries[0].args
+	for _, want := range []driver.Value{`{"grade_8"}`, `{"几何"}`, "[0.5,1]"} {
+		found := false
+		for _, arg := range args {
+			if arg == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("insert args %#v do not contain %#v", args, want)
+		}
+	}
+}
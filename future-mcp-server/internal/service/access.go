@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+
+	"github.com/future-mcp/future-mcp-server/internal/auth"
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// rolePermissions 角色拥有的权限，格式为"资源:操作"，"资源:*"与"*"为通配
+var rolePermissions = map[types.UserRole][]string{
+	types.UserRoleGuest:     {permissionMaterialsRead},
+	types.UserRoleStudent:   {permissionMaterialsRead},
+	types.UserRolePartner:   {permissionMaterialsRead},
+	types.UserRoleTeacher:   {permissionMaterialsRead, permissionContentGenerate},
+	types.UserRoleDeveloper: {permissionMaterialsRead, permissionContentGenerate},
+	types.UserRoleInternal:  {"*"},
+	types.UserRoleAdmin:     {"*"},
+}
+
+const (
+	// guestConcurrentLimit 访客（含所有匿名调用）同时执行的工具调用数上限
+	guestConcurrentLimit = 2
+	// userConcurrentLimit 已认证用户同时执行的工具调用数上限
+	userConcurrentLimit = 10
+)
+
+// roleAccess 按调用者角色校验工具权限与并发配额，实现mcp.PermissionChecker与mcp.UserConcurrencyLimiter
+// 角色来自传输层认证后的调用者（JWT声明或stdio配置），匿名调用按访客处理
+type roleAccess struct{}
+
+// ValidateUserPermission 实现mcp.PermissionChecker
+func (roleAccess) ValidateUserPermission(ctx context.Context, userID uuid.UUID, resource string, action string) (bool, error) {
+	for _, permission := range rolePermissions[callerRole(ctx, userID)] {
+		if permission == "*" || permission == resource+":*" || permission == resource+":"+action {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// UserConcurrentLimit 实现mcp.UserConcurrencyLimiter
+func (roleAccess) UserConcurrentLimit(ctx context.Context, userID uuid.UUID) (int, error) {
+	if callerRole(ctx, userID) == types.UserRoleGuest {
+		return guestConcurrentLimit, nil
+	}
+	return userConcurrentLimit, nil
+}
+
+// callerRole 调用者角色，匿名调用始终为访客
+func callerRole(ctx context.Context, userID uuid.UUID) types.UserRole {
+	if userID == uuid.Nil {
+		return types.UserRoleGuest
+	}
+	return auth.RoleFromContext(ctx)
+}
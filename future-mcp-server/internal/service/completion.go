@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+)
+
+// completionMaterialPoolSize 参与素材补全排序的候选素材数量上限
+const completionMaterialPoolSize = 100
+
+// optionDisplayNames 枚举参数取值的显示名称，用于补全匹配与征询表单的选项名称
+var optionDisplayNames = map[string]map[string]string{
+	"student_level": {"beginner": "基础", "intermediate": "中等", "advanced": "拔高"},
+	"exercise_type": {"practice": "课堂练习", "homework": "课后作业", "quiz": "小测验", "olympic": "奥数", "competition": "竞赛"},
+	"difficulty":    {"easy": "简单", "medium": "中等", "hard": "困难", "challenge": "挑战"},
+}
+
+// registerCompleters 注册教育参数的补全能力：年级、学科与学段的显示名称，素材与知识点的候选值
+func (m *EducationModule) registerCompleters(server *mcp.Server) {
+	// 资源模板中的年级取值为"3"，提示模板与工具中为"grade_3"，显示名称统一按grade_N查找
+	gradeLabel := func(value string) string {
+		return gradeDisplayName("grade_" + strings.TrimPrefix(value, "grade_"))
+	}
+	server.RegisterCompleter("grade", &mcp.ArgumentCompleter{
+		Candidates: func(ctx context.Context, contextArgs map[string]string) []mcp.CompletionCandidate {
+			return mcp.EnumCompletionCandidates(promptGradeOptions, gradeLabel)
+		},
+		Label: gradeLabel,
+	})
+	server.RegisterCompleter("subject", &mcp.ArgumentCompleter{
+		Candidates: func(ctx context.Context, contextArgs map[string]string) []mcp.CompletionCandidate {
+			return mcp.EnumCompletionCandidates(knownSubjects, subjectDisplayName)
+		},
+		Label: subjectDisplayName,
+	})
+	server.RegisterCompleter("level", &mcp.ArgumentCompleter{
+		Label: knowledgeGraphLevelDisplayName,
+	})
+	for argName, names := range optionDisplayNames {
+		names := names
+		server.RegisterCompleter(argName, &mcp.ArgumentCompleter{
+			Label: func(value string) string {
+				return names[value]
+			},
+		})
+	}
+
+	materialIDs := func(ctx context.Context, contextArgs map[string]string) []mcp.CompletionCandidate {
+		return m.materialCompletionCandidates(ctx, contextArgs, false)
+	}
+	server.RegisterCompleter("material_id", &mcp.ArgumentCompleter{Candidates: materialIDs})
+	server.RegisterCompleter("material_ids", &mcp.ArgumentCompleter{Candidates: materialIDs, List: true})
+
+	knowledgePoints := &mcp.ArgumentCompleter{
+		Candidates: func(ctx context.Context, contextArgs map[string]string) []mcp.CompletionCandidate {
+			return knowledgePointCompletionCandidates(contextArgs["subject"])
+		},
+	}
+	for _, argName := range []string{"knowledge_point", "topic", "objectives", "learning_goals"} {
+		server.RegisterCompleter(argName, knowledgePoints)
+	}
+
+	server.RegisterCompleter("query", &mcp.ArgumentCompleter{
+		Candidates: func(ctx context.Context, contextArgs map[string]string) []mcp.CompletionCandidate {
+			candidates := knowledgePointCompletionCandidates(contextArgs["subject"])
+			return append(candidates, m.materialCompletionCandidates(ctx, contextArgs, true)...)
+		},
+	})
+}
+
+// materialCompletionCandidates 从素材库获取当前用户可访问的素材，byTitle为true时以标题作为候选值，否则以ID作为候选值
+// 上下文中已填写的年级与学科用于缩小范围
+func (m *EducationModule) materialCompletionCandidates(ctx context.Context, contextArgs map[string]string, byTitle bool) []mcp.CompletionCandidate {
+	if m.materialService == nil {
+		return nil
+	}
+
+	search := types.SearchMaterialsRequest{
+		Subject: types.Subject(contextArgs["subject"]),
+		Pagination: types.PaginationRequest{
+			Page:     1,
+			PageSize: completionMaterialPoolSize,
+		},
+	}
+	if grade := contextArgs["grade"]; grade != "" {
+		search.Grade = convertToGradeLevels([]string{grade})
+	}
+
+	userID := mcp.UserIDFromContext(ctx)
+	result, err := m.materialService.SearchMaterials(userID, search)
+	if err != nil {
+		return nil
+	}
+	candidates := make([]mcp.CompletionCandidate, 0, len(result.Materials))
+	for _, material := range result.Materials {
+		if byTitle {
+			candidates = append(candidates, mcp.CompletionCandidate{Value: material.Title})
+			continue
+		}
+		candidates = append(candidates, mcp.CompletionCandidate{
+			Value:  material.ID.String(),
+			Labels: []string{material.Title},
+		})
+	}
+	return candidates
+}
+
+// knowledgePointCompletionCandidates 从知识图谱获取知识点名称，指定学科时只返回该学科的知识点
+func knowledgePointCompletionCandidates(subject string) []mcp.CompletionCandidate {
+	keys := make([]string, 0, len(knowledgeGraphs))
+	for key := range knowledgeGraphs {
+		if subject == "" || strings.HasPrefix(key, subject+"/") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var candidates []mcp.CompletionCandidate
+	for _, key := range keys {
+		for _, node := range knowledgeGraphs[key].nodes {
+			label, _ := node["label"].(string)
+			id, _ := node["id"].(string)
+			candidates = append(candidates, mcp.CompletionCandidate{
+				Value:  label,
+				Labels: []string{id},
+			})
+		}
+	}
+	return candidates
+}
+
+// knowledgeGraphLevelDisplayName 知识图谱学段显示名称
+func knowledgeGraphLevelDisplayName(level string) string {
+	names := map[string]string{
+		"elementary": "小学", "junior": "初中", "senior": "高中",
+	}
+	if name, ok := names[level]; ok {
+		return name
+	}
+	return level
+}
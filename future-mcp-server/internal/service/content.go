@@ -0,0 +1,110 @@
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+// materialURIScheme 教学素材资源URI前缀
+const materialURIScheme = "material://"
+
+// maxInlineAudioSize 以audio内容块内联返回的音频素材大小上限，超出时只返回资源链接
+const maxInlineAudioSize = 1 << 20
+
+// MaterialFileReader 读取素材文件内容
+type MaterialFileReader interface {
+	ReadMaterialFile(material *types.TeachingMaterial) ([]byte, error)
+}
+
+// LocalMaterialFileReader 从本地存储目录读取素材文件，文件名为"素材ID.文件格式"
+type LocalMaterialFileReader struct {
+	root string
+}
+
+// NewLocalMaterialFileReader 创建本地素材文件读取器
+func NewLocalMaterialFileReader(root string) *LocalMaterialFileReader {
+	return &LocalMaterialFileReader{root: root}
+}
+
+// ReadMaterialFile 实现MaterialFileReader接口
+func (r *LocalMaterialFileReader) ReadMaterialFile(material *types.TeachingMaterial) ([]byte, error) {
+	name := material.ID.String()
+	if format := strings.TrimPrefix(strings.ToLower(material.Metadata.Format), "."); format != "" {
+		name += "." + format
+	}
+	// 文件格式来自素材记录，取Base防止路径穿越
+	return os.ReadFile(filepath.Join(r.root, filepath.Base(name)))
+}
+
+// materialFormatMimeTypes 素材文件格式对应的MIME类型
+var materialFormatMimeTypes = map[string]string{
+	"mp4":  "video/mp4",
+	"webm": "video/webm",
+	"mov":  "video/quicktime",
+	"mp3":  "audio/mpeg",
+	"m4a":  "audio/mp4",
+	"wav":  "audio/wav",
+	"ogg":  "audio/ogg",
+	"pdf":  "application/pdf",
+	"ppt":  "application/vnd.ms-powerpoint",
+	"pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	"doc":  "application/msword",
+	"docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"png":  "image/png",
+	"jpg":  "image/jpeg",
+	"jpeg": "image/jpeg",
+	"gif":  "image/gif",
+	"svg":  "image/svg+xml",
+	"json": "application/json",
+	"md":   "text/markdown",
+	"txt":  "text/plain",
+}
+
+// materialTypeMimeTypes 未记录文件格式时按素材类型推断的MIME类型
+var materialTypeMimeTypes = map[types.MaterialType]string{
+	types.MaterialTypeVideo:      "video/mp4",
+	types.MaterialTypePPT:        "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	types.MaterialTypePDF:        "application/pdf",
+	types.MaterialTypeExercise:   "application/json",
+	types.MaterialTypeLessonPlan: "application/json",
+	types.MaterialTypeAudio:      "audio/mpeg",
+	types.MaterialTypeImage:      "image/png",
+}
+
+// materialURI 素材资源URI
+func materialURI(material *types.TeachingMaterial) string {
+	return materialURIScheme + material.ID.String()
+}
+
+// materialMimeType 素材文件的MIME类型，优先使用元数据中的文件格式
+func materialMimeType(material *types.TeachingMaterial) string {
+	format := strings.TrimPrefix(strings.ToLower(material.Metadata.Format), ".")
+	if mimeType, ok := materialFormatMimeTypes[format]; ok {
+		return mimeType
+	}
+	if mimeType, ok := materialTypeMimeTypes[material.Type]; ok {
+		return mimeType
+	}
+	return "application/octet-stream"
+}
+
+// materialRecordContent 以JSON记录形式表示素材，向量嵌入不随内容返回
+func materialRecordContent(material *types.TeachingMaterial) (types.ResourceContent, error) {
+	record := *material
+	record.Embeddings = nil
+
+	data, err := json.MarshalIndent(&record, "", "  ")
+	if err != nil {
+		return types.ResourceContent{}, err
+	}
+
+	return types.ResourceContent{
+		URI:      materialURI(material),
+		MimeType: "application/json",
+		Text:     string(data),
+	}, nil
+}
@@ -0,0 +1,623 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+	"github.com/google/uuid"
+)
+
+// educationModuleName 教育模块名称
+const educationModuleName = "talink-education"
+
+// 工具权限
+const (
+	permissionMaterialsRead   = "materials:read"
+	permissionContentGenerate = "content:generate"
+)
+
+// 工具执行参数
+const (
+	// generationToolTimeout 生成类工具需要等待客户端LLM多轮采样，超时更长
+	generationToolTimeout = 3 * time.Minute
+	// generationToolConcurrency 生成类工具各自的执行槽位，限制长时间占用的调用数量
+	generationToolConcurrency = 4
+
+	searchToolCostWeight     = 1
+	generationToolCostWeight = 10
+)
+
+// 素材查询错误
+var (
+	errInvalidMaterialID = errors.New("invalid material id")
+	errMaterialNotFound  = errors.New("material not found")
+)
+
+// readOnlyToolAnnotations 检索与查看类工具：只读、可重复调用，仅访问素材库
+// 客户端可据此自动批准调用
+func readOnlyToolAnnotations(title string) *types.ToolAnnotations {
+	readOnly, idempotent, openWorld := true, true, false
+	return &types.ToolAnnotations{
+		Title:          title,
+		ReadOnlyHint:   &readOnly,
+		IdempotentHint: &idempotent,
+		OpenWorldHint:  &openWorld,
+	}
+}
+
+// generationToolAnnotations 生成类工具：不修改服务端数据，但每次调用都会请求客户端LLM生成新内容
+// 客户端应在调用前征得用户确认
+func generationToolAnnotations(title string) *types.ToolAnnotations {
+	readOnly, destructive, idempotent, openWorld := false, false, false, true
+	return &types.ToolAnnotations{
+		Title:           title,
+		ReadOnlyHint:    &readOnly,
+		DestructiveHint: &destructive,
+		IdempotentHint:  &idempotent,
+		OpenWorldHint:   &openWorld,
+	}
+}
+
+// EducationModule TALink教育模块，提供素材检索、教案与练习题生成等工具，
+// 课程大纲、知识图谱与教学素材资源，以及教学类提示模板
+type EducationModule struct {
+	materialService MaterialService
+	materialFiles   MaterialFileReader
+}
+
+// NewEducationModule 创建教育模块，materialService为nil时素材相关能力返回未配置错误，
+// materialFiles为nil时素材详情不内联素材文件
+func NewEducationModule(materialService MaterialService, materialFiles MaterialFileReader) *EducationModule {
+	return &EducationModule{materialService: materialService, materialFiles: materialFiles}
+}
+
+// Name 实现mcp.Module接口
+func (m *EducationModule) Name() string {
+	return educationModuleName
+}
+
+// Register 实现mcp.Module接口，注册教育模块的工具、资源、提示模板与参数补全
+func (m *EducationModule) Register(server *mcp.Server) error {
+	if err := m.registerTools(server); err != nil {
+		return err
+	}
+	if err := m.registerResources(server); err != nil {
+		return err
+	}
+	m.registerPrompts(server)
+	m.registerCompleters(server)
+	return nil
+}
+
+// registerTools 注册教育工具，输入与输出Schema由处理器的参数与结果类型生成
+// 类型无法生成Schema属于编码错误，返回所有注册失败的工具错误，由模块注册失败报告
+func (m *EducationModule) registerTools(server *mcp.Server) error {
+	var errs []error
+
+	// 检索类工具
+	errs = append(errs, mcp.RegisterTypedTool(server.Tools(), &types.ToolDefinition{
+		Name:        "search_teaching_materials",
+		Description: "按关键词搜索教学素材，支持学而思培优体系",
+		Annotations: readOnlyToolAnnotations("检索教学素材"),
+		Category:    types.ToolCategorySearch,
+		Permission:  permissionMaterialsRead,
+		CostWeight:  searchToolCostWeight,
+	}, m.handleSearchMaterials))
+
+	errs = append(errs, mcp.RegisterTypedTool(server.Tools(), &types.ToolDefinition{
+		Name:        "search_by_grade_subject",
+		Description: "按年级学科筛选教学素材 (学而思培优体系)",
+		Annotations: readOnlyToolAnnotations("按年级学科检索素材"),
+		Category:    types.ToolCategorySearch,
+		Permission:  permissionMaterialsRead,
+		CostWeight:  searchToolCostWeight,
+	}, m.handleSearchByGradeSubject))
+
+	errs = append(errs, mcp.RegisterTypedTool(server.Tools(), &types.ToolDefinition{
+		Name:        "get_recommended_materials",
+		Description: "基于学习数据个性化推荐 (AI算法)",
+		Annotations: readOnlyToolAnnotations("个性化素材推荐"),
+		Category:    types.ToolCategorySearch,
+		Permission:  permissionMaterialsRead,
+		CostWeight:  searchToolCostWeight,
+	}, m.handleGetRecommendedMaterials))
+
+	// 内容类工具
+	errs = append(errs, mcp.RegisterTypedTool(server.Tools(), &types.ToolDefinition{
+		Name:        "get_material_detail",
+		Description: "获取教学素材详细信息 (包含教学元数据)",
+		Annotations: readOnlyToolAnnotations("查看素材详情"),
+		Category:    types.ToolCategoryContent,
+		Permission:  permissionMaterialsRead,
+		CostWeight:  searchToolCostWeight,
+	}, m.handleGetMaterialDetail))
+
+	errs = append(errs, mcp.RegisterTypedTool(server.Tools(), &types.ToolDefinition{
+		Name:        "get_related_materials",
+		Description: "获取相关素材 (知识图谱关联)",
+		Annotations: readOnlyToolAnnotations("查找关联素材"),
+		Category:    types.ToolCategorySearch,
+		Permission:  permissionMaterialsRead,
+		CostWeight:  searchToolCostWeight,
+	}, m.handleGetRelatedMaterials))
+
+	// 生成类工具
+	errs = append(errs, mcp.RegisterTypedTool(server.Tools(), &types.ToolDefinition{
+		Name:           "generate_lesson_plan",
+		Description:    "生成个性化教案 (学而思教研标准)",
+		Annotations:    generationToolAnnotations("生成教案"),
+		Category:       types.ToolCategoryGeneration,
+		Permission:     permissionContentGenerate,
+		CostWeight:     generationToolCostWeight,
+		Timeout:        generationToolTimeout,
+		MaxConcurrency: generationToolConcurrency,
+		ElicitFields:   []string{"grade", "objectives", "student_level"},
+	}, m.handleGenerateLessonPlan))
+
+	errs = append(errs, mcp.RegisterTypedTool(server.Tools(), &types.ToolDefinition{
+		Name:           "generate_exercises",
+		Description:    "生成智能练习题 (奥数/竞赛专项)",
+		Annotations:    generationToolAnnotations("生成练习题"),
+		Category:       types.ToolCategoryGeneration,
+		Permission:     permissionContentGenerate,
+		CostWeight:     generationToolCostWeight,
+		Timeout:        generationToolTimeout,
+		MaxConcurrency: generationToolConcurrency,
+		ElicitFields:   []string{"exercise_type", "difficulty"},
+	}, m.handleGenerateExercises))
+
+	return errors.Join(errs...)
+}
+
+// registerResources 注册教育资源与资源模板，返回所有注册失败的资源模板错误
+func (m *EducationModule) registerResources(server *mcp.Server) error {
+	// 课程大纲资源
+	server.Resources().RegisterResource(&types.ResourceDefinition{
+		URI:         "curriculum://grade-1/math",
+		Name:        "一年级数学课程大纲 (学而思标准)",
+		Description: "一年级数学课程框架，包含知识体系、教学目标和评估标准",
+		MimeType:    "application/json",
+		Handler:     mcp.WithResourceVars(m.handleCurriculumResource, map[string]string{"grade": "1", "subject": "math"}),
+	})
+
+	server.Resources().RegisterResource(&types.ResourceDefinition{
+		URI:         "curriculum://grade-2/math",
+		Name:        "二年级数学课程大纲 (学而思标准)",
+		Description: "二年级数学课程框架，包含知识体系、教学目标和评估标准",
+		MimeType:    "application/json",
+		Handler:     mcp.WithResourceVars(m.handleCurriculumResource, map[string]string{"grade": "2", "subject": "math"}),
+	})
+
+	// 知识图谱资源
+	server.Resources().RegisterResource(&types.ResourceDefinition{
+		URI:         "knowledge-graph://math/elementary",
+		Name:        "小学数学知识图谱",
+		Description: "小学数学知识点关联网络，包含概念关系和学习路径",
+		MimeType:    "application/json",
+		Handler:     mcp.WithResourceVars(m.handleKnowledgeGraphResource, map[string]string{"subject": "math", "level": "elementary"}),
+	})
+
+	// 教学模板资源
+	server.Resources().RegisterResource(&types.ResourceDefinition{
+		URI:         "template://lesson-plan/5e-model",
+		Name:        "5E教学模型模板",
+		Description: "基于Engage-Explore-Explain-Elaborate-Evaluate的教学流程模板",
+		MimeType:    "application/json",
+		Handler:     m.handleTeachingTemplateResource,
+	})
+
+	// 参数化资源模板
+	var errs []error
+	errs = append(errs, registerResourceTemplate(server, &types.ResourceTemplateDefinition{
+		URITemplate: "curriculum://grade-{grade}/subject-{subject}",
+		Name:        "课程大纲 (学而思标准)",
+		Description: "按年级(1-12)和学科获取课程框架，包含知识体系、教学目标和评估标准",
+		MimeType:    "application/json",
+		Handler:     m.handleCurriculumResource,
+		Completions: map[string][]string{
+			"grade":   curriculumGrades,
+			"subject": knownSubjects,
+		},
+	}))
+
+	errs = append(errs, registerResourceTemplate(server, &types.ResourceTemplateDefinition{
+		URITemplate: "knowledge-graph://subject-{subject}/level-{level}",
+		Name:        "学科知识图谱",
+		Description: "按学科和学段(elementary/junior/senior)获取知识点关联网络",
+		MimeType:    "application/json",
+		Handler:     m.handleKnowledgeGraphResource,
+		Completions: map[string][]string{
+			"subject": knownSubjects,
+			"level":   knowledgeGraphLevels,
+		},
+	}))
+
+	errs = append(errs, registerResourceTemplate(server, &types.ResourceTemplateDefinition{
+		URITemplate: materialURIScheme + "{material_id}",
+		Name:        "教学素材",
+		Description: "按素材ID获取教学素材记录，包含课标对齐、元数据与授权信息",
+		MimeType:    "application/json",
+		Handler:     m.handleMaterialResource,
+	}))
+
+	return errors.Join(errs...)
+}
+
+// registerResourceTemplate 注册资源模板，模板语法错误时返回带模板的错误
+func registerResourceTemplate(server *mcp.Server, definition *types.ResourceTemplateDefinition) error {
+	if err := server.Resources().RegisterTemplate(definition); err != nil {
+		return fmt.Errorf("resource template %s: %w", definition.URITemplate, err)
+	}
+	return nil
+}
+
+// 工具处理器实现（这里提供基础实现，实际需要调用具体服务）
+func (m *EducationModule) handleSearchMaterials(ctx *types.ToolContext, params *searchMaterialsInput) (*searchMaterialsOutput, error) {
+	// 调用素材服务
+	ctx.ReportProgress(0, 2, "正在检索素材")
+	result, err := m.materialService.SearchMaterials(ctx.UserID, types.SearchMaterialsRequest{
+		Query:   params.Query,
+		Grade:   convertToGradeLevels(params.Grade),
+		Subject: types.Subject(params.Subject),
+		Pagination: types.PaginationRequest{
+			Page:     1,
+			PageSize: params.Limit,
+		},
+		Cursor: params.Cursor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 返回结构化素材记录，向量嵌入体积大且对调用方无用，不随结果返回；
+	// 无权访问的私有素材已在检索时排除
+	ctx.ReportProgress(1, 2, "正在整理检索结果")
+	materials := result.Materials
+	for i := range materials {
+		materials[i].Embeddings = nil
+	}
+
+	return &searchMaterialsOutput{
+		Materials:  materials,
+		TotalCount: result.TotalCount,
+		NextCursor: result.NextCursor,
+	}, nil
+}
+
+// searchMaterialsInput 素材检索工具参数
+type searchMaterialsInput struct {
+	Query   string   `json:"query" description:"搜索关键词" jsonschema:"required"`
+	Grade   []string `json:"grade,omitempty" description:"年级列表 (学而思体系)"`
+	Subject string   `json:"subject,omitempty" description:"学科" jsonschema:"enum=math|chinese|english|physics|chemistry|biology"`
+	Limit   int      `json:"limit,omitempty" description:"返回数量限制" jsonschema:"maximum=50,default=10"`
+	Cursor  string   `json:"cursor,omitempty" description:"分页游标，取上一次结果中的下一页游标"`
+}
+
+// searchMaterialsOutput 素材检索工具的结构化结果
+type searchMaterialsOutput struct {
+	Materials  []types.TeachingMaterial `json:"materials"`
+	TotalCount int64                    `json:"total_count"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
+}
+
+// searchByGradeSubjectInput 按年级学科检索工具参数
+type searchByGradeSubjectInput struct {
+	Grade         string `json:"grade" description:"年级" jsonschema:"required,enum=grade_1|grade_2|grade_3|grade_4|grade_5|grade_6|grade_7|grade_8|grade_9"`
+	Subject       string `json:"subject" description:"学科" jsonschema:"required,enum=math|chinese|english|physics|chemistry|biology"`
+	Difficulty    string `json:"difficulty,omitempty" description:"难度级别" jsonschema:"enum=easy|medium|hard,default=medium"`
+	TeachingStage string `json:"teaching_stage,omitempty" description:"教学阶段" jsonschema:"enum=basic|advanced|olympic"`
+}
+
+// recommendedMaterialsInput 个性化推荐工具参数
+type recommendedMaterialsInput struct {
+	UserID         string   `json:"user_id" description:"用户ID" jsonschema:"required"`
+	LearningGoals  []string `json:"learning_goals,omitempty" description:"学习目标"`
+	HistoryRecords []string `json:"history_records,omitempty" description:"历史学习记录"`
+	Limit          int      `json:"limit,omitempty" description:"推荐数量" jsonschema:"maximum=20,default=5"`
+}
+
+// materialDetailInput 素材详情工具参数
+type materialDetailInput struct {
+	MaterialID string `json:"material_id" description:"素材ID" jsonschema:"required"`
+}
+
+// relatedMaterialsInput 关联素材工具参数
+type relatedMaterialsInput struct {
+	MaterialID   string `json:"material_id" description:"素材ID" jsonschema:"required"`
+	RelationType string `json:"relation_type,omitempty" description:"关联类型" jsonschema:"enum=prerequisite|followup|similar|complementary,default=similar"`
+	Limit        int    `json:"limit,omitempty" description:"返回数量" jsonschema:"default=5"`
+}
+
+// 其他工具处理器的基础实现
+func (m *EducationModule) handleSearchByGradeSubject(ctx *types.ToolContext, params *searchByGradeSubjectInput) (*types.ToolsCallResponse, error) {
+	return &types.ToolsCallResponse{
+		Content: []types.Content{
+			{
+				Type: "text",
+				Text: "年级学科筛选功能正在开发中...",
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+func (m *EducationModule) handleGetRecommendedMaterials(ctx *types.ToolContext, params *recommendedMaterialsInput) (*types.ToolsCallResponse, error) {
+	return &types.ToolsCallResponse{
+		Content: []types.Content{
+			{
+				Type: "text",
+				Text: "个性化推荐功能正在开发中...",
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// handleGetMaterialDetail 获取素材详情：返回摘要文本、嵌入的素材记录、素材资源链接，
+// 小体积音频素材内联为audio内容块，素材文件与预览以资源链接返回
+func (m *EducationModule) handleGetMaterialDetail(ctx *types.ToolContext, params *materialDetailInput) (*types.ToolsCallResponse, error) {
+	detail, err := m.accessibleMaterialDetail(ctx, params.MaterialID)
+	if err != nil {
+		return nil, materialToolError(err, params.MaterialID)
+	}
+	material := detail.TeachingMaterial
+
+	record, err := materialRecordContent(material)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := fmt.Sprintf("%s (ID: %s)\n类型：%s，学科：%s，难度：%s", material.Title, material.ID, material.Type, material.Subject, material.Difficulty)
+	if material.Description != "" {
+		summary += "\n" + material.Description
+	}
+
+	content := []types.Content{
+		mcp.TextContent(summary),
+		mcp.EmbeddedResourceContent(record),
+		mcp.ResourceLinkContent(materialURI(material), material.Title, "素材记录", "application/json", 0),
+	}
+	if audio, ok := m.inlineAudio(material); ok {
+		content = append(content, audio)
+	}
+
+	// 素材文件与预览以链接形式返回，由客户端按需获取，避免大文件直接嵌入结果
+	var size int64
+	if material.Metadata.FileSize != nil {
+		size = *material.Metadata.FileSize
+	}
+	if detail.AccessURL != "" {
+		content = append(content, mcp.ResourceLinkContent(detail.AccessURL, material.Title, "素材文件", materialMimeType(material), size))
+	}
+	if detail.PreviewURL != "" {
+		content = append(content, mcp.ResourceLinkContent(detail.PreviewURL, material.Title+" (预览)", "素材预览", materialMimeType(material), 0))
+	}
+
+	return &types.ToolsCallResponse{
+		Content: content,
+		IsError: false,
+	}, nil
+}
+
+// inlineAudio 读取音频素材文件并生成audio内容块，未配置文件读取器、文件过大或读取失败时跳过
+func (m *EducationModule) inlineAudio(material *types.TeachingMaterial) (types.Content, bool) {
+	if m.materialFiles == nil || material.Type != types.MaterialTypeAudio {
+		return types.Content{}, false
+	}
+	if size := material.Metadata.FileSize; size != nil && *size > maxInlineAudioSize {
+		return types.Content{}, false
+	}
+
+	data, err := m.materialFiles.ReadMaterialFile(material)
+	if err != nil {
+		logger.Warn("Failed to read audio material",
+			logger.Any("material_id", material.ID),
+			logger.Any("error", err))
+		return types.Content{}, false
+	}
+	if len(data) == 0 || len(data) > maxInlineAudioSize {
+		return types.Content{}, false
+	}
+	return mcp.AudioContent(data, materialMimeType(material)), true
+}
+
+func (m *EducationModule) handleGetRelatedMaterials(ctx *types.ToolContext, params *relatedMaterialsInput) (*types.ToolsCallResponse, error) {
+	return &types.ToolsCallResponse{
+		Content: []types.Content{
+			{
+				Type: "text",
+				Text: "关联素材推荐功能正在开发中...",
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// 资源处理器实现
+func (m *EducationModule) handleCurriculumResource(ctx context.Context, uri string, vars map[string]string) (*types.ResourcesReadResponse, error) {
+	grade, err := parseCurriculumGrade(uri, vars["grade"])
+	if err != nil {
+		return nil, err
+	}
+
+	subject := vars["subject"]
+	if !isKnownSubject(subject) {
+		return nil, &mcp.InvalidResourceError{URI: uri, Reason: fmt.Sprintf("unknown subject: %s", subject)}
+	}
+
+	// 模拟课程大纲数据
+	gradeLevel := fmt.Sprintf("grade_%d", grade)
+	curriculumData := map[string]interface{}{
+		"grade":   gradeLevel,
+		"subject": subject,
+		"name":    fmt.Sprintf("%s%s课程大纲", gradeDisplayName(gradeLevel), subjectDisplayName(subject)),
+		"objectives": []string{
+			fmt.Sprintf("掌握%s%s核心知识与基本技能", gradeDisplayName(gradeLevel), subjectDisplayName(subject)),
+			"形成良好的学习习惯与思维方法",
+		},
+		"units": []string{},
+	}
+
+	if subject == string(types.SubjectMath) {
+		objectives, units := mathCurriculumOutline(grade)
+		curriculumData["objectives"] = objectives
+		curriculumData["units"] = units
+	}
+
+	data, _ := json.Marshal(curriculumData)
+
+	return &types.ResourcesReadResponse{
+		Contents: []types.ResourceContent{
+			{
+				URI:      uri,
+				MimeType: "application/json",
+				Text:     string(data),
+			},
+		},
+	}, nil
+}
+
+func (m *EducationModule) handleKnowledgeGraphResource(ctx context.Context, uri string, vars map[string]string) (*types.ResourcesReadResponse, error) {
+	subject, level := vars["subject"], vars["level"]
+	if !isKnownSubject(subject) {
+		return nil, &mcp.InvalidResourceError{URI: uri, Reason: fmt.Sprintf("unknown subject: %s", subject)}
+	}
+	if !containsString(knowledgeGraphLevels, level) {
+		return nil, &mcp.InvalidResourceError{URI: uri, Reason: fmt.Sprintf("unknown level: %s", level)}
+	}
+
+	// 模拟知识图谱数据
+	graphData := map[string]interface{}{
+		"subject": subject,
+		"level":   level,
+		"nodes":   []map[string]interface{}{},
+		"edges":   []map[string]interface{}{},
+	}
+	if graph, ok := knowledgeGraphs[subject+"/"+level]; ok {
+		graphData["nodes"] = graph.nodes
+		graphData["edges"] = graph.edges
+	}
+
+	data, _ := json.Marshal(graphData)
+
+	return &types.ResourcesReadResponse{
+		Contents: []types.ResourceContent{
+			{
+				URI:      uri,
+				MimeType: "application/json",
+				Text:     string(data),
+			},
+		},
+	}, nil
+}
+
+func (m *EducationModule) handleTeachingTemplateResource(ctx context.Context, uri string, vars map[string]string) (*types.ResourcesReadResponse, error) {
+	// 模拟教学模板数据
+	templateData := map[string]interface{}{
+		"model": "5E",
+		"phases": []map[string]interface{}{
+			{"phase": "Engage", "description": "激发兴趣", "duration": 5},
+			{"phase": "Explore", "description": "自主探索", "duration": 15},
+			{"phase": "Explain", "description": "概念讲解", "duration": 10},
+			{"phase": "Elaborate", "description": "深化应用", "duration": 10},
+			{"phase": "Evaluate", "description": "效果评估", "duration": 5},
+		},
+	}
+
+	data, _ := json.Marshal(templateData)
+
+	return &types.ResourcesReadResponse{
+		Contents: []types.ResourceContent{
+			{
+				URI:      uri,
+				MimeType: "application/json",
+				Text:     string(data),
+			},
+		},
+	}, nil
+}
+
+// handleMaterialResource 读取教学素材记录，仅返回当前用户有权访问的素材
+func (m *EducationModule) handleMaterialResource(ctx context.Context, uri string, vars map[string]string) (*types.ResourcesReadResponse, error) {
+	detail, err := m.accessibleMaterialDetail(ctx, vars["material_id"])
+	if err != nil {
+		return nil, &mcp.InvalidResourceError{URI: uri, Reason: err.Error()}
+	}
+
+	record, err := materialRecordContent(detail.TeachingMaterial)
+	if err != nil {
+		return nil, err
+	}
+	record.URI = uri
+
+	return &types.ResourcesReadResponse{
+		Contents: []types.ResourceContent{record},
+	}, nil
+}
+
+// accessibleMaterialDetail 获取当前用户可访问的素材详情，无权访问的私有素材按不存在处理
+func (m *EducationModule) accessibleMaterialDetail(ctx context.Context, rawID string) (*types.MaterialDetailResponse, error) {
+	if m.materialService == nil {
+		return nil, errors.New("material service not configured")
+	}
+
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidMaterialID, rawID)
+	}
+
+	userID := mcp.UserIDFromContext(ctx)
+	detail, err := m.materialService.GetMaterialDetail(userID, id)
+	if err != nil {
+		logger.Warn("Failed to load material",
+			logger.Any("material_id", id),
+			logger.Any("error", err))
+		return nil, fmt.Errorf("%w: %s", errMaterialNotFound, id)
+	}
+
+	if !materialAccessible(userID, detail.TeachingMaterial) {
+		return nil, fmt.Errorf("%w: %s", errMaterialNotFound, id)
+	}
+	return detail, nil
+}
+
+// materialToolError 素材ID无效或素材不存在属于调用参数问题，以工具错误返回便于调用方修正后重试，其他错误原样返回
+func materialToolError(err error, rawID string) error {
+	switch {
+	case errors.Is(err, errInvalidMaterialID):
+		return &mcp.ToolError{Message: fmt.Sprintf("素材ID格式无效：%s，请使用检索结果中的素材ID", rawID)}
+	case errors.Is(err, errMaterialNotFound):
+		return &mcp.ToolError{Message: fmt.Sprintf("素材不存在或无权访问：%s", rawID)}
+	default:
+		return err
+	}
+}
+
+// materialAccessible 私有素材只对授权用户可见
+func materialAccessible(userID uuid.UUID, material *types.TeachingMaterial) bool {
+	return material.Permissions.AccessLevel != "private" || containsUserID(material.Permissions.AllowedUsers, userID)
+}
+
+// containsUserID 检查用户ID列表是否包含指定用户
+func containsUserID(userIDs []uuid.UUID, userID uuid.UUID) bool {
+	for _, id := range userIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func convertToGradeLevels(grades []string) []types.GradeLevel {
+	result := make([]types.GradeLevel, len(grades))
+	for i, grade := range grades {
+		result[i] = types.GradeLevel(grade)
+	}
+	return result
+}
@@ -0,0 +1,637 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/future-mcp/future-mcp-server/internal/repository"
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+)
+
+func TestMain(m *testing.M) {
+	viper.Set("log.level", "error")
+	if err := logger.Init(); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// newEducationServer 创建注册了教育模块的MCP服务端，素材仓库为写入给定素材的内存仓库
+func newEducationServer(t *testing.T, materials ...*types.TeachingMaterial) *mcp.Server {
+	t.Helper()
+	return newEducationServerWithFiles(t, nil, materials...)
+}
+
+// newEducationServerWithFiles 同newEducationServer，素材详情可通过files读取素材文件
+func newEducationServerWithFiles(t *testing.T, files MaterialFileReader, materials ...*types.TeachingMaterial) *mcp.Server {
+	t.Helper()
+
+	repo := repository.NewMemoryMaterialRepository()
+	for _, material := range materials {
+		if err := repo.CreateMaterial(material); err != nil {
+			t.Fatalf("CreateMaterial: %v", err)
+		}
+	}
+	server := mcp.NewServer(mcp.ServerConfig{})
+	if err := server.RegisterModule(NewEducationModule(NewMaterialService(repo, NewMemoryCacheService(), nil), files)); err != nil {
+		t.Fatalf("RegisterModule: %v", err)
+	}
+	return server
+}
+
+// handle 以给定方法和参数调用MCP服务端
+func handle(t *testing.T, s *mcp.Server, ctx context.Context, id interface{}, method string, params interface{}) *types.MCPResponse {
+	t.Helper()
+
+	response, err := s.HandleRequest(ctx, &types.MCPRequest{
+		MCPMessage: types.MCPMessage{JSONRPC: "2.0", ID: id},
+		Method:     method,
+		Params:     params,
+	})
+	if err != nil {
+		t.Fatalf("%s: %v", method, err)
+	}
+	return response
+}
+
+// errorCode 返回响应的错误码，成功响应返回0
+func errorCode(response *types.MCPResponse) int {
+	if response == nil || response.Error == nil {
+		return 0
+	}
+	return response.Error.Code
+}
+
+func TestEducationModuleRegister(t *testing.T) {
+	// 注册错误不再被吞掉，所有工具与资源模板都应注册成功
+	server := mcp.NewServer(mcp.ServerConfig{})
+	if err := NewEducationModule(nil, nil).Register(server); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if tools := server.Tools().ListTools(); len(tools) != 7 {
+		t.Fatalf("registered tools = %d, want 7", len(tools))
+	}
+	if templates := server.Resources().ListTemplates(); len(templates) != 3 {
+		t.Fatalf("registered templates = %d, want 3", len(templates))
+	}
+
+	// 模板语法错误以带模板的错误返回，由模块注册失败报告
+	err := registerResourceTemplate(server, &types.ResourceTemplateDefinition{URITemplate: "material://{material_id"})
+	if err == nil || !strings.Contains(err.Error(), "material://{material_id") {
+		t.Fatalf("registerResourceTemplate err = %v, want template error", err)
+	}
+}
+
+func TestMaterialMimeType(t *testing.T) {
+	tests := []struct {
+		format       string
+		materialType types.MaterialType
+		want         string
+	}{
+		{"mp3", types.MaterialTypeAudio, "audio/mpeg"},
+		{".PDF", types.MaterialTypeLessonPlan, "application/pdf"},
+		{"", types.MaterialTypeAudio, "audio/mpeg"},
+		{"", types.MaterialTypeVideo, "video/mp4"},
+		{"xyz", "unknown", "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		material := &types.TeachingMaterial{Type: tt.materialType, Metadata: types.MaterialMetadata{Format: tt.format}}
+		if got := materialMimeType(material); got != tt.want {
+			t.Errorf("materialMimeType(%q, %s) = %q, want %q", tt.format, tt.materialType, got, tt.want)
+		}
+	}
+}
+
+func TestGetMaterialDetailContent(t *testing.T) {
+	material := &types.TeachingMaterial{
+		ID:          uuid.New(),
+		Title:       "英语听力训练",
+		Description: "初二英语听力材料",
+		Type:        types.MaterialTypeAudio,
+		Subject:     types.SubjectEnglish,
+		Metadata:    types.MaterialMetadata{Format: "mp3"},
+		Permissions: types.MaterialPermissions{AccessLevel: "public"},
+	}
+	root := t.TempDir()
+	audio := []byte("ID3 listening clip")
+	if err := os.WriteFile(filepath.Join(root, material.ID.String()+".mp3"), audio, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		files     MaterialFileReader
+		wantAudio bool
+	}{
+		{"with material files", NewLocalMaterialFileReader(root), true},
+		{"without material files", nil, false},
+		{"missing audio file", NewLocalMaterialFileReader(t.TempDir()), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newEducationServerWithFiles(t, tt.files, material)
+
+			response := handle(t, s, context.Background(), 1, types.MCPMethodToolsCall, map[string]interface{}{
+				"name":      "get_material_detail",
+				"arguments": map[string]string{"material_id": material.ID.String()},
+			})
+			if response.Error != nil {
+				t.Fatalf("tools/call error: %+v", response.Error)
+			}
+
+			result := response.Result.(*types.ToolsCallResponse)
+			wantLen := 3
+			if tt.wantAudio {
+				wantLen = 4
+			}
+			if len(result.Content) != wantLen {
+				t.Fatalf("content = %+v, want %d blocks", result.Content, wantLen)
+			}
+			if summary := result.Content[0]; summary.Type != types.ContentTypeText || !strings.Contains(summary.Text, material.Title) {
+				t.Fatalf("summary = %+v", summary)
+			}
+
+			record := result.Content[1]
+			if record.Type != types.ContentTypeResource || record.Resource == nil {
+				t.Fatalf("record = %+v, want embedded resource", record)
+			}
+			if record.Resource.URI != "material://"+material.ID.String() || record.Resource.MimeType != "application/json" {
+				t.Fatalf("embedded resource = %+v", record.Resource)
+			}
+			var decoded types.TeachingMaterial
+			if err := json.Unmarshal([]byte(record.Resource.Text), &decoded); err != nil || decoded.ID != material.ID {
+				t.Fatalf("embedded record = %q (%v)", record.Resource.Text, err)
+			}
+
+			// 无论是否有访问地址，都返回指向素材资源的链接
+			link := result.Content[2]
+			if link.Type != types.ContentTypeResourceLink || link.URI != "material://"+material.ID.String() || link.Name != material.Title {
+				t.Fatalf("resource link = %+v", link)
+			}
+
+			if !tt.wantAudio {
+				return
+			}
+			block := result.Content[3]
+			if block.Type != types.ContentTypeAudio || block.MimeType != "audio/mpeg" {
+				t.Fatalf("audio = %+v", block)
+			}
+			if data, err := base64.StdEncoding.DecodeString(block.Data); err != nil || string(data) != string(audio) {
+				t.Fatalf("audio data = %q (%v)", data, err)
+			}
+		})
+	}
+}
+
+func TestReadMaterialResource(t *testing.T) {
+	public := &types.TeachingMaterial{
+		ID:          uuid.New(),
+		Title:       "公开素材",
+		Type:        types.MaterialTypePDF,
+		Permissions: types.MaterialPermissions{AccessLevel: "public"},
+	}
+	private := &types.TeachingMaterial{
+		ID:          uuid.New(),
+		Title:       "私有素材",
+		Type:        types.MaterialTypePDF,
+		Permissions: types.MaterialPermissions{AccessLevel: "private"},
+	}
+	s := newEducationServer(t, public, private)
+
+	tests := []struct {
+		name     string
+		uri      string
+		wantCode int
+	}{
+		{"public material", "material://" + public.ID.String(), 0},
+		{"private material hidden", "material://" + private.ID.String(), types.MCPInvalidParams},
+		{"invalid id", "material://not-a-uuid", types.MCPInvalidParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := handle(t, s, context.Background(), 1, types.MCPMethodResourcesRead, map[string]string{"uri": tt.uri})
+			if code := errorCode(response); code != tt.wantCode {
+				t.Fatalf("error code = %d, want %d (%+v)", code, tt.wantCode, response.Error)
+			}
+			if tt.wantCode != 0 {
+				return
+			}
+			contents := response.Result.(*types.ResourcesReadResponse).Contents
+			if len(contents) != 1 || contents[0].URI != tt.uri || !strings.Contains(contents[0].Text, public.Title) {
+				t.Fatalf("contents = %+v", contents)
+			}
+		})
+	}
+}
+
+func TestSearchMaterialsCursor(t *testing.T) {
+	repo := repository.NewMemoryMaterialRepository()
+	all, err := NewMaterialService(repo, NewMemoryCacheService(), nil).SearchMaterials(uuid.Nil, types.SearchMaterialsRequest{
+		Pagination: types.PaginationRequest{Page: 1, PageSize: 100},
+	})
+	if err != nil {
+		t.Fatalf("SearchMaterials: %v", err)
+	}
+	if all.NextCursor != "" {
+		t.Fatalf("single page has next cursor %q", all.NextCursor)
+	}
+	if len(all.Materials) < 2 {
+		t.Fatalf("need at least 2 sample materials, got %d", len(all.Materials))
+	}
+
+	// 沿游标逐页遍历，结果与一次性查询一致
+	materialService := NewMaterialService(repo, NewMemoryCacheService(), mcp.NewCursorCodec("secret"))
+	req := types.SearchMaterialsRequest{Pagination: types.PaginationRequest{Page: 1, PageSize: 1}}
+	var walked []uuid.UUID
+	for {
+		page, err := materialService.SearchMaterials(uuid.Nil, req)
+		if err != nil {
+			t.Fatalf("SearchMaterials page %d: %v", len(walked)+1, err)
+		}
+		for _, material := range page.Materials {
+			walked = append(walked, material.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		req.Cursor = page.NextCursor
+	}
+	if len(walked) != len(all.Materials) {
+		t.Fatalf("walked %d materials, want %d", len(walked), len(all.Materials))
+	}
+	for i, material := range all.Materials {
+		if walked[i] != material.ID {
+			t.Fatalf("material %d = %s, want %s", i, walked[i], material.ID)
+		}
+	}
+
+	// 游标绑定查询条件，换用其他条件时被拒绝
+	first, err := materialService.SearchMaterials(uuid.Nil, types.SearchMaterialsRequest{Pagination: types.PaginationRequest{Page: 1, PageSize: 1}})
+	if err != nil {
+		t.Fatalf("SearchMaterials: %v", err)
+	}
+	_, err = materialService.SearchMaterials(uuid.Nil, types.SearchMaterialsRequest{Query: "方程", Cursor: first.NextCursor})
+	if !errors.Is(err, mcp.ErrInvalidCursor) {
+		t.Fatalf("cursor reused with other query: err = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestCompletionComplete(t *testing.T) {
+	s := newEducationServer(t)
+
+	tests := []struct {
+		name     string
+		ref      map[string]string
+		argument string
+		value    string
+		context  map[string]string
+		want     []string
+		wantCode int
+	}{
+		{
+			name:     "prompt enum by pinyin",
+			ref:      map[string]string{"type": types.CompletionRefPrompt, "name": "build_5e_lesson"},
+			argument: "subject",
+			value:    "wl",
+			want:     []string{"physics"},
+		},
+		{
+			name:     "prompt knowledge point scoped by subject",
+			ref:      map[string]string{"type": types.CompletionRefPrompt, "name": "explain_knowledge_point"},
+			argument: "knowledge_point",
+			value:    "fl",
+			context:  map[string]string{"subject": "physics"},
+			want:     []string{"浮力"},
+		},
+		{
+			name:     "resource template variable",
+			ref:      map[string]string{"type": types.CompletionRefResource, "uri": "knowledge-graph://subject-{subject}/level-{level}"},
+			argument: "level",
+			value:    "cz",
+			want:     []string{"junior"},
+		},
+		{
+			name:     "unknown prompt",
+			ref:      map[string]string{"type": types.CompletionRefPrompt, "name": "missing"},
+			argument: "grade",
+			wantCode: types.MCPInvalidParams,
+		},
+		{
+			name:     "unknown argument",
+			ref:      map[string]string{"type": types.CompletionRefPrompt, "name": "build_5e_lesson"},
+			argument: "missing",
+			wantCode: types.MCPInvalidParams,
+		},
+		{
+			name:     "unsupported reference type",
+			ref:      map[string]string{"type": "ref/unknown"},
+			argument: "grade",
+			wantCode: types.MCPInvalidParams,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := map[string]interface{}{
+				"ref":      tt.ref,
+				"argument": map[string]string{"name": tt.argument, "value": tt.value},
+			}
+			if tt.context != nil {
+				params["context"] = map[string]interface{}{"arguments": tt.context}
+			}
+
+			response := handle(t, s, context.Background(), 1, types.MCPMethodCompletionComplete, params)
+			if code := errorCode(response); code != tt.wantCode {
+				t.Fatalf("error code = %d, want %d (%+v)", code, tt.wantCode, response.Error)
+			}
+			if tt.wantCode != 0 {
+				return
+			}
+
+			result := response.Result.(*types.CompletionCompleteResponse)
+			if !reflect.DeepEqual(result.Completion.Values, tt.want) {
+				t.Fatalf("values = %v, want %v", result.Completion.Values, tt.want)
+			}
+			if result.Completion.Total != len(tt.want) || result.Completion.HasMore {
+				t.Fatalf("total = %d, hasMore = %v", result.Completion.Total, result.Completion.HasMore)
+			}
+		})
+	}
+}
+
+func TestToolsListIncludesOutputSchema(t *testing.T) {
+	s := newEducationServer(t)
+
+	response := handle(t, s, context.Background(), 1, types.MCPMethodToolsList, nil)
+	data, err := json.Marshal(response.Result)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var result struct {
+		Tools []struct {
+			Name         string          `json:"name"`
+			OutputSchema json.RawMessage `json:"outputSchema"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	withSchema := make(map[string]bool)
+	for _, tool := range result.Tools {
+		withSchema[tool.Name] = len(tool.OutputSchema) > 0
+	}
+	for _, name := range []string{"search_teaching_materials", "generate_lesson_plan", "generate_exercises"} {
+		if !withSchema[name] {
+			t.Fatalf("tool %s has no outputSchema in tools/list", name)
+		}
+	}
+}
+
+func TestDefaultToolAnnotations(t *testing.T) {
+	s := newEducationServer(t)
+
+	response := handle(t, s, context.Background(), 1, types.MCPMethodToolsList, nil)
+	tools := make(map[string]types.Tool)
+	for _, tool := range response.Result.(*types.ToolsListResponse).Tools {
+		tools[tool.Name] = tool
+	}
+
+	tests := []struct {
+		name     string
+		readOnly bool
+	}{
+		{"search_teaching_materials", true},
+		{"get_material_detail", true},
+		{"generate_lesson_plan", false},
+		{"generate_exercises", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := tools[tt.name].Annotations
+			if annotations == nil || annotations.Title == "" || annotations.ReadOnlyHint == nil {
+				t.Fatalf("annotations = %+v", annotations)
+			}
+			if *annotations.ReadOnlyHint != tt.readOnly {
+				t.Fatalf("readOnlyHint = %v, want %v", *annotations.ReadOnlyHint, tt.readOnly)
+			}
+		})
+	}
+}
+
+func TestToolsCallValidatesArguments(t *testing.T) {
+	s := newEducationServer(t)
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		wantField string
+	}{
+		{"missing required", map[string]interface{}{"exercise_type": "quiz"}, "material_id"},
+		{"invalid enum", map[string]interface{}{"material_id": "m1", "exercise_type": "essay"}, "exercise_type"},
+		{"out of range", map[string]interface{}{"material_id": "m1", "exercise_type": "quiz", "count": 50}, "count"},
+		{"unknown field", map[string]interface{}{"material_id": "m1", "exercise_type": "quiz", "answer": true}, "answer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := handle(t, s, context.Background(), 1, types.MCPMethodToolsCall, map[string]interface{}{
+				"name": "generate_exercises", "arguments": tt.arguments,
+			})
+			if code := errorCode(response); code != types.MCPInvalidParams {
+				t.Fatalf("error code = %d, want %d (%+v)", code, types.MCPInvalidParams, response.Error)
+			}
+			errs, _ := response.Error.Data.(mcp.SchemaErrors)
+			if len(errs) != 1 || errs[0].Path != tt.wantField {
+				t.Fatalf("error data = %#v, want single error on %s", response.Error.Data, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestPromptsGet(t *testing.T) {
+	s := newEducationServer(t)
+
+	tests := []struct {
+		name     string
+		params   map[string]interface{}
+		wantCode int
+		wantText string
+	}{
+		{
+			name:     "unknown prompt",
+			params:   map[string]interface{}{"name": "missing"},
+			wantCode: types.MCPInvalidParams,
+		},
+		{
+			name:     "missing required argument",
+			params:   map[string]interface{}{"name": "build_5e_lesson", "arguments": map[string]string{"topic": "浮力", "grade": "grade_8"}},
+			wantCode: types.MCPInvalidParams,
+		},
+		{
+			name:     "invalid enum value",
+			params:   map[string]interface{}{"name": "explain_knowledge_point", "arguments": map[string]string{"knowledge_point": "勾股定理", "grade": "grade_13"}},
+			wantCode: types.MCPInvalidParams,
+		},
+		{
+			name:     "default applied",
+			params:   map[string]interface{}{"name": "build_5e_lesson", "arguments": map[string]string{"topic": "浮力", "grade": "grade_8", "subject": "physics"}},
+			wantText: "45分钟",
+		},
+		{
+			name:     "explain knowledge point",
+			params:   map[string]interface{}{"name": "explain_knowledge_point", "arguments": map[string]string{"knowledge_point": "勾股定理", "grade": "grade_8"}},
+			wantText: "勾股定理",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := handle(t, s, context.Background(), 1, types.MCPMethodPromptsGet, tt.params)
+			if code := errorCode(response); code != tt.wantCode {
+				t.Fatalf("error code = %d, want %d (%+v)", code, tt.wantCode, response.Error)
+			}
+			if tt.wantCode != 0 {
+				return
+			}
+
+			result, ok := response.Result.(*types.PromptsGetResponse)
+			if !ok {
+				t.Fatalf("result type = %T", response.Result)
+			}
+			if len(result.Messages) != 1 || !strings.Contains(result.Messages[0].Content.Text, tt.wantText) {
+				t.Fatalf("messages = %+v, want text containing %q", result.Messages, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestPromptsList(t *testing.T) {
+	s := newEducationServer(t)
+
+	response := handle(t, s, context.Background(), 1, types.MCPMethodPromptsList, nil)
+	result, ok := response.Result.(*types.PromptsListResponse)
+	if !ok {
+		t.Fatalf("result type = %T", response.Result)
+	}
+
+	required := make(map[string][]string)
+	for _, prompt := range result.Prompts {
+		for _, arg := range prompt.Arguments {
+			if arg.Required {
+				required[prompt.Name] = append(required[prompt.Name], arg.Name)
+			}
+		}
+	}
+	if got := strings.Join(required["build_5e_lesson"], ","); got != "topic,grade,subject" {
+		t.Fatalf("build_5e_lesson required = %s", got)
+	}
+	if got := strings.Join(required["explain_knowledge_point"], ","); got != "knowledge_point,grade" {
+		t.Fatalf("explain_knowledge_point required = %s", got)
+	}
+}
+
+func TestResourcesReadTemplate(t *testing.T) {
+	s := newEducationServer(t)
+
+	tests := []struct {
+		uri      string
+		wantCode int
+	}{
+		{"curriculum://grade-1/math", 0},
+		{"curriculum://grade-5/subject-math", 0},
+		{"curriculum://grade-13/subject-math", types.MCPInvalidParams},
+		{"knowledge-graph://subject-physics/level-junior", 0},
+		{"knowledge-graph://subject-physics/level-college", types.MCPInvalidParams},
+		{"unknown://resource", types.MCPInvalidParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			response := handle(t, s, context.Background(), 1, types.MCPMethodResourcesRead, map[string]string{"uri": tt.uri})
+			if code := errorCode(response); code != tt.wantCode {
+				t.Fatalf("error code = %d, want %d (%+v)", code, tt.wantCode, response.Error)
+			}
+		})
+	}
+}
+
+func TestSamplingFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"not supported", mcp.ErrSamplingNotSupported, "教案生成失败：客户端未开启sampling能力"},
+		{"rejected", &mcp.SamplingError{Message: "user declined"}, "教案生成失败：客户端拒绝了生成请求：user declined"},
+		{"invalid output", mcp.ErrSamplingInvalidOutput, "教案生成失败：模型多次未返回符合格式要求的内容"},
+		{"other", errors.New("session closed"), "教案生成失败：session closed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var toolErr *mcp.ToolError
+			if err := samplingFailure("教案生成", tt.err); !errors.As(err, &toolErr) {
+				t.Fatalf("samplingFailure = %v, want ToolError", err)
+			}
+			if !strings.HasPrefix(toolErr.Message, tt.want) {
+				t.Fatalf("message = %q, want prefix %q", toolErr.Message, tt.want)
+			}
+			if result := toolErr.Result(); !result.IsError || len(result.Content) != 1 {
+				t.Fatalf("failure result = %+v", result)
+			}
+		})
+	}
+}
+
+func TestGetMaterialDetail(t *testing.T) {
+	existing := &types.TeachingMaterial{
+		ID:          uuid.New(),
+		Title:       "勾股定理",
+		Type:        types.MaterialTypeVideo,
+		GradeLevels: []types.GradeLevel{types.GradeLevel8},
+		Subject:     types.SubjectMath,
+		Permissions: types.MaterialPermissions{AccessLevel: "public"},
+	}
+	s := newEducationServer(t, existing)
+
+	tests := []struct {
+		name        string
+		materialID  string
+		wantIsError bool
+		wantText    string
+	}{
+		{"existing material", existing.ID.String(), false, existing.Title},
+		{"invalid id", "not-a-uuid", true, "素材ID格式无效：not-a-uuid"},
+		{"unknown material", "6ba7b810-9dad-11d1-80b4-00c04fd430c8", true, "素材不存在或无权访问：6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := mcp.ContextWithUserID(context.Background(), uuid.New())
+			response := handle(t, s, ctx, 1, types.MCPMethodToolsCall, map[string]interface{}{
+				"name":      "get_material_detail",
+				"arguments": map[string]string{"material_id": tt.materialID},
+			})
+			// 参数问题以isError结果返回，而不是JSON-RPC内部错误
+			if response.Error != nil {
+				t.Fatalf("unexpected JSON-RPC error: %+v", response.Error)
+			}
+
+			result := response.Result.(*types.ToolsCallResponse)
+			if result.IsError != tt.wantIsError {
+				t.Fatalf("IsError = %v, want %v: %+v", result.IsError, tt.wantIsError, result)
+			}
+			if len(result.Content) == 0 || !strings.Contains(result.Content[0].Text, tt.wantText) {
+				t.Fatalf("content = %+v, want text containing %q", result.Content, tt.wantText)
+			}
+		})
+	}
+}
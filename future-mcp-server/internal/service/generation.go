@@ -0,0 +1,278 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+)
+
+// 生成类工具的采样参数
+const (
+	lessonPlanMaxTokens = 4000
+	exercisesMaxTokens  = 3000
+
+	defaultLessonDuration = 45
+	defaultExerciseCount  = 5
+	maxExerciseCount      = 20
+)
+
+// lessonPlanPhases 教案步骤允许的阶段
+var lessonPlanPhases = []string{"introduction", "development", "closure"}
+
+// generationModelPreferences 生成类工具偏向能力更强的模型
+func generationModelPreferences() *types.ModelPreferences {
+	intelligence, speed := 0.8, 0.3
+	return &types.ModelPreferences{
+		IntelligencePriority: &intelligence,
+		SpeedPriority:        &speed,
+	}
+}
+
+// lessonPlanSystemPrompt 教案生成的系统提示，约定输出结构与types.LessonPlanResponse一致
+const lessonPlanSystemPrompt = `你是学而思教研团队的资深教研员，负责依据给定的教学素材编写教案。
+只输出一个JSON对象，不要输出任何解释或Markdown，结构如下：
+{
+  "title": "教案标题",
+  "subject": "学科代码，如math",
+  "objectives": ["教学目标"],
+  "materials": [{"id": "引用的素材ID", "title": "素材标题", "type": "素材类型", "purpose": "使用目的", "timing": "使用环节"}],
+  "procedure": [{"phase": "introduction|development|closure", "title": "环节名称", "description": "环节说明", "timing": 5, "materials": ["素材ID"], "activities": ["师生活动"]}],
+  "assessment": {"formative": ["形成性评价"], "summative": ["总结性评价"], "rubric": [{"criteria": "评价维度", "levels": ["优秀", "良好", "待提高"], "points": 10}]},
+  "differentiation": {"support": ["对学困生的支持策略"], "extension": ["对学优生的延伸活动"], "grouping": "分组策略"},
+  "standards": ["对应的课程标准条目"]
+}
+要求：procedure中各环节timing为分钟数，总和不超过课时长度；materials只能引用提供的素材ID。`
+
+// exercisesSystemPrompt 练习题生成的系统提示，约定输出结构与types.GenerateExercisesResponse一致
+const exercisesSystemPrompt = `你是学而思教研团队的资深命题老师，负责依据给定的教学素材编写练习题。
+只输出一个JSON对象，不要输出任何解释或Markdown，结构如下：
+{
+  "exercises": [{"question": "题干", "options": ["选择题选项，其他题型省略"], "answer": "答案", "explanation": "解析", "knowledge_points": ["考查的知识点"], "difficulty": "easy|medium|hard|challenge"}]
+}
+要求：题目紧扣素材内容，难度与题型符合要求，答案准确，解析说明解题思路。`
+
+// lessonPlanInput 教案生成工具参数
+type lessonPlanInput struct {
+	MaterialIDs  []string `json:"material_ids" description:"素材ID列表" jsonschema:"required,minItems=1"`
+	Objectives   []string `json:"objectives" description:"教学目标" jsonschema:"required,minItems=1"`
+	Grade        string   `json:"grade" description:"年级" jsonschema:"required,enum=grade_1|grade_2|grade_3|grade_4|grade_5|grade_6|grade_7|grade_8|grade_9|grade_10|grade_11|grade_12"`
+	StudentLevel string   `json:"student_level,omitempty" description:"学生水平" jsonschema:"enum=beginner|intermediate|advanced"`
+	Duration     int      `json:"duration,omitempty" description:"教学时长（分钟）" jsonschema:"minimum=1,default=45"`
+}
+
+// exercisesInput 练习题生成工具参数
+type exercisesInput struct {
+	MaterialID      string   `json:"material_id" description:"基于的素材ID" jsonschema:"required,minLength=1"`
+	ExerciseType    string   `json:"exercise_type" description:"练习题类型" jsonschema:"required,enum=practice|homework|quiz|olympic|competition"`
+	Difficulty      string   `json:"difficulty,omitempty" description:"难度级别" jsonschema:"enum=easy|medium|hard|challenge"`
+	KnowledgePoints []string `json:"knowledge_points,omitempty" description:"涉及的知识点"`
+	Count           int      `json:"count,omitempty" description:"生成题目数量" jsonschema:"minimum=1,maximum=20,default=5"`
+}
+
+// handleGenerateLessonPlan 生成教案：检索素材后通过sampling请求客户端LLM撰写，校验结构后返回
+func (m *EducationModule) handleGenerateLessonPlan(ctx *types.ToolContext, params *lessonPlanInput) (*types.LessonPlanResponse, error) {
+	if params.Duration <= 0 {
+		params.Duration = defaultLessonDuration
+	}
+
+	if !mcp.SupportsSampling(ctx) {
+		return nil, samplingFailure("教案生成", mcp.ErrSamplingNotSupported)
+	}
+
+	ctx.ReportProgress(0, 3, "正在加载教学素材")
+	materials, err := m.loadPromptMaterials(ctx, params.MaterialIDs, types.SearchMaterialsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "请为%s学生编写一份%d分钟的教案。\n", gradeDisplayName(params.Grade), params.Duration)
+	if params.StudentLevel != "" {
+		fmt.Fprintf(&prompt, "学生水平：%s\n", params.StudentLevel)
+	}
+	fmt.Fprintf(&prompt, "教学目标：\n- %s\n", strings.Join(params.Objectives, "\n- "))
+	writePromptMaterials(&prompt, materials)
+
+	ctx.ReportProgress(1, 3, "正在请求客户端模型生成教案")
+	plan, err := mcp.SampleJSON(ctx, &types.CreateMessageRequest{
+		Messages: []types.SamplingMessage{
+			{Role: "user", Content: types.Content{Type: "text", Text: prompt.String()}},
+		},
+		SystemPrompt:     lessonPlanSystemPrompt,
+		ModelPreferences: generationModelPreferences(),
+		IncludeContext:   "none",
+		MaxTokens:        lessonPlanMaxTokens,
+	}, func(plan *types.LessonPlanResponse) error {
+		return validateLessonPlan(plan, params.Duration, materials)
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, samplingFailure("教案生成", err)
+	}
+
+	// 以请求参数为准，不信任模型回填的年级与时长
+	ctx.ReportProgress(2, 3, "正在整理教案")
+	plan.Grade = types.GradeLevel(params.Grade)
+	plan.Duration = params.Duration
+	plan.GeneratedAt = time.Now()
+	if plan.Subject == "" && len(materials) > 0 {
+		plan.Subject = materials[0].Subject
+	}
+
+	return plan, nil
+}
+
+// handleGenerateExercises 生成练习题：基于素材通过sampling请求客户端LLM命题，校验题目数量与完整性后返回
+func (m *EducationModule) handleGenerateExercises(ctx *types.ToolContext, params *exercisesInput) (*types.GenerateExercisesResponse, error) {
+	if params.Count <= 0 {
+		params.Count = defaultExerciseCount
+	}
+	if params.Count > maxExerciseCount {
+		params.Count = maxExerciseCount
+	}
+	if params.Difficulty == "" {
+		params.Difficulty = "medium"
+	}
+
+	if !mcp.SupportsSampling(ctx) {
+		return nil, samplingFailure("练习题生成", mcp.ErrSamplingNotSupported)
+	}
+
+	ctx.ReportProgress(0, 3, "正在加载教学素材")
+	materials, err := m.loadPromptMaterials(ctx, []string{params.MaterialID}, types.SearchMaterialsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "请编写%d道%s类型的练习题，难度为%s。\n", params.Count, params.ExerciseType, params.Difficulty)
+	if len(params.KnowledgePoints) > 0 {
+		fmt.Fprintf(&prompt, "考查知识点：%s\n", strings.Join(params.KnowledgePoints, "、"))
+	}
+	writePromptMaterials(&prompt, materials)
+
+	ctx.ReportProgress(1, 3, "正在请求客户端模型生成练习题")
+	result, err := mcp.SampleJSON(ctx, &types.CreateMessageRequest{
+		Messages: []types.SamplingMessage{
+			{Role: "user", Content: types.Content{Type: "text", Text: prompt.String()}},
+		},
+		SystemPrompt:     exercisesSystemPrompt,
+		ModelPreferences: generationModelPreferences(),
+		IncludeContext:   "none",
+		MaxTokens:        exercisesMaxTokens,
+	}, func(result *types.GenerateExercisesResponse) error {
+		return validateExercises(result, params.Count)
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, samplingFailure("练习题生成", err)
+	}
+
+	ctx.ReportProgress(2, 3, "正在整理练习题")
+	if len(materials) > 0 {
+		result.MaterialID = materials[0].ID
+	}
+	result.ExerciseType = params.ExerciseType
+	result.Difficulty = params.Difficulty
+	result.GeneratedAt = time.Now()
+	for i := range result.Exercises {
+		if result.Exercises[i].Difficulty == "" {
+			result.Exercises[i].Difficulty = params.Difficulty
+		}
+	}
+
+	return result, nil
+}
+
+// validateLessonPlan 校验模型生成的教案结构，错误信息会反馈给模型用于修正
+func validateLessonPlan(plan *types.LessonPlanResponse, duration int, materials []types.TeachingMaterial) error {
+	if strings.TrimSpace(plan.Title) == "" {
+		return errors.New("title is required")
+	}
+	if len(plan.Objectives) == 0 {
+		return errors.New("objectives must not be empty")
+	}
+	if len(plan.Procedure) == 0 {
+		return errors.New("procedure must not be empty")
+	}
+
+	total := 0
+	for i, step := range plan.Procedure {
+		if !containsString(lessonPlanPhases, step.Phase) {
+			return fmt.Errorf("procedure[%d].phase must be one of %s", i, strings.Join(lessonPlanPhases, "/"))
+		}
+		if step.Timing <= 0 {
+			return fmt.Errorf("procedure[%d].timing must be a positive number of minutes", i)
+		}
+		total += step.Timing
+	}
+	if total > duration {
+		return fmt.Errorf("total procedure timing %d exceeds lesson duration %d", total, duration)
+	}
+
+	if len(plan.Assessment.Formative) == 0 && len(plan.Assessment.Summative) == 0 {
+		return errors.New("assessment must contain formative or summative items")
+	}
+
+	// 只允许引用检索到的素材，避免模型虚构素材
+	for i, material := range plan.Materials {
+		if !containsMaterial(materials, material.ID.String()) {
+			return fmt.Errorf("materials[%d].id %s is not one of the provided materials", i, material.ID)
+		}
+	}
+	return nil
+}
+
+// validateExercises 校验模型生成的练习题，错误信息会反馈给模型用于修正
+func validateExercises(result *types.GenerateExercisesResponse, count int) error {
+	if len(result.Exercises) != count {
+		return fmt.Errorf("expected %d exercises, got %d", count, len(result.Exercises))
+	}
+	for i, exercise := range result.Exercises {
+		switch {
+		case strings.TrimSpace(exercise.Question) == "":
+			return fmt.Errorf("exercises[%d].question is required", i)
+		case strings.TrimSpace(exercise.Answer) == "":
+			return fmt.Errorf("exercises[%d].answer is required", i)
+		case strings.TrimSpace(exercise.Explanation) == "":
+			return fmt.Errorf("exercises[%d].explanation is required", i)
+		}
+	}
+	return nil
+}
+
+// containsMaterial 检查素材列表是否包含指定ID
+func containsMaterial(materials []types.TeachingMaterial, id string) bool {
+	for _, material := range materials {
+		if material.ID.String() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// samplingFailure 将采样失败转换为工具错误，以isError结果返回便于调用方理解失败原因
+func samplingFailure(action string, err error) error {
+	var reason string
+	var rejected *mcp.SamplingError
+	switch {
+	case errors.Is(err, mcp.ErrSamplingNotSupported):
+		reason = "客户端未开启sampling能力，请在支持sampling的客户端中使用"
+	case errors.As(err, &rejected):
+		reason = "客户端拒绝了生成请求：" + rejected.Message
+	case errors.Is(err, mcp.ErrSamplingInvalidOutput):
+		reason = "模型多次未返回符合格式要求的内容，请稍后重试"
+	default:
+		reason = err.Error()
+	}
+
+	return &mcp.ToolError{Message: fmt.Sprintf("%s失败：%s", action, reason)}
+}
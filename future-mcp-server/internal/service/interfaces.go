@@ -24,6 +24,9 @@ type MaterialService interface {
 
 	// 推荐相关
 	GetPersonalizedRecommendations(userID uuid.UUID, limit int) (*types.RecommendationResult, error)
+
+	// InvalidateCache 素材变更后清除指定素材的详情缓存并使全部检索缓存失效
+	InvalidateCache(materialIDs ...uuid.UUID)
 }
 
 // ToolService 工具服务接口
@@ -2,11 +2,13 @@ package service
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/future-mcp/future-mcp-server/internal/repository"
 	"github.com/future-mcp/future-mcp-server/internal/types"
 	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
 	"github.com/google/uuid"
 )
 
@@ -14,13 +16,23 @@ import (
 type MaterialServiceImpl struct {
 	materialRepo repository.MaterialRepository
 	cache        CacheService
+	cursors      *mcp.CursorCodec
+	// searchVersion 检索缓存版本，写入缓存键，素材变更时递增使旧结果不再命中
+	searchVersion atomic.Uint64
 }
 
-// NewMaterialService 创建素材服务
-func NewMaterialService(materialRepo repository.MaterialRepository, cache CacheService) MaterialService {
+// materialSearchCursorScope 素材搜索游标范围
+const materialSearchCursorScope = "materials/search"
+
+// NewMaterialService 创建素材服务，cursors为nil时使用随机密钥的游标编解码器
+func NewMaterialService(materialRepo repository.MaterialRepository, cache CacheService, cursors *mcp.CursorCodec) MaterialService {
+	if cursors == nil {
+		cursors = mcp.NewCursorCodec("")
+	}
 	return &MaterialServiceImpl{
 		materialRepo: materialRepo,
 		cache:        cache,
+		cursors:      cursors,
 	}
 }
 
@@ -32,8 +44,24 @@ func (s *MaterialServiceImpl) SearchMaterials(userID uuid.UUID, req types.Search
 		logger.Any("grade", req.Grade),
 		logger.Any("subject", req.Subject))
 
-	// 尝试从缓存获取
-	cacheKey := fmt.Sprintf("search:%s:%v:%s:%d", req.Query, req.Grade, req.Subject, req.Pagination.Page)
+	// 私有素材在仓库查询中按用户过滤，保证分页与总数只计入可见素材
+	req.ViewerID = userID
+
+	// 游标分页：游标绑定查询条件，解码后换算为页码
+	filter := materialSearchFilter(req)
+	if req.Cursor != "" {
+		cursor, err := s.cursors.Decode(req.Cursor, materialSearchCursorScope, filter)
+		if err != nil || cursor.Limit == 0 {
+			return nil, mcp.ErrInvalidCursor
+		}
+		req.Pagination = types.PaginationRequest{
+			Page:     cursor.Offset/cursor.Limit + 1,
+			PageSize: cursor.Limit,
+		}
+	}
+
+	// 尝试从缓存获取，结果随查询条件、可见范围与分页变化
+	cacheKey := fmt.Sprintf("search:%d:%s:%s:%d:%d", s.searchVersion.Load(), filter, userID, req.Pagination.Page, req.Pagination.PageSize)
 	if cached, err := s.cache.GetSearchCache(cacheKey, nil); err == nil && cached != nil {
 		logger.Info("Search result from cache", logger.Any("cache_key", cacheKey))
 		return &types.SearchMaterialsResponse{
@@ -42,6 +70,7 @@ func (s *MaterialServiceImpl) SearchMaterials(userID uuid.UUID, req types.Search
 			TotalCount:  cached.TotalCount,
 			SearchTime:  cached.SearchTime,
 			Query:       req.Query,
+			NextCursor:  s.nextSearchCursor(req, filter, cached.TotalCount),
 		}, nil
 	}
 
@@ -59,6 +88,7 @@ func (s *MaterialServiceImpl) SearchMaterials(userID uuid.UUID, req types.Search
 		TotalCount:  total,
 		SearchTime:  0.1, // 模拟搜索时间
 		Query:       req.Query,
+		NextCursor:  s.nextSearchCursor(req, filter, total),
 	}
 
 	// 缓存结果
@@ -74,6 +104,18 @@ func (s *MaterialServiceImpl) SearchMaterials(userID uuid.UUID, req types.Search
 	return response, nil
 }
 
+// InvalidateCache 清除素材详情缓存，并递增检索缓存版本（检索结果可能包含任意素材，无法逐条清除）
+func (s *MaterialServiceImpl) InvalidateCache(materialIDs ...uuid.UUID) {
+	s.searchVersion.Add(1)
+	for _, id := range materialIDs {
+		if err := s.cache.DeleteMaterialCache(id.String()); err != nil {
+			logger.Warn("Failed to delete material cache",
+				logger.Any("material_id", id),
+				logger.Any("error", err))
+		}
+	}
+}
+
 // SearchByGradeSubject 按年级学科搜索
 func (s *MaterialServiceImpl) SearchByGradeSubject(userID uuid.UUID, grade types.GradeLevel, subject types.Subject, difficulty types.Difficulty, teachingStage string) (*types.SearchMaterialsResponse, error) {
 	logger.Info("Searching materials by grade and subject",
@@ -257,6 +299,31 @@ func (s *MaterialServiceImpl) GetPersonalizedRecommendations(userID uuid.UUID, l
 }
 
 // 辅助方法
+// nextSearchCursor 生成下一页游标，未分页或已是最后一页时为空
+func (s *MaterialServiceImpl) nextSearchCursor(req types.SearchMaterialsRequest, filter string, total int64) string {
+	page, pageSize := req.Pagination.Page, req.Pagination.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 || int64(page*pageSize) >= total {
+		return ""
+	}
+
+	return s.cursors.Encode(mcp.Cursor{
+		Scope:  materialSearchCursorScope,
+		Filter: filter,
+		Offset: page * pageSize,
+		Limit:  pageSize,
+	})
+}
+
+// materialSearchFilter 素材搜索条件摘要，分页参数不参与计算
+func materialSearchFilter(req types.SearchMaterialsRequest) string {
+	req.Pagination = types.PaginationRequest{}
+	req.Cursor = ""
+	return mcp.CursorFilter(req)
+}
+
 func (s *MaterialServiceImpl) buildPaginationResponse(req types.PaginationRequest, total int64) types.PaginationResponse {
 	pageSize := req.PageSize
 	if pageSize == 0 {
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/future-mcp/future-mcp-server/internal/repository"
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// seedMaterials 写入public个公开素材与private个仅授权给owner的私有素材
+func seedMaterials(t *testing.T, repo repository.MaterialRepository, public, private int, owner uuid.UUID) {
+	t.Helper()
+
+	for i := 0; i < public+private; i++ {
+		material := &types.TeachingMaterial{
+			ID:          uuid.New(),
+			Title:       "分页测试素材",
+			Type:        types.MaterialTypePDF,
+			Permissions: types.MaterialPermissions{AccessLevel: "public"},
+		}
+		// 私有素材与公开素材交错，分页后再过滤会造成页内缺项
+		if i%2 == 0 && i/2 < private {
+			material.Permissions = types.MaterialPermissions{AccessLevel: "private", AllowedUsers: types.UUIDArray{owner}}
+		}
+		if err := repo.CreateMaterial(material); err != nil {
+			t.Fatalf("CreateMaterial: %v", err)
+		}
+	}
+}
+
+func TestSearchMaterialsFiltersPrivateBeforePaging(t *testing.T) {
+	owner := uuid.New()
+	repo := repository.NewMemoryMaterialRepository()
+	seedMaterials(t, repo, 3, 2, owner)
+	service := NewMaterialService(repo, NewMemoryCacheService(), nil)
+
+	tests := []struct {
+		name      string
+		userID    uuid.UUID
+		wantTotal int64
+	}{
+		{"other user sees only public", uuid.New(), 3},
+		{"anonymous sees only public", uuid.Nil, 3},
+		{"owner sees private", owner, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := types.SearchMaterialsRequest{Query: "分页测试", Pagination: types.PaginationRequest{Page: 1, PageSize: 2}}
+			var seen int64
+			for page := 0; ; page++ {
+				result, err := service.SearchMaterials(tt.userID, req)
+				if err != nil {
+					t.Fatalf("SearchMaterials: %v", err)
+				}
+				if result.TotalCount != tt.wantTotal {
+					t.Fatalf("TotalCount = %d, want %d", result.TotalCount, tt.wantTotal)
+				}
+				// 除最后一页外每页都是满的
+				if result.NextCursor != "" && len(result.Materials) != 2 {
+					t.Fatalf("page %d has %d materials, want 2", page, len(result.Materials))
+				}
+				for _, material := range result.Materials {
+					if !materialAccessible(tt.userID, &material) {
+						t.Fatalf("page %d contains inaccessible material %s", page, material.ID)
+					}
+				}
+				seen += int64(len(result.Materials))
+				if result.NextCursor == "" {
+					break
+				}
+				req.Cursor = result.NextCursor
+			}
+			if seen != tt.wantTotal {
+				t.Fatalf("paged through %d materials, want %d", seen, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestSearchMaterialsCacheKeyIncludesPageSize(t *testing.T) {
+	repo := repository.NewMemoryMaterialRepository()
+	seedMaterials(t, repo, 5, 0, uuid.Nil)
+	service := NewMaterialService(repo, NewMemoryCacheService(), nil)
+	userID := uuid.New()
+
+	for _, pageSize := range []int{2, 4, 2} {
+		result, err := service.SearchMaterials(userID, types.SearchMaterialsRequest{
+			Query:      "分页测试",
+			Pagination: types.PaginationRequest{Page: 1, PageSize: pageSize},
+		})
+		if err != nil {
+			t.Fatalf("SearchMaterials: %v", err)
+		}
+		if len(result.Materials) != pageSize || result.Pagination.PageSize != pageSize {
+			t.Fatalf("page size %d returned %d materials (pagination %+v)", pageSize, len(result.Materials), result.Pagination)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/auth"
+	"github.com/future-mcp/future-mcp-server/internal/repository"
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+	"github.com/google/uuid"
+)
+
+func TestNewMCPServiceToolCacheConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttls    map[string]time.Duration
+		wantErr string
+	}{
+		{"read-only tool", map[string]time.Duration{"get_material_detail": time.Minute}, ""},
+		{"unknown tool", map[string]time.Duration{"missing": time.Minute}, "unknown tool missing"},
+		{"generation tool", map[string]time.Duration{"generate_exercises": time.Minute}, "not read-only and idempotent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMCPService(&MCPServiceConfig{Cache: NewMemoryCacheService(), ToolCacheTTLs: tt.ttls})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestToolCacheInvalidatedByMaterialChanges(t *testing.T) {
+	material := &types.TeachingMaterial{
+		ID:          uuid.New(),
+		Title:       "一元二次方程",
+		Type:        types.MaterialTypePDF,
+		Permissions: types.MaterialPermissions{AccessLevel: "public"},
+	}
+	repo := repository.NewObservableMaterialRepository(repository.NewMemoryMaterialRepository())
+	if err := repo.CreateMaterial(material); err != nil {
+		t.Fatalf("CreateMaterial: %v", err)
+	}
+
+	cache := NewMemoryCacheService()
+	server, err := NewMCPService(&MCPServiceConfig{
+		MaterialService: NewMaterialService(repo, cache, nil),
+		Cache:           cache,
+		ToolCacheTTLs:   map[string]time.Duration{"get_material_detail": time.Minute},
+		MaterialEvents:  repo,
+	})
+	if err != nil {
+		t.Fatalf("NewMCPService: %v", err)
+	}
+
+	ctx := mcp.ContextWithUserID(context.Background(), uuid.New())
+	detail := func() *types.ToolsCallResponse {
+		response := handle(t, server, ctx, 1, types.MCPMethodToolsCall, map[string]interface{}{
+			"name":      "get_material_detail",
+			"arguments": map[string]string{"material_id": material.ID.String()},
+		})
+		if response.Error != nil {
+			t.Fatalf("tools/call error: %+v", response.Error)
+		}
+		return response.Result.(*types.ToolsCallResponse)
+	}
+	hit := func(result *types.ToolsCallResponse) bool {
+		meta, _ := result.Meta["cache"].(map[string]interface{})
+		return meta["hit"] == true
+	}
+
+	if result := detail(); hit(result) {
+		t.Fatal("first call should not hit the cache")
+	}
+	if result := detail(); !hit(result) {
+		t.Fatal("second call should hit the cache")
+	}
+
+	// 素材更新后缓存失效，重新执行的工具读到新数据
+	updated := *material
+	updated.Title = "二次函数"
+	if err := repo.UpdateMaterial(&updated); err != nil {
+		t.Fatalf("UpdateMaterial: %v", err)
+	}
+	result := detail()
+	if hit(result) || !strings.Contains(result.Content[0].Text, "二次函数") {
+		t.Fatalf("result after update = %+v", result)
+	}
+}
+
+func TestSearchCacheInvalidatedByMaterialChanges(t *testing.T) {
+	material := &types.TeachingMaterial{
+		ID:          uuid.New(),
+		Title:       "缓存失效测试",
+		Type:        types.MaterialTypePDF,
+		Permissions: types.MaterialPermissions{AccessLevel: "public"},
+	}
+	repo := repository.NewObservableMaterialRepository(repository.NewMemoryMaterialRepository())
+	if err := repo.CreateMaterial(material); err != nil {
+		t.Fatalf("CreateMaterial: %v", err)
+	}
+
+	// 未启用工具结果缓存时，素材服务的检索缓存仍随素材变更失效
+	materialService := NewMaterialService(repo, NewMemoryCacheService(), nil)
+	if _, err := NewMCPService(&MCPServiceConfig{
+		MaterialService: materialService,
+		MaterialEvents:  repo,
+	}); err != nil {
+		t.Fatalf("NewMCPService: %v", err)
+	}
+
+	userID := uuid.New()
+	search := func() []types.TeachingMaterial {
+		result, err := materialService.SearchMaterials(userID, types.SearchMaterialsRequest{
+			Query:      "缓存失效测试",
+			Pagination: types.PaginationRequest{Page: 1, PageSize: 10},
+		})
+		if err != nil {
+			t.Fatalf("SearchMaterials: %v", err)
+		}
+		return result.Materials
+	}
+
+	if materials := search(); len(materials) != 1 {
+		t.Fatalf("materials = %+v, want 1", materials)
+	}
+
+	updated := *material
+	updated.Title = "缓存失效测试（修订）"
+	if err := repo.UpdateMaterial(&updated); err != nil {
+		t.Fatalf("UpdateMaterial: %v", err)
+	}
+	if materials := search(); len(materials) != 1 || materials[0].Title != updated.Title {
+		t.Fatalf("materials after update = %+v", materials)
+	}
+}
+
+func TestAnonymousCallsUseGuestConcurrencyLimit(t *testing.T) {
+	server, err := NewMCPService(&MCPServiceConfig{
+		EnforceRoles: true,
+	})
+	if err != nil {
+		t.Fatalf("NewMCPService: %v", err)
+	}
+
+	started := make(chan struct{}, guestConcurrentLimit)
+	release := make(chan struct{})
+	server.Tools().RegisterTool(&types.ToolDefinition{
+		Name:        "slow",
+		InputSchema: map[string]interface{}{"type": "object"},
+		Handler: func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+			started <- struct{}{}
+			<-release
+			return &types.ToolsCallResponse{Content: []types.Content{mcp.TextContent("done")}}, nil
+		},
+	})
+	call := func() *types.ToolsCallResponse {
+		response := handle(t, server, context.Background(), 1, types.MCPMethodToolsCall, map[string]interface{}{"name": "slow"})
+		if response.Error != nil {
+			t.Errorf("tools/call error: %+v", response.Error)
+			return nil
+		}
+		return response.Result.(*types.ToolsCallResponse)
+	}
+
+	// 匿名调用共用访客配额，超出上限的调用被拒绝
+	results := make(chan *types.ToolsCallResponse, guestConcurrentLimit)
+	for i := 0; i < guestConcurrentLimit; i++ {
+		go func() { results <- call() }()
+		<-started
+	}
+	if result := call(); result == nil || !result.IsError {
+		t.Fatalf("call over guest limit = %+v, want tool error", result)
+	}
+
+	close(release)
+	for i := 0; i < guestConcurrentLimit; i++ {
+		if result := <-results; result == nil || result.IsError {
+			t.Fatalf("anonymous call %d = %+v", i, result)
+		}
+	}
+}
+
+func TestRoleAccess(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name      string
+		userID    uuid.UUID
+		role      types.UserRole
+		wantGrant bool
+		wantLimit int
+	}{
+		{"teacher", userID, types.UserRoleTeacher, true, userConcurrentLimit},
+		{"student", userID, types.UserRoleStudent, false, userConcurrentLimit},
+		{"authenticated without role", userID, "", false, guestConcurrentLimit},
+		{"anonymous ignores role", uuid.Nil, types.UserRoleAdmin, false, guestConcurrentLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.role != "" {
+				ctx = auth.ContextWithRole(ctx, tt.role)
+			}
+
+			granted, err := roleAccess{}.ValidateUserPermission(ctx, tt.userID, "content", "generate")
+			if err != nil || granted != tt.wantGrant {
+				t.Fatalf("content:generate = %v, %v, want %v", granted, err, tt.wantGrant)
+			}
+			if limit, _ := (roleAccess{}).UserConcurrentLimit(ctx, tt.userID); limit != tt.wantLimit {
+				t.Fatalf("concurrent limit = %d, want %d", limit, tt.wantLimit)
+			}
+		})
+	}
+}
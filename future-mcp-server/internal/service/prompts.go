@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+	"github.com/google/uuid"
+)
+
+// 提示模板参数可选值
+var (
+	promptGradeOptions   = []string{"grade_1", "grade_2", "grade_3", "grade_4", "grade_5", "grade_6", "grade_7", "grade_8", "grade_9", "grade_10", "grade_11", "grade_12"}
+	promptSubjectOptions = []string{"math", "chinese", "english", "physics", "chemistry", "biology"}
+)
+
+// promptMaterialLimit 提示模板中嵌入的素材数量上限
+const promptMaterialLimit = 3
+
+// registerPrompts 注册教学类提示模板
+func (m *EducationModule) registerPrompts(server *mcp.Server) {
+	server.Prompts().RegisterPrompt(&types.PromptDefinition{
+		Name:        "explain_knowledge_point",
+		Description: "面向指定年级讲解知识点，自动引用相关教学素材",
+		Arguments: []types.PromptArgumentDefinition{
+			{Name: "knowledge_point", Description: "知识点名称，如“一元二次方程”", Required: true},
+			{Name: "grade", Description: "年级 (学而思体系)", Required: true, Enum: promptGradeOptions},
+			{Name: "subject", Description: "学科", Enum: promptSubjectOptions},
+			{Name: "material_id", Description: "指定引用的素材ID，缺省时按知识点检索"},
+		},
+		Handler: m.handleExplainKnowledgePointPrompt,
+	})
+
+	server.Prompts().RegisterPrompt(&types.PromptDefinition{
+		Name:        "build_5e_lesson",
+		Description: "基于教学素材按5E教学模式设计一节课",
+		Arguments: []types.PromptArgumentDefinition{
+			{Name: "topic", Description: "课题", Required: true},
+			{Name: "grade", Description: "年级 (学而思体系)", Required: true, Enum: promptGradeOptions},
+			{Name: "subject", Description: "学科", Required: true, Enum: promptSubjectOptions},
+			{Name: "material_ids", Description: "引用的素材ID，多个以逗号分隔，缺省时按课题检索"},
+			{Name: "duration", Description: "课时长度（分钟）", Default: "45"},
+		},
+		Handler: m.handleBuild5ELessonPrompt,
+	})
+}
+
+// handleExplainKnowledgePointPrompt 知识点讲解提示
+func (m *EducationModule) handleExplainKnowledgePointPrompt(ctx context.Context, args map[string]string) (*types.PromptsGetResponse, error) {
+	knowledgePoint := args["knowledge_point"]
+
+	var materialIDs []string
+	if id := args["material_id"]; id != "" {
+		materialIDs = []string{id}
+	}
+
+	materials, err := m.loadPromptMaterials(ctx, materialIDs, types.SearchMaterialsRequest{
+		Query:   knowledgePoint,
+		Grade:   convertToGradeLevels([]string{args["grade"]}),
+		Subject: types.Subject(args["subject"]),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "请面向%s学生讲解知识点“%s”。\n", gradeDisplayName(args["grade"]), knowledgePoint)
+	text.WriteString("要求：\n")
+	text.WriteString("1. 先用生活化的例子引入，再给出准确的定义；\n")
+	text.WriteString("2. 语言符合该年级学生的认知水平，避免超纲术语；\n")
+	text.WriteString("3. 给出1-2道由浅入深的例题及讲解；\n")
+	text.WriteString("4. 指出学生常见的错误和易混淆点。\n")
+	writePromptMaterials(&text, materials)
+
+	return &types.PromptsGetResponse{
+		Description: fmt.Sprintf("讲解知识点：%s", knowledgePoint),
+		Messages: []types.PromptMessage{
+			{
+				Role:    "user",
+				Content: types.Content{Type: "text", Text: text.String()},
+			},
+		},
+	}, nil
+}
+
+// handleBuild5ELessonPrompt 5E教学设计提示
+func (m *EducationModule) handleBuild5ELessonPrompt(ctx context.Context, args map[string]string) (*types.PromptsGetResponse, error) {
+	topic := args["topic"]
+
+	var materialIDs []string
+	for _, id := range strings.Split(args["material_ids"], ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			materialIDs = append(materialIDs, id)
+		}
+	}
+
+	materials, err := m.loadPromptMaterials(ctx, materialIDs, types.SearchMaterialsRequest{
+		Query:   topic,
+		Grade:   convertToGradeLevels([]string{args["grade"]}),
+		Subject: types.Subject(args["subject"]),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "请为%s%s设计一节%s分钟的课，课题为“%s”。\n",
+		gradeDisplayName(args["grade"]), subjectDisplayName(args["subject"]), args["duration"], topic)
+	text.WriteString("请按5E教学模式组织教学环节，并为每个环节给出时长、教师活动、学生活动和设计意图：\n")
+	text.WriteString("1. Engage（引入）：激发兴趣，暴露前概念；\n")
+	text.WriteString("2. Explore（探究）：学生自主或合作探索；\n")
+	text.WriteString("3. Explain（解释）：概念讲解与归纳；\n")
+	text.WriteString("4. Elaborate（迁移）：在新情境中深化应用；\n")
+	text.WriteString("5. Evaluate（评价）：检测学习目标达成情况。\n")
+	writePromptMaterials(&text, materials)
+
+	return &types.PromptsGetResponse{
+		Description: fmt.Sprintf("5E教学设计：%s", topic),
+		Messages: []types.PromptMessage{
+			{
+				Role:    "user",
+				Content: types.Content{Type: "text", Text: text.String()},
+			},
+		},
+	}, nil
+}
+
+// loadPromptMaterials 加载提示模板引用的素材：指定ID时逐个获取，否则按条件检索
+func (m *EducationModule) loadPromptMaterials(ctx context.Context, materialIDs []string, search types.SearchMaterialsRequest) ([]types.TeachingMaterial, error) {
+	if m.materialService == nil {
+		return nil, nil
+	}
+
+	userID := mcp.UserIDFromContext(ctx)
+
+	if len(materialIDs) > 0 {
+		materials := make([]types.TeachingMaterial, 0, len(materialIDs))
+		for _, rawID := range materialIDs {
+			id, err := uuid.Parse(rawID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid material id: %s", rawID)
+			}
+
+			detail, err := m.materialService.GetMaterialDetail(userID, id)
+			if err != nil {
+				return nil, err
+			}
+			materials = append(materials, *detail.TeachingMaterial)
+		}
+		return materials, nil
+	}
+
+	search.Pagination = types.PaginationRequest{
+		Page:     1,
+		PageSize: promptMaterialLimit,
+	}
+	result, err := m.materialService.SearchMaterials(userID, search)
+	if err != nil {
+		return nil, err
+	}
+	return result.Materials, nil
+}
+
+// writePromptMaterials 将素材内容写入提示文本
+func writePromptMaterials(text *strings.Builder, materials []types.TeachingMaterial) {
+	if len(materials) == 0 {
+		return
+	}
+
+	text.WriteString("\n请参考以下教学素材：\n")
+	for i, material := range materials {
+		fmt.Fprintf(text, "\n【素材%d】%s (ID: %s)\n", i+1, material.Title, material.ID)
+		fmt.Fprintf(text, "类型：%s；学科：%s；难度：%s\n", material.Type, subjectDisplayName(string(material.Subject)), material.Difficulty)
+		if len(material.GradeLevels) > 0 {
+			grades := make([]string, len(material.GradeLevels))
+			for j, grade := range material.GradeLevels {
+				grades[j] = gradeDisplayName(string(grade))
+			}
+			fmt.Fprintf(text, "适用年级：%s\n", strings.Join(grades, "、"))
+		}
+		if material.Description != "" {
+			fmt.Fprintf(text, "简介：%s\n", material.Description)
+		}
+		if objectives := material.CurriculumAlignment.Objectives; len(objectives) > 0 {
+			fmt.Fprintf(text, "学习目标：%s\n", strings.Join(objectives, "；"))
+		}
+		if len(material.Tags) > 0 {
+			fmt.Fprintf(text, "标签：%s\n", strings.Join(material.Tags, "、"))
+		}
+	}
+}
+
+// gradeDisplayName 年级显示名称
+func gradeDisplayName(grade string) string {
+	names := map[string]string{
+		"grade_1": "一年级", "grade_2": "二年级", "grade_3": "三年级",
+		"grade_4": "四年级", "grade_5": "五年级", "grade_6": "六年级",
+		"grade_7": "初一", "grade_8": "初二", "grade_9": "初三",
+		"grade_10": "高一", "grade_11": "高二", "grade_12": "高三",
+	}
+	if name, ok := names[grade]; ok {
+		return name
+	}
+	return grade
+}
+
+// subjectDisplayName 学科显示名称
+func subjectDisplayName(subject string) string {
+	names := map[string]string{
+		"math": "数学", "chinese": "语文", "english": "英语",
+		"physics": "物理", "chemistry": "化学", "biology": "生物",
+		"history": "历史", "geography": "地理", "politics": "政治",
+	}
+	if name, ok := names[subject]; ok {
+		return name
+	}
+	return subject
+}
+
+// containsString 检查切片是否包含指定字符串
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+)
+
+// knowledgeGraphLevels 知识图谱学段
+var knowledgeGraphLevels = []string{"elementary", "junior", "senior"}
+
+// knowledgeGraph 知识图谱数据
+type knowledgeGraph struct {
+	nodes []map[string]interface{}
+	edges []map[string]interface{}
+}
+
+// knowledgeGraphs 模拟知识图谱数据，键为"学科/学段"
+var knowledgeGraphs = map[string]knowledgeGraph{
+	"math/elementary": {
+		nodes: []map[string]interface{}{
+			{"id": "addition", "label": "加法", "level": "basic"},
+			{"id": "subtraction", "label": "减法", "level": "basic"},
+			{"id": "multiplication", "label": "乘法", "level": "intermediate"},
+			{"id": "division", "label": "除法", "level": "intermediate"},
+			{"id": "fraction", "label": "分数", "level": "advanced"},
+		},
+		edges: []map[string]interface{}{
+			{"source": "addition", "target": "multiplication", "relation": "prerequisite"},
+			{"source": "subtraction", "target": "division", "relation": "prerequisite"},
+			{"source": "division", "target": "fraction", "relation": "prerequisite"},
+		},
+	},
+	"math/junior": {
+		nodes: []map[string]interface{}{
+			{"id": "rational_numbers", "label": "有理数", "level": "basic"},
+			{"id": "linear_equation", "label": "一元一次方程", "level": "basic"},
+			{"id": "linear_function", "label": "一次函数", "level": "intermediate"},
+			{"id": "quadratic_equation", "label": "一元二次方程", "level": "intermediate"},
+			{"id": "quadratic_function", "label": "二次函数", "level": "advanced"},
+		},
+		edges: []map[string]interface{}{
+			{"source": "rational_numbers", "target": "linear_equation", "relation": "prerequisite"},
+			{"source": "linear_equation", "target": "linear_function", "relation": "prerequisite"},
+			{"source": "linear_equation", "target": "quadratic_equation", "relation": "prerequisite"},
+			{"source": "quadratic_equation", "target": "quadratic_function", "relation": "prerequisite"},
+		},
+	},
+	"physics/junior": {
+		nodes: []map[string]interface{}{
+			{"id": "motion", "label": "机械运动", "level": "basic"},
+			{"id": "force", "label": "力", "level": "basic"},
+			{"id": "pressure", "label": "压强", "level": "intermediate"},
+			{"id": "buoyancy", "label": "浮力", "level": "advanced"},
+		},
+		edges: []map[string]interface{}{
+			{"source": "force", "target": "pressure", "relation": "prerequisite"},
+			{"source": "pressure", "target": "buoyancy", "relation": "prerequisite"},
+		},
+	},
+}
+
+// parseCurriculumGrade 解析课程大纲年级，支持"3"与"grade_3"两种写法
+func parseCurriculumGrade(uri, value string) (int, error) {
+	grade, err := strconv.Atoi(strings.TrimPrefix(value, "grade_"))
+	if err != nil || grade < 1 || grade > 12 {
+		return 0, &mcp.InvalidResourceError{URI: uri, Reason: fmt.Sprintf("grade must be 1-12, got %q", value)}
+	}
+	return grade, nil
+}
+
+// knownSubjects 已知学科
+var knownSubjects = []string{
+	string(types.SubjectMath), string(types.SubjectChinese), string(types.SubjectEnglish),
+	string(types.SubjectPhysics), string(types.SubjectChemistry), string(types.SubjectBiology),
+	string(types.SubjectHistory), string(types.SubjectGeography), string(types.SubjectPolitics),
+}
+
+// curriculumGrades 课程大纲年级取值
+var curriculumGrades = []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}
+
+// isKnownSubject 是否为已知学科
+func isKnownSubject(subject string) bool {
+	return containsString(knownSubjects, subject)
+}
+
+// mathCurriculumOutline 数学课程大纲（模拟数据）
+func mathCurriculumOutline(grade int) ([]string, []string) {
+	switch {
+	case grade <= 2:
+		return []string{"认识数字1-100", "掌握基本加减法", "理解几何图形"},
+			[]string{"数字与运算", "图形与测量", "统计与概率初步"}
+	case grade <= 6:
+		return []string{"掌握四则混合运算", "理解分数与小数", "会计算常见图形的面积与体积"},
+			[]string{"分数与小数", "图形的面积与体积", "简易方程", "统计图表"}
+	case grade <= 9:
+		return []string{"掌握实数运算", "会解方程与不等式", "理解函数概念"},
+			[]string{"有理数与实数", "方程与不等式", "函数初步", "三角形与四边形"}
+	default:
+		return []string{"理解函数的性质", "掌握三角函数与数列", "具备空间想象与解析几何能力"},
+			[]string{"集合与函数", "三角函数", "数列", "立体几何与解析几何"}
+	}
+}
@@ -5,22 +5,23 @@ import (
 	"fmt"
 
 	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
 )
 
 // ToolServiceImpl 工具服务实现
 type ToolServiceImpl struct {
-	mcpService *MCPService
+	mcpService *mcp.Server
 }
 
 // NewToolService 创建工具服务
-func NewToolService(mcpService *MCPService) ToolService {
+func NewToolService(mcpService *mcp.Server) ToolService {
 	return &ToolServiceImpl{
 		mcpService: mcpService,
 	}
 }
 
 // SetMCPService 设置MCP服务引用
-func (s *ToolServiceImpl) SetMCPService(mcpService *MCPService) {
+func (s *ToolServiceImpl) SetMCPService(mcpService *mcp.Server) {
 	s.mcpService = mcpService
 }
 
@@ -45,12 +46,19 @@ func (s *ToolServiceImpl) ExecuteTool(ctx context.Context, toolName string, para
 		return nil, fmt.Errorf("failed to execute tool: %w", err)
 	}
 
+	if response == nil {
+		return nil, fmt.Errorf("tool execution cancelled")
+	}
+
 	if response.Error != nil {
 		return nil, fmt.Errorf("tool execution error: %s", response.Error.Message)
 	}
 
 	// 解析工具调用响应
 	if toolResponse, ok := response.Result.(*types.ToolsCallResponse); ok {
+		if toolResponse.StructuredContent != nil {
+			return toolResponse.StructuredContent, nil
+		}
 		if len(toolResponse.Content) > 0 {
 			return toolResponse.Content[0].Text, nil
 		}
@@ -61,14 +69,16 @@ func (s *ToolServiceImpl) ExecuteTool(ctx context.Context, toolName string, para
 
 // ListAvailableTools 列出可用工具
 func (s *ToolServiceImpl) ListAvailableTools() ([]types.Tool, error) {
-	tools := s.mcpService.toolRegistry.ListTools()
+	tools := s.mcpService.Tools().ListTools()
 	result := make([]types.Tool, 0, len(tools))
 
 	for _, tool := range tools {
 		result = append(result, types.Tool{
-			Name:        tool.Name,
-			Description: tool.Description,
-			InputSchema: tool.InputSchema,
+			Name:         tool.Name,
+			Description:  tool.Description,
+			InputSchema:  tool.InputSchema,
+			OutputSchema: tool.OutputSchema,
+			Annotations:  tool.Annotations,
 		})
 	}
 
@@ -77,7 +87,7 @@ func (s *ToolServiceImpl) ListAvailableTools() ([]types.Tool, error) {
 
 // GetToolDefinition 获取工具定义
 func (s *ToolServiceImpl) GetToolDefinition(toolName string) (*types.ToolDefinition, error) {
-	tool := s.mcpService.toolRegistry.GetTool(toolName)
+	tool := s.mcpService.Tools().GetTool(toolName)
 	if tool == nil {
 		return nil, fmt.Errorf("tool not found: %s", toolName)
 	}
@@ -88,7 +98,7 @@ func (s *ToolServiceImpl) GetToolDefinition(toolName string) (*types.ToolDefinit
 func (s *ToolServiceImpl) GetToolUsageStatistics() (map[string]interface{}, error) {
 	// 简化实现，返回基本统计信息
 	return map[string]interface{}{
-		"total_tools": len(s.mcpService.toolRegistry.ListTools()),
+		"total_tools": len(s.mcpService.Tools().ListTools()),
 		"tools_active": true,
 		"last_updated": "2024-01-01T00:00:00Z",
 	}, nil
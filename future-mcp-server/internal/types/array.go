@@ -0,0 +1,216 @@
+package types
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// errNullArrayElement 数组元素为NULL，Go切片中无法表示
+var errNullArrayElement = errors.New("NULL array element is not supported")
+
+// TextArray PostgreSQL text[]列，元素为字符串或字符串枚举
+// 实现driver.Valuer与sql.Scanner，可直接作为查询参数（如 grade_levels && ?），不会被展开为参数列表
+type TextArray[T ~string] []T
+
+// Value 编码为数组字面量，nil编码为NULL
+func (a TextArray[T]) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	elements := make([]string, len(a))
+	for i, element := range a {
+		elements[i] = string(element)
+	}
+	return formatArrayLiteral(elements), nil
+}
+
+// Scan 解析数组字面量，NULL解析为nil
+func (a *TextArray[T]) Scan(src interface{}) error {
+	elements, err := scanArrayLiteral(src)
+	if err != nil || elements == nil {
+		*a = nil
+		return err
+	}
+
+	result := make(TextArray[T], len(elements))
+	for i, element := range elements {
+		result[i] = T(element)
+	}
+	*a = result
+	return nil
+}
+
+// UUIDArray PostgreSQL uuid[]列
+type UUIDArray []uuid.UUID
+
+// Value 编码为数组字面量，nil编码为NULL
+func (a UUIDArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	elements := make([]string, len(a))
+	for i, id := range a {
+		elements[i] = id.String()
+	}
+	return formatArrayLiteral(elements), nil
+}
+
+// Scan 解析数组字面量，NULL解析为nil
+func (a *UUIDArray) Scan(src interface{}) error {
+	elements, err := scanArrayLiteral(src)
+	if err != nil || elements == nil {
+		*a = nil
+		return err
+	}
+
+	result := make(UUIDArray, len(elements))
+	for i, element := range elements {
+		id, err := uuid.Parse(element)
+		if err != nil {
+			return fmt.Errorf("invalid uuid array element %q: %w", element, err)
+		}
+		result[i] = id
+	}
+	*a = result
+	return nil
+}
+
+// Vector pgvector的vector列，文本格式为[1,2,3]
+type Vector []float32
+
+// Value 编码为vector文本格式，nil编码为NULL
+func (v Vector) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	elements := make([]string, len(v))
+	for i, value := range v {
+		elements[i] = strconv.FormatFloat(float64(value), 'f', -1, 32)
+	}
+	return "[" + strings.Join(elements, ",") + "]", nil
+}
+
+// Scan 解析vector文本格式，NULL解析为nil
+func (v *Vector) Scan(src interface{}) error {
+	text, ok, err := scanText(src)
+	if err != nil || !ok {
+		*v = nil
+		return err
+	}
+
+	if len(text) < 2 || text[0] != '[' || text[len(text)-1] != ']' {
+		return fmt.Errorf("invalid vector %q", text)
+	}
+	result := Vector{}
+	if body := text[1 : len(text)-1]; body != "" {
+		for _, element := range strings.Split(body, ",") {
+			value, err := strconv.ParseFloat(strings.TrimSpace(element), 32)
+			if err != nil {
+				return fmt.Errorf("invalid vector element %q: %w", element, err)
+			}
+			result = append(result, float32(value))
+		}
+	}
+	*v = result
+	return nil
+}
+
+// formatArrayLiteral 编码一维数组字面量，元素一律加引号并转义引号与反斜杠
+func formatArrayLiteral(elements []string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, element := range elements {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('"')
+		for _, r := range element {
+			if r == '"' || r == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		}
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// scanText 取出驱动返回的文本值，NULL时ok为false
+func scanText(src interface{}) (string, bool, error) {
+	switch value := src.(type) {
+	case nil:
+		return "", false, nil
+	case []byte:
+		return string(value), true, nil
+	case string:
+		return value, true, nil
+	default:
+		return "", false, fmt.Errorf("cannot scan %T as text", src)
+	}
+}
+
+// scanArrayLiteral 解析驱动返回的一维数组字面量，NULL时返回nil
+func scanArrayLiteral(src interface{}) ([]string, error) {
+	text, ok, err := scanText(src)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return parseArrayLiteral(text)
+}
+
+// parseArrayLiteral 解析一维数组字面量，如 {a,"b c","d\"e"}；不支持多维数组与NULL元素
+func parseArrayLiteral(literal string) ([]string, error) {
+	if len(literal) < 2 || literal[0] != '{' || literal[len(literal)-1] != '}' {
+		return nil, fmt.Errorf("invalid array literal %q", literal)
+	}
+	body := literal[1 : len(literal)-1]
+	elements := []string{}
+	if body == "" {
+		return elements, nil
+	}
+
+	for i := 0; ; {
+		var element strings.Builder
+		quoted := i < len(body) && body[i] == '"'
+		if quoted {
+			i++
+			for ; i < len(body) && body[i] != '"'; i++ {
+				if body[i] == '\\' {
+					i++
+				}
+				if i < len(body) {
+					element.WriteByte(body[i])
+				}
+			}
+			if i >= len(body) {
+				return nil, fmt.Errorf("unterminated quoted element in array literal %q", literal)
+			}
+			i++
+		} else {
+			for ; i < len(body) && body[i] != ','; i++ {
+				if body[i] == '{' || body[i] == '"' {
+					return nil, fmt.Errorf("unsupported array literal %q", literal)
+				}
+				element.WriteByte(body[i])
+			}
+			if element.String() == "NULL" {
+				return nil, errNullArrayElement
+			}
+		}
+		elements = append(elements, element.String())
+
+		if i == len(body) {
+			return elements, nil
+		}
+		if body[i] != ',' {
+			return nil, fmt.Errorf("invalid array literal %q", literal)
+		}
+		i++
+	}
+}
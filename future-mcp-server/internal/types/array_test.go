@@ -0,0 +1,193 @@
+package types
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTextArrayValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		array TextArray[string]
+		want  interface{}
+	}{
+		{"nil", nil, nil},
+		{"empty", TextArray[string]{}, "{}"},
+		{"plain", TextArray[string]{"grade_7", "grade_8"}, `{"grade_7","grade_8"}`},
+		{"escaped", TextArray[string]{`a "b"`, `c\d`, "e,f", ""}, `{"a \"b\"","c\\d","e,f",""}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.array.Value()
+			if err != nil {
+				t.Fatalf("Value: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("Value = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextArrayScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    TextArray[GradeLevel]
+		wantErr bool
+	}{
+		{"null", nil, nil, false},
+		{"empty", "{}", TextArray[GradeLevel]{}, false},
+		{"bytes", []byte("{grade_7,grade_8}"), TextArray[GradeLevel]{GradeLevel7, GradeLevel8}, false},
+		{"quoted", `{"a \"b\"","c\\d","e,f","",NULLS}`, TextArray[GradeLevel]{`a "b"`, `c\d`, "e,f", "", "NULLS"}, false},
+		{"quoted null", `{"NULL"}`, TextArray[GradeLevel]{"NULL"}, false},
+		{"null element", "{a,NULL}", nil, true},
+		{"multidimensional", "{{a},{b}}", nil, true},
+		{"unterminated", `{"a}`, nil, true},
+		{"not an array", "grade_7", nil, true},
+		{"unsupported type", 7, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got TextArray[GradeLevel]
+			err := got.Scan(tt.src)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Scan(%#v) = %#v, want error", tt.src, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Scan = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextArrayRoundTrip(t *testing.T) {
+	want := TextArray[string]{"一元二次方程", `路径 C:\tmp`, `"引号"`, "{花括号}", " 空格 "}
+	value, err := want.Value()
+	if err != nil {
+		t.Fatalf("Value: %v", err)
+	}
+
+	var got TextArray[string]
+	if err := got.Scan(value); err != nil {
+		t.Fatalf("Scan(%v): %v", value, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("round trip = %#v, want %#v", got, want)
+	}
+}
+
+func TestTextArrayNullElement(t *testing.T) {
+	var got TextArray[string]
+	if err := got.Scan("{NULL}"); !errors.Is(err, errNullArrayElement) {
+		t.Fatalf("err = %v, want errNullArrayElement", err)
+	}
+}
+
+func TestUUIDArray(t *testing.T) {
+	ids := UUIDArray{uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8"), uuid.MustParse("6ba7b811-9dad-11d1-80b4-00c04fd430c8")}
+	value, err := ids.Value()
+	if err != nil {
+		t.Fatalf("Value: %v", err)
+	}
+	want := `{"6ba7b810-9dad-11d1-80b4-00c04fd430c8","6ba7b811-9dad-11d1-80b4-00c04fd430c8"}`
+	if value != want {
+		t.Fatalf("Value = %v, want %s", value, want)
+	}
+
+	// PostgreSQL输出uuid[]时不加引号
+	var got UUIDArray
+	if err := got.Scan("{6ba7b810-9dad-11d1-80b4-00c04fd430c8,6ba7b811-9dad-11d1-80b4-00c04fd430c8}"); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if !reflect.DeepEqual(got, ids) {
+		t.Fatalf("Scan = %v, want %v", got, ids)
+	}
+
+	if err := got.Scan("{not-a-uuid}"); err == nil {
+		t.Fatal("Scan of invalid uuid should fail")
+	}
+}
+
+func TestVector(t *testing.T) {
+	value, err := Vector{0.5, -1, 2.25}.Value()
+	if err != nil {
+		t.Fatalf("Value: %v", err)
+	}
+	if value != "[0.5,-1,2.25]" {
+		t.Fatalf("Value = %v", value)
+	}
+
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    Vector
+		wantErr bool
+	}{
+		{"null", nil, nil, false},
+		{"empty", "[]", Vector{}, false},
+		{"values", []byte("[0.5,-1,2.25]"), Vector{0.5, -1, 2.25}, false},
+		{"invalid element", "[0.5,x]", nil, true},
+		{"not a vector", "{0.5}", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Vector
+			err := got.Scan(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Scan = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONMap(t *testing.T) {
+	value, err := JSONMap[int64]{"grade_7": 3}.Value()
+	if err != nil {
+		t.Fatalf("Value: %v", err)
+	}
+	if value != `{"grade_7":3}` {
+		t.Fatalf("Value = %v", value)
+	}
+	if value, _ := JSONMap[int64](nil).Value(); value != nil {
+		t.Fatalf("Value of nil map = %v, want nil", value)
+	}
+
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    JSONMap[int64]
+		wantErr bool
+	}{
+		{"null", nil, nil, false},
+		{"object", []byte(`{"grade_7":3,"grade_8":5}`), JSONMap[int64]{"grade_7": 3, "grade_8": 5}, false},
+		{"wrong value type", `{"grade_7":"three"}`, nil, true},
+		{"unsupported type", 3, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got JSONMap[int64]
+			err := got.Scan(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Scan = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// JSONMap PostgreSQL jsonb对象列
+// 实现driver.Valuer与sql.Scanner，nil映射对应NULL
+type JSONMap[V any] map[string]V
+
+// Value 编码为JSON，nil编码为NULL
+func (m JSONMap[V]) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(map[string]V(m))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal jsonb value: %w", err)
+	}
+	return string(data), nil
+}
+
+// Scan 解析JSON，NULL解析为nil
+func (m *JSONMap[V]) Scan(src interface{}) error {
+	text, ok, err := scanText(src)
+	if err != nil || !ok {
+		*m = nil
+		return err
+	}
+
+	var result map[string]V
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		return fmt.Errorf("failed to unmarshal jsonb value: %w", err)
+	}
+	*m = result
+	return nil
+}
@@ -78,9 +78,9 @@ type TeachingMaterial struct {
 	Title       string      `json:"title" gorm:"not null;index"`
 	Description string      `json:"description" gorm:"type:text"`
 	Type        MaterialType `json:"type" gorm:"not null"`
-	GradeLevels []GradeLevel `json:"grade_levels" gorm:"type:text[]"`
+	GradeLevels TextArray[GradeLevel] `json:"grade_levels" gorm:"type:text[]"`
 	Subject     Subject     `json:"subject" gorm:"not null;index"`
-	Tags        []string    `json:"tags" gorm:"type:text[]"`
+	Tags        TextArray[string] `json:"tags" gorm:"type:text[]"`
 	Difficulty  Difficulty  `json:"difficulty" gorm:"not null"`
 
 	// 课标对齐信息
@@ -96,7 +96,7 @@ type TeachingMaterial struct {
 	Statistics MaterialStatistics `json:"statistics" gorm:"embedded"`
 
 	// 向量嵌入（用于语义搜索）
-	Embeddings []float32 `json:"embeddings,omitempty" gorm:"type:vector(768)"`
+	Embeddings Vector `json:"embeddings,omitempty" gorm:"type:vector(768)"`
 
 	// 时间戳
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
@@ -106,7 +106,7 @@ type TeachingMaterial struct {
 // CurriculumAlignment 课标对齐信息
 type CurriculumAlignment struct {
 	Standard        string   `json:"standard" gorm:"column:curriculum_standard"` // 课标编号
-	Objectives      []string `json:"objectives" gorm:"type:text[]"`             // 学习目标
+	Objectives      TextArray[string] `json:"objectives" gorm:"type:text[]"`    // 学习目标
 	CompetencyLevel int      `json:"competency_level" gorm:"default:0"`         // 对齐度评分 (0-100)
 }
 
@@ -125,12 +125,12 @@ type MaterialMetadata struct {
 
 // MaterialPermissions 素材权限控制
 type MaterialPermissions struct {
-	AllowedUsage   []UsageType `json:"allowed_usage" gorm:"type:text[]"`
+	AllowedUsage   TextArray[UsageType] `json:"allowed_usage" gorm:"type:text[]"`
 	Licensing      LicenseInfo `json:"licensing" gorm:"embedded"`
-	Restrictions   []string    `json:"restrictions" gorm:"type:text[]"`
+	Restrictions   TextArray[string] `json:"restrictions" gorm:"type:text[]"`
 	AccessLevel    string      `json:"access_level" gorm:"default:'public'"` // public/protected/private
-	AllowedRoles   []string    `json:"allowed_roles" gorm:"type:text[]"`    // 允许的角色
-	AllowedUsers   []uuid.UUID `json:"allowed_users" gorm:"type:uuid[]"`    // 允许的用户ID
+	AllowedRoles   TextArray[string] `json:"allowed_roles" gorm:"type:text[]"`    // 允许的角色
+	AllowedUsers   UUIDArray   `json:"allowed_users" gorm:"type:uuid[]"`    // 允许的用户ID
 }
 
 // LicenseInfo 授权信息
@@ -153,9 +153,9 @@ type MaterialStatistics struct {
 	TotalAccessTime   int64 `json:"total_access_time" gorm:"default:0"` // 总访问时长（秒）
 
 	// 按维度统计
-	UsageByGrade     map[string]int64 `json:"usage_by_grade,omitempty" gorm:"type:jsonb"`
-	UsageBySubject   map[string]int64 `json:"usage_by_subject,omitempty" gorm:"type:jsonb"`
-	UsageByTimeRange map[string]int64 `json:"usage_by_time_range,omitempty" gorm:"type:jsonb"`
+	UsageByGrade     JSONMap[int64] `json:"usage_by_grade,omitempty" gorm:"type:jsonb"`
+	UsageBySubject   JSONMap[int64] `json:"usage_by_subject,omitempty" gorm:"type:jsonb"`
+	UsageByTimeRange JSONMap[int64] `json:"usage_by_time_range,omitempty" gorm:"type:jsonb"`
 }
 
 // SearchMaterialsRequest 搜索素材请求
@@ -169,11 +169,16 @@ type SearchMaterialsRequest struct {
 
 	Pagination PaginationRequest `json:"pagination"`
 	Sort       SortRequest       `json:"sort"`
+	// Cursor 上一页响应的next_cursor，设置时按游标继续分页，忽略Pagination.Page
+	Cursor string `json:"cursor,omitempty" form:"cursor"`
 
 	// 高级搜索选项
 	SemanticSearch bool     `json:"semantic_search,omitempty"` // 是否启用语义搜索
 	MinScore       *float64 `json:"min_score,omitempty"`       // 最小匹配分数
 	Filters        map[string]interface{} `json:"filters,omitempty"` // 自定义过滤器
+
+	// ViewerID 发起检索的用户，私有素材只对授权用户可见，由服务端设置
+	ViewerID uuid.UUID `json:"-" form:"-"`
 }
 
 // PaginationRequest 分页请求
@@ -196,6 +201,7 @@ type SearchMaterialsResponse struct {
 	SearchTime  float64            `json:"search_time"`
 	Query       string             `json:"query"`
 	Suggestions []string           `json:"suggestions,omitempty"`
+	NextCursor  string             `json:"next_cursor,omitempty"`
 }
 
 // PaginationResponse 分页响应
@@ -299,6 +305,25 @@ type LessonPlanResponse struct {
 	GeneratedAt   time.Time             `json:"generated_at"`
 }
 
+// GenerateExercisesResponse 练习题生成响应
+type GenerateExercisesResponse struct {
+	MaterialID   uuid.UUID           `json:"material_id"`
+	ExerciseType string              `json:"exercise_type"`
+	Difficulty   string              `json:"difficulty"`
+	Exercises    []GeneratedExercise `json:"exercises"`
+	GeneratedAt  time.Time           `json:"generated_at"`
+}
+
+// GeneratedExercise 生成的练习题
+type GeneratedExercise struct {
+	Question        string   `json:"question"`
+	Options         []string `json:"options,omitempty"` // 选择题选项，其他题型为空
+	Answer          string   `json:"answer"`
+	Explanation     string   `json:"explanation"`
+	KnowledgePoints []string `json:"knowledge_points"`
+	Difficulty      string   `json:"difficulty"`
+}
+
 // LessonPlanMaterial 教案材料
 type LessonPlanMaterial struct {
 	ID          uuid.UUID `json:"id"`
@@ -310,7 +335,7 @@ type LessonPlanMaterial struct {
 
 // LessonPlanStep 教案步骤
 type LessonPlanStep struct {
-	Phase       string `json:"phase"`       // introduction/development/closure
+	Phase       string `json:"phase" jsonschema:"enum=introduction|development|closure"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Timing      int    `json:"timing"`      // 分钟
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,8 +11,41 @@ import (
 // MCP协议相关类型定义
 // 参考: https://modelcontextprotocol.io/specification
 
-// MCPProtocolVersion 协议版本
-const MCPProtocolVersion = "2024-11-05"
+// MCP协议版本
+const (
+	MCPProtocolVersion20241105 = "2024-11-05"
+	MCPProtocolVersion20250326 = "2025-03-26"
+	MCPProtocolVersion20250618 = "2025-06-18"
+)
+
+// MCPProtocolVersion 服务器支持的最新协议版本
+const MCPProtocolVersion = MCPProtocolVersion20250618
+
+// SupportedProtocolVersions 服务器支持的协议版本，按从新到旧排列
+var SupportedProtocolVersions = []string{
+	MCPProtocolVersion20250618,
+	MCPProtocolVersion20250326,
+	MCPProtocolVersion20241105,
+}
+
+// IsSupportedProtocolVersion 是否支持指定协议版本
+func IsSupportedProtocolVersion(version string) bool {
+	for _, supported := range SupportedProtocolVersions {
+		if supported == version {
+			return true
+		}
+	}
+	return false
+}
+
+// NegotiateProtocolVersion 协商协议版本
+// 支持客户端请求的版本时原样返回，否则返回服务器支持的最新版本，由客户端决定是否继续
+func NegotiateProtocolVersion(requested string) string {
+	if IsSupportedProtocolVersion(requested) {
+		return requested
+	}
+	return MCPProtocolVersion
+}
 
 // MCPMessage MCP消息基础结构
 type MCPMessage struct {
@@ -39,6 +74,39 @@ type MCPNotification struct {
 	Params interface{} `json:"params,omitempty"`
 }
 
+// MCPRawMessage 传输层收到的原始JSON-RPC消息，可能是请求、通知或客户端响应
+type MCPRawMessage struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      interface{} `json:"id,omitempty"`
+	Method  string      `json:"method,omitempty"`
+	Params  interface{} `json:"params,omitempty"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   *MCPError   `json:"error,omitempty"`
+}
+
+// IsResponse 是否为客户端对服务端请求的响应
+func (m *MCPRawMessage) IsResponse() bool {
+	return m.Method == "" && m.ID != nil
+}
+
+// Request 转换为请求
+func (m *MCPRawMessage) Request() *MCPRequest {
+	return &MCPRequest{
+		MCPMessage: MCPMessage{JSONRPC: m.JSONRPC, ID: m.ID},
+		Method:     m.Method,
+		Params:     m.Params,
+	}
+}
+
+// Response 转换为响应
+func (m *MCPRawMessage) Response() *MCPResponse {
+	return &MCPResponse{
+		MCPMessage: MCPMessage{JSONRPC: m.JSONRPC, ID: m.ID},
+		Result:     m.Result,
+		Error:      m.Error,
+	}
+}
+
 // MCPError MCP错误
 type MCPError struct {
 	Code    int         `json:"code"`
@@ -46,6 +114,11 @@ type MCPError struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// Error 实现error接口，便于拦截器以JSON-RPC错误短路请求
+func (e *MCPError) Error() string {
+	return fmt.Sprintf("MCP error %d: %s", e.Code, e.Message)
+}
+
 // MCP错误码
 const (
 	MCPParseError     = -32700
@@ -53,16 +126,22 @@ const (
 	MCPMethodNotFound = -32601
 	MCPInvalidParams  = -32602
 	MCPInternalError  = -32603
+
+	// 服务端自定义错误码
+	MCPServerBusy       = -32000
+	MCPPermissionDenied = -32001
 )
 
 // MCP标准方法
 const (
 	MCPMethodInitialize     = "initialize"
 	MCPMethodInitialized    = "initialized"
+	MCPMethodNotificationsInitialized = "notifications/initialized"
 	MCPMethodToolsList      = "tools/list"
 	MCPMethodToolsCall      = "tools/call"
 	MCPMethodResourcesList  = "resources/list"
 	MCPMethodResourcesRead  = "resources/read"
+	MCPMethodResourcesTemplatesList = "resources/templates/list"
 	MCPMethodResourcesSubscribe = "resources/subscribe"
 	MCPMethodResourcesUnsubscribe = "resources/unsubscribe"
 	MCPMethodPing           = "ping"
@@ -70,6 +149,16 @@ const (
 	MCPMethodProgress       = "notifications/progress"
 	MCPMethodResourcesUpdated = "notifications/resources/updated"
 	MCPMethodToolsChanged   = "notifications/tools/list_changed"
+	MCPMethodResourcesChanged = "notifications/resources/list_changed"
+	MCPMethodPromptsList    = "prompts/list"
+	MCPMethodPromptsGet     = "prompts/get"
+	MCPMethodPromptsChanged = "notifications/prompts/list_changed"
+	MCPMethodLoggingSetLevel = "logging/setLevel"
+	MCPMethodLoggingMessage  = "notifications/message"
+	MCPMethodCancelled      = "notifications/cancelled"
+	MCPMethodCompletionComplete = "completion/complete"
+	MCPMethodSamplingCreateMessage = "sampling/createMessage"
+	MCPMethodElicitationCreate     = "elicitation/create"
 )
 
 // ==================== 初始化相关 ====================
@@ -87,6 +176,11 @@ type ClientCapabilities struct {
 	Resources *ResourcesCapability `json:"resources,omitempty"`
 	Sampling  *SamplingCapability  `json:"sampling,omitempty"`
 	Logging   *LoggingCapability   `json:"logging,omitempty"`
+	Roots     *RootsCapability     `json:"roots,omitempty"`
+	// Elicitation 客户端可向用户展示表单并返回填写结果
+	Elicitation *ElicitationCapability `json:"elicitation,omitempty"`
+
+	Experimental map[string]interface{} `json:"experimental,omitempty"`
 }
 
 // ToolsCapability 工具能力
@@ -108,6 +202,15 @@ type SamplingCapability struct {
 type LoggingCapability struct {
 }
 
+// ElicitationCapability 征询能力
+type ElicitationCapability struct {
+}
+
+// RootsCapability 根目录能力
+type RootsCapability struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
 // ImplementationInfo 实现信息
 type ImplementationInfo struct {
 	Name    string `json:"name" binding:"required"`
@@ -128,6 +231,7 @@ type ServerCapabilities struct {
 	Resources *ServerResourcesCapability `json:"resources,omitempty"`
 	Logging   *ServerLoggingCapability   `json:"logging,omitempty"`
 	Prompts   *ServerPromptsCapability   `json:"prompts,omitempty"`
+	Completions *ServerCompletionsCapability `json:"completions,omitempty"`
 }
 
 // ServerToolsCapability 服务器工具能力
@@ -150,14 +254,21 @@ type ServerPromptsCapability struct {
 	ListChanged bool `json:"listChanged,omitempty"`
 }
 
+// ServerCompletionsCapability 服务器参数补全能力
+type ServerCompletionsCapability struct {
+}
+
 // ==================== 工具相关 ====================
 
 // ToolsListRequest 工具列表请求
-type ToolsListRequest struct{}
+type ToolsListRequest struct {
+	PaginatedRequest
+}
 
 // ToolsListResponse 工具列表响应
 type ToolsListResponse struct {
-	Tools []Tool `json:"tools"`
+	Tools      []Tool `json:"tools"`
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 // Tool 工具定义
@@ -165,36 +276,83 @@ type Tool struct {
 	Name        string      `json:"name"`
 	Description string      `json:"description,omitempty"`
 	InputSchema interface{} `json:"inputSchema"` // JSON Schema
+	// OutputSchema 结构化结果的JSON Schema，声明时工具必须返回符合该Schema的structuredContent
+	OutputSchema interface{} `json:"outputSchema,omitempty"`
+	// Annotations 工具行为提示，客户端可据此自动批准只读工具、对生成类工具请求用户确认
+	Annotations *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations 工具行为提示
+// 仅为提示信息，客户端不应将其作为安全保证；未设置的布尔提示按协议默认值理解
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`    // 不修改任何状态，默认false
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"` // 可能执行破坏性更新，默认true
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`  // 相同参数重复调用无额外影响，默认false
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`   // 与外部实体交互，默认true
 }
 
 // ToolsCallRequest 工具调用请求
 type ToolsCallRequest struct {
-	Name      string      `json:"name" binding:"required"`
-	Arguments interface{} `json:"arguments,omitempty"`
+	Name      string       `json:"name" binding:"required"`
+	Arguments interface{}  `json:"arguments,omitempty"`
+	Meta      *RequestMeta `json:"_meta,omitempty"`
+}
+
+// RequestMeta 请求元数据
+type RequestMeta struct {
+	ProgressToken interface{} `json:"progressToken,omitempty"` // string | number
 }
 
 // ToolsCallResponse 工具调用响应
 type ToolsCallResponse struct {
 	Content []Content `json:"content"`
-	IsError bool      `json:"isError,omitempty"`
+	// StructuredContent 结构化结果，Content中同时保留其JSON文本以兼容不支持结构化结果的客户端
+	StructuredContent interface{} `json:"structuredContent,omitempty"`
+	IsError           bool        `json:"isError,omitempty"`
+	// Meta 结果元数据，如工具异常时的关联ID
+	Meta map[string]interface{} `json:"_meta,omitempty"`
 }
 
+// 内容块类型
+const (
+	ContentTypeText         = "text"
+	ContentTypeImage        = "image"
+	ContentTypeAudio        = "audio"
+	ContentTypeResource     = "resource"
+	ContentTypeResourceLink = "resource_link"
+)
+
 // Content 内容
+// text使用Text；image与audio使用Data与MimeType；resource使用Resource嵌入资源内容；
+// resource_link使用URI、Name指向可通过resources/read读取的资源
 type Content struct {
-	Type string `json:"type" binding:"required,oneof=text image"` // text | image
-	Text string `json:"text,omitempty"`
-	Data string `json:"data,omitempty"` // base64 for images
+	Type     string `json:"type" binding:"required,oneof=text image audio resource resource_link"`
+	Text     string `json:"text,omitempty"`
+	Data     string `json:"data,omitempty"` // base64 for images and audio
 	MimeType string `json:"mimeType,omitempty"`
+
+	// 嵌入资源
+	Resource *ResourceContent `json:"resource,omitempty"`
+
+	// 资源链接
+	URI         string `json:"uri,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Size        int64  `json:"size,omitempty"`
 }
 
 // ==================== 资源相关 ====================
 
 // ResourcesListRequest 资源列表请求
-type ResourcesListRequest struct{}
+type ResourcesListRequest struct {
+	PaginatedRequest
+}
 
 // ResourcesListResponse 资源列表响应
 type ResourcesListResponse struct {
-	Resources []Resource `json:"resources"`
+	Resources  []Resource `json:"resources"`
+	NextCursor string     `json:"nextCursor,omitempty"`
 }
 
 // Resource 资源定义
@@ -205,6 +363,19 @@ type Resource struct {
 	MimeType    string `json:"mimeType,omitempty"`
 }
 
+// ResourcesTemplatesListResponse 资源模板列表响应
+type ResourcesTemplatesListResponse struct {
+	ResourceTemplates []ResourceTemplate `json:"resourceTemplates"`
+}
+
+// ResourceTemplate 资源模板定义
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"` // RFC 6570
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
 // ResourcesReadRequest 资源读取请求
 type ResourcesReadRequest struct {
 	URI string `json:"uri" binding:"required"`
@@ -233,6 +404,215 @@ type ResourcesUnsubscribeRequest struct {
 	URI string `json:"uri" binding:"required"`
 }
 
+// ==================== 提示相关 ====================
+
+// PromptsListRequest 提示列表请求
+type PromptsListRequest struct {
+	PaginatedRequest
+}
+
+// PromptsListResponse 提示列表响应
+type PromptsListResponse struct {
+	Prompts    []Prompt `json:"prompts"`
+	NextCursor string   `json:"nextCursor,omitempty"`
+}
+
+// Prompt 提示模板定义
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+// PromptArgument 提示模板参数
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// PromptsGetRequest 获取提示请求
+type PromptsGetRequest struct {
+	Name      string            `json:"name" binding:"required"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// PromptsGetResponse 获取提示响应
+type PromptsGetResponse struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// PromptMessage 提示消息
+type PromptMessage struct {
+	Role    string  `json:"role" binding:"required,oneof=user assistant"` // user | assistant
+	Content Content `json:"content"`
+}
+
+// ==================== 补全相关 ====================
+
+// 补全引用类型
+const (
+	CompletionRefPrompt   = "ref/prompt"
+	CompletionRefResource = "ref/resource"
+	// CompletionRefTool 工具参数补全，为本服务扩展的引用类型
+	CompletionRefTool = "ref/tool"
+)
+
+// CompletionMaxValues 单次补全返回的最大候选数（MCP规范限制）
+const CompletionMaxValues = 100
+
+// CompletionCompleteRequest 参数补全请求
+type CompletionCompleteRequest struct {
+	Ref      CompletionReference `json:"ref"`
+	Argument CompletionArgument  `json:"argument"`
+	Context  *CompletionContext  `json:"context,omitempty"`
+}
+
+// CompletionReference 补全对象引用：提示模板按name引用，资源模板按uri引用
+type CompletionReference struct {
+	Type string `json:"type" binding:"required"`
+	Name string `json:"name,omitempty"`
+	URI  string `json:"uri,omitempty"`
+}
+
+// CompletionArgument 待补全的参数及当前输入
+type CompletionArgument struct {
+	Name  string `json:"name" binding:"required"`
+	Value string `json:"value"`
+}
+
+// CompletionContext 补全上下文，包含已填写的其他参数
+type CompletionContext struct {
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// CompletionCompleteResponse 参数补全响应
+type CompletionCompleteResponse struct {
+	Completion CompletionResult `json:"completion"`
+}
+
+// CompletionResult 补全结果
+type CompletionResult struct {
+	Values  []string `json:"values"`
+	Total   int      `json:"total,omitempty"`
+	HasMore bool     `json:"hasMore,omitempty"`
+}
+
+// ==================== 采样相关 ====================
+
+// CreateMessageRequest 服务端请求客户端LLM生成内容（sampling/createMessage）
+type CreateMessageRequest struct {
+	Messages         []SamplingMessage      `json:"messages"`
+	ModelPreferences *ModelPreferences      `json:"modelPreferences,omitempty"`
+	SystemPrompt     string                 `json:"systemPrompt,omitempty"`
+	IncludeContext   string                 `json:"includeContext,omitempty"` // none | thisServer | allServers
+	Temperature      *float64               `json:"temperature,omitempty"`
+	MaxTokens        int                    `json:"maxTokens"`
+	StopSequences    []string               `json:"stopSequences,omitempty"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// SamplingMessage 采样对话消息
+type SamplingMessage struct {
+	Role    string  `json:"role"` // user | assistant
+	Content Content `json:"content"`
+}
+
+// ModelPreferences 模型偏好，优先级取值0-1，由客户端自行决定最终使用的模型
+type ModelPreferences struct {
+	Hints                []ModelHint `json:"hints,omitempty"`
+	CostPriority         *float64    `json:"costPriority,omitempty"`
+	SpeedPriority        *float64    `json:"speedPriority,omitempty"`
+	IntelligencePriority *float64    `json:"intelligencePriority,omitempty"`
+}
+
+// ModelHint 模型名称提示
+type ModelHint struct {
+	Name string `json:"name,omitempty"`
+}
+
+// CreateMessageResult 客户端返回的采样结果
+type CreateMessageResult struct {
+	Role       string  `json:"role"`
+	Content    Content `json:"content"`
+	Model      string  `json:"model"`
+	StopReason string  `json:"stopReason,omitempty"` // endTurn | stopSequence | maxTokens
+}
+
+// ==================== 征询相关 ====================
+
+// ElicitRequest 服务端请求客户端向用户征询信息（elicitation/create）
+// RequestedSchema为受限的JSON Schema：扁平对象，属性仅限string、number、integer、boolean及字符串枚举
+type ElicitRequest struct {
+	Message         string      `json:"message"`
+	RequestedSchema interface{} `json:"requestedSchema"`
+}
+
+// 征询结果动作
+const (
+	ElicitActionAccept  = "accept"  // 用户提交了表单
+	ElicitActionDecline = "decline" // 用户明确拒绝提供
+	ElicitActionCancel  = "cancel"  // 用户关闭了表单，未作选择
+)
+
+// ElicitResult 客户端返回的征询结果，仅accept时包含content
+type ElicitResult struct {
+	Action  string                 `json:"action"`
+	Content map[string]interface{} `json:"content,omitempty"`
+}
+
+// ==================== 日志相关 ====================
+
+// LoggingLevel 日志级别 (RFC 5424)
+type LoggingLevel string
+
+const (
+	LoggingLevelDebug     LoggingLevel = "debug"
+	LoggingLevelInfo      LoggingLevel = "info"
+	LoggingLevelNotice    LoggingLevel = "notice"
+	LoggingLevelWarning   LoggingLevel = "warning"
+	LoggingLevelError     LoggingLevel = "error"
+	LoggingLevelCritical  LoggingLevel = "critical"
+	LoggingLevelAlert     LoggingLevel = "alert"
+	LoggingLevelEmergency LoggingLevel = "emergency"
+)
+
+// loggingLevelSeverity 日志级别严重程度，数值越大越严重
+var loggingLevelSeverity = map[LoggingLevel]int{
+	LoggingLevelDebug:     0,
+	LoggingLevelInfo:      1,
+	LoggingLevelNotice:    2,
+	LoggingLevelWarning:   3,
+	LoggingLevelError:     4,
+	LoggingLevelCritical:  5,
+	LoggingLevelAlert:     6,
+	LoggingLevelEmergency: 7,
+}
+
+// IsValid 是否为合法的日志级别
+func (l LoggingLevel) IsValid() bool {
+	_, ok := loggingLevelSeverity[l]
+	return ok
+}
+
+// AtLeast 是否不低于指定级别
+func (l LoggingLevel) AtLeast(min LoggingLevel) bool {
+	return loggingLevelSeverity[l] >= loggingLevelSeverity[min]
+}
+
+// LoggingSetLevelRequest 设置日志级别请求
+type LoggingSetLevelRequest struct {
+	Level LoggingLevel `json:"level" binding:"required"`
+}
+
+// LoggingMessageParams 日志消息通知参数
+type LoggingMessageParams struct {
+	Level  LoggingLevel `json:"level"`
+	Logger string       `json:"logger,omitempty"`
+	Data   interface{}  `json:"data"`
+}
+
 // ==================== 通知相关 ====================
 
 // ProgressNotification 进度通知
@@ -243,10 +623,10 @@ type ProgressNotification struct {
 
 // ProgressParams 进度参数
 type ProgressParams struct {
-	ProgressToken string  `json:"progressToken"`
-	Progress      float64 `json:"progress"`      // 0-1
-	Total         *float64 `json:"total,omitempty"`
-	Message       string   `json:"message,omitempty"`
+	ProgressToken interface{} `json:"progressToken"` // string | number
+	Progress      float64     `json:"progress"`      // 单调递增
+	Total         *float64    `json:"total,omitempty"`
+	Message       string      `json:"message,omitempty"`
 }
 
 // ResourcesUpdatedNotification 资源更新通知
@@ -260,6 +640,22 @@ type ResourcesUpdatedParams struct {
 	URI string `json:"uri"`
 }
 
+// CancelledParams 取消通知参数
+type CancelledParams struct {
+	RequestID interface{} `json:"requestId"`
+	Reason    string      `json:"reason,omitempty"`
+}
+
+// PromptsListChangedNotification 提示列表变更通知
+type PromptsListChangedNotification struct {
+	Method string `json:"method" binding:"eq=notifications/prompts/list_changed"`
+}
+
+// ResourcesListChangedNotification 资源列表变更通知
+type ResourcesListChangedNotification struct {
+	Method string `json:"method" binding:"eq=notifications/resources/list_changed"`
+}
+
 // ToolsListChangedNotification 工具列表变更通知
 type ToolsListChangedNotification struct {
 	Method string `json:"method" binding:"eq=notifications/tools/list_changed"`
@@ -267,24 +663,86 @@ type ToolsListChangedNotification struct {
 
 // ==================== 工具实现相关 ====================
 
+// ToolCategory 工具类别
+type ToolCategory string
+
+const (
+	ToolCategorySearch     ToolCategory = "search"     // 智能检索
+	ToolCategoryContent    ToolCategory = "content"    // 内容处理
+	ToolCategoryGeneration ToolCategory = "generation" // 教学生成
+	ToolCategoryAnalysis   ToolCategory = "analysis"   // 学习分析
+)
+
 // ToolDefinition 工具定义（内部使用）
 type ToolDefinition struct {
 	Name        string
 	Description string
 	Handler     ToolHandler
 	InputSchema interface{}
+	// OutputSchema 结构化结果的JSON Schema，可选
+	OutputSchema interface{}
+	// Annotations 工具行为提示，通过tools/list暴露给客户端
+	Annotations *ToolAnnotations
+
+	// 以下为内部元数据，不暴露给客户端
+	Category ToolCategory
+	// Permission 调用所需权限，格式为"资源:操作"，为空时不校验
+	Permission string
+	// CostWeight 单次调用的成本权重，用于配额计量，为0时按1计
+	CostWeight int
+	// Timeout 执行超时，为0时使用服务默认值
+	Timeout time.Duration
+	// MaxConcurrency 同时执行的调用数上限，为0时使用服务默认值
+	MaxConcurrency int
+	// ElicitFields 调用缺少必填参数且客户端支持elicitation时可向用户询问的字段，
+	// 字段须为基本类型、字符串枚举或字符串数组
+	ElicitFields []string
 }
 
 // ToolHandler 工具处理器
 type ToolHandler func(ctx *ToolContext, args interface{}) (*ToolsCallResponse, error)
 
 // ToolContext 工具上下文
+// 内嵌的context.Context在客户端取消请求、连接断开或会话关闭时被取消，
+// 耗时的工具处理器应通过Done()/Err()及时中止
 type ToolContext struct {
+	context.Context
+
 	UserID      uuid.UUID
 	SessionID   string
 	RequestID   string
 	StartTime   time.Time
 	Parameters  map[string]interface{}
+
+	// OnProgress 进度回调，仅当客户端请求携带_meta.progressToken时设置
+	OnProgress ProgressFunc
+	// OnLog 日志回调，按客户端设置的级别转发为notifications/message
+	OnLog LogFunc
+}
+
+// LogFunc 日志回调，data为字符串或可JSON序列化的结构化数据
+type LogFunc func(level LoggingLevel, data interface{})
+
+// ProgressFunc 进度回调，total为0表示总量未知
+type ProgressFunc func(progress, total float64, message string)
+
+// ReportProgress 报告工具执行进度，客户端未请求进度时忽略
+func (c *ToolContext) ReportProgress(progress, total float64, message string) {
+	if c.OnProgress != nil {
+		c.OnProgress(progress, total, message)
+	}
+}
+
+// Log 记录日志并转发给客户端
+func (c *ToolContext) Log(level LoggingLevel, data interface{}) {
+	if c.OnLog != nil {
+		c.OnLog(level, data)
+	}
+}
+
+// Warn 记录警告并转发给客户端，如素材因授权限制被过滤
+func (c *ToolContext) Warn(format string, args ...interface{}) {
+	c.Log(LoggingLevelWarning, fmt.Sprintf(format, args...))
 }
 
 // ResourceDefinition 资源定义（内部使用）
@@ -296,12 +754,46 @@ type ResourceDefinition struct {
 	Handler     ResourceHandler
 }
 
-// ResourceHandler 资源处理器
-type ResourceHandler func(uri string) (*ResourcesReadResponse, error)
+// ResourceTemplateDefinition 资源模板定义（内部使用）
+type ResourceTemplateDefinition struct {
+	URITemplate string
+	Name        string
+	Description string
+	MimeType    string
+	Handler     ResourceHandler
+
+	// Completions 模板变量的可选值，用于completion/complete
+	Completions map[string][]string
+}
+
+// ResourceHandler 资源处理器，vars为从URI模板中提取的变量，固定URI资源为nil
+// ctx携带当前用户与会话，用于按用户校验资源访问权限
+type ResourceHandler func(ctx context.Context, uri string, vars map[string]string) (*ResourcesReadResponse, error)
+
+// PromptDefinition 提示模板定义（内部使用）
+type PromptDefinition struct {
+	Name        string
+	Description string
+	Arguments   []PromptArgumentDefinition
+	Handler     PromptHandler
+}
+
+// PromptArgumentDefinition 提示模板参数定义（内部使用）
+// Enum非空时参数值必须是其中之一，Default在参数缺省时填充
+type PromptArgumentDefinition struct {
+	Name        string
+	Description string
+	Required    bool
+	Enum        []string
+	Default     string
+}
+
+// PromptHandler 提示模板处理器，args已完成必填校验与默认值填充
+type PromptHandler func(ctx context.Context, args map[string]string) (*PromptsGetResponse, error)
 
 // ==================== 扩展类型 ====================
 
-// PaginatedRequest 分页请求（扩展）
+// PaginatedRequest 游标分页请求，cursor为上一页响应的nextCursor，limit为扩展参数
 type PaginatedRequest struct {
 	Cursor *string `json:"cursor,omitempty"`
 	Limit  *int    `json:"limit,omitempty"`
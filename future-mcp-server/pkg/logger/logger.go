@@ -60,6 +60,10 @@ func Init() error {
 
 		config.OutputPaths = []string{logPath}
 		config.ErrorOutputPaths = []string{logPath}
+	} else if output == "stderr" {
+		// stdio传输占用stdout，日志只能写入stderr
+		config.OutputPaths = []string{"stderr"}
+		config.ErrorOutputPaths = []string{"stderr"}
 	} else {
 		config.OutputPaths = []string{"stdout"}
 		config.ErrorOutputPaths = []string{"stderr"}
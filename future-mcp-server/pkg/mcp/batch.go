@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+)
+
+const (
+	// defaultBatchMaxSize 单个批量请求允许的最大消息数
+	defaultBatchMaxSize = 10
+	// defaultBatchConcurrency 批量请求内并发执行的最大消息数
+	defaultBatchConcurrency = 4
+)
+
+// BatchMaxSize 单个批量请求允许的最大消息数
+func (s *Server) BatchMaxSize() int {
+	if s.config.BatchMaxSize > 0 {
+		return s.config.BatchMaxSize
+	}
+	return defaultBatchMaxSize
+}
+
+// batchConcurrency 批量请求内的并发上限
+func (s *Server) batchConcurrency() int {
+	if s.config.BatchConcurrency > 0 {
+		return s.config.BatchConcurrency
+	}
+	return defaultBatchConcurrency
+}
+
+// HandleBatch 并发处理一组JSON-RPC消息
+// 没有id的消息视为通知，不产生响应；返回的响应按原消息顺序排列，仅包含通知时返回空切片。
+func (s *Server) HandleBatch(ctx context.Context, requests []*types.MCPRequest) []*types.MCPResponse {
+	results := make([]*types.MCPResponse, len(requests))
+	semaphore := make(chan struct{}, s.batchConcurrency())
+
+	var wg sync.WaitGroup
+	for i, request := range requests {
+		wg.Add(1)
+		go func(i int, request *types.MCPRequest) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				if request.ID != nil {
+					results[i], _ = createErrorResponse(request.ID, types.MCPInternalError, "Request cancelled")
+				}
+				return
+			}
+
+			response, err := s.HandleRequest(ctx, request)
+			if err != nil {
+				logger.Error("Failed to handle batch MCP request",
+					logger.Any("method", request.Method),
+					logger.Any("error", err))
+				response, _ = createErrorResponse(request.ID, types.MCPInternalError, "Internal server error")
+			}
+
+			// 通知不返回响应
+			if request.ID != nil {
+				results[i] = response
+			}
+		}(i, request)
+	}
+	wg.Wait()
+
+	responses := make([]*types.MCPResponse, 0, len(results))
+	for _, response := range results {
+		if response != nil {
+			responses = append(responses, response)
+		}
+	}
+	return responses
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+// registerBlockingTool 注册一个阻塞到上下文取消的工具，started在工具开始执行时关闭
+func registerBlockingTool(s *Server, name string) <-chan struct{} {
+	started := make(chan struct{})
+	s.toolRegistry.RegisterTool(&types.ToolDefinition{
+		Name: name,
+		Handler: func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+	return started
+}
+
+func TestCancelledRequestReturnsNoResponse(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	started := registerBlockingTool(s, "block")
+	_, ctx := readySession(t, s)
+
+	done := make(chan *types.MCPResponse, 1)
+	go func() {
+		done <- handle(t, s, ctx, "call-1", types.MCPMethodToolsCall, map[string]interface{}{"name": "block"})
+	}()
+	<-started
+
+	if response := handle(t, s, ctx, nil, types.MCPMethodCancelled, map[string]interface{}{"requestId": "call-1", "reason": "user abort"}); response != nil {
+		t.Fatalf("cancel notification returned %+v", response)
+	}
+
+	select {
+	case response := <-done:
+		if response != nil {
+			t.Fatalf("cancelled request returned %+v, want no response", response)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("request was not cancelled")
+	}
+}
+
+func TestCancelUnknownRequestIgnored(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	session, ctx := readySession(t, s)
+
+	if session.CancelRequest("missing") {
+		t.Fatal("CancelRequest reported success for unknown request")
+	}
+	if response := handle(t, s, ctx, nil, types.MCPMethodCancelled, map[string]interface{}{"requestId": "missing"}); response != nil {
+		t.Fatalf("cancel notification returned %+v", response)
+	}
+}
+
+func TestSessionTrackRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		action    func(session *Session)
+		wantCause error
+	}{
+		{"cancelled by client", func(session *Session) { session.CancelRequest(float64(7)) }, ErrRequestCancelled},
+		{"session closed", func(session *Session) { session.Close() }, ErrSessionClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(ServerConfig{})
+			session, ctx := readySession(t, s)
+
+			// 数字ID经JSON解码为float64，登记与取消使用同一键
+			tracked, done := session.TrackRequest(ctx, float64(7))
+			defer done()
+
+			tt.action(session)
+			select {
+			case <-tracked.Done():
+			case <-time.After(time.Second):
+				t.Fatal("tracked context not cancelled")
+			}
+			if cause := context.Cause(tracked); cause != tt.wantCause {
+				t.Fatalf("cause = %v, want %v", cause, tt.wantCause)
+			}
+		})
+	}
+}
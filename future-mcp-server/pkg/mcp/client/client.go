@@ -0,0 +1,514 @@
+// Package client MCP客户端
+// 通过HTTP、Streamable HTTP（SSE）、WebSocket或stdio连接MCP服务器，
+// 提供初始化握手、工具/资源调用、分页列表、通知订阅、请求取消与进度回调。
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+)
+
+// ErrClientClosed 客户端已关闭
+var ErrClientClosed = errors.New("client closed")
+
+const (
+	// defaultClientName 未配置客户端信息时上报的名称
+	defaultClientName = "talink-go-client"
+	// defaultClientVersion 未配置客户端信息时上报的版本
+	defaultClientVersion = "1.0.0"
+	// cancelNotifyTimeout 发送取消通知的超时
+	cancelNotifyTimeout = 5 * time.Second
+)
+
+// Config 客户端配置
+type Config struct {
+	// ClientInfo 初始化时上报的客户端信息
+	ClientInfo mcp.ImplementationInfo
+	// Capabilities 初始化时声明的客户端能力，注册采样处理器时自动声明sampling
+	Capabilities mcp.ClientCapabilities
+	// ProtocolVersion 请求的协议版本，为空时使用最新版本
+	ProtocolVersion string
+}
+
+// NotificationHandler 服务端通知处理器
+// 在接收goroutine中按到达顺序执行，不应阻塞或在其中发起新的请求
+type NotificationHandler func(params json.RawMessage)
+
+// RequestHandler 服务端请求处理器（如sampling/createMessage），返回值作为响应结果
+// 返回*RPCError时以其错误码响应，其他错误以-32603响应
+type RequestHandler func(ctx context.Context, params json.RawMessage) (interface{}, error)
+
+// ProgressHandler 请求进度回调
+type ProgressHandler func(progress mcp.ProgressParams)
+
+// RPCError 服务端返回的JSON-RPC错误
+type RPCError struct {
+	Code    int
+	Message string
+	Data    interface{}
+}
+
+// Error 实现error接口
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("mcp error %d: %s", e.Code, e.Message)
+}
+
+// message 收到的JSON-RPC消息，字段保留原始JSON以便按方法解码
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *types.MCPError `json:"error,omitempty"`
+}
+
+// Client MCP客户端
+type Client struct {
+	transport Transport
+	config    Config
+
+	nextID        int64
+	nextProgress  int64
+	pending       map[string]chan *message
+	progress      map[string]ProgressHandler
+	notifications map[string][]NotificationHandler
+	requests      map[string]RequestHandler
+	inflight      map[string]context.CancelFunc
+	initialized   *mcp.InitializeResponse
+	started       bool
+	closed        bool
+	closeErr      error
+	done          chan struct{}
+	mu            sync.Mutex
+}
+
+// New 创建客户端，需调用Connect（或Start后Initialize）后使用
+func New(transport Transport, config Config) *Client {
+	if config.ClientInfo.Name == "" {
+		config.ClientInfo = mcp.ImplementationInfo{Name: defaultClientName, Version: defaultClientVersion}
+	}
+	if config.ProtocolVersion == "" {
+		config.ProtocolVersion = types.MCPProtocolVersion
+	}
+
+	return &Client{
+		transport:     transport,
+		config:        config,
+		pending:       make(map[string]chan *message),
+		progress:      make(map[string]ProgressHandler),
+		notifications: make(map[string][]NotificationHandler),
+		requests:      make(map[string]RequestHandler),
+		inflight:      make(map[string]context.CancelFunc),
+		done:          make(chan struct{}),
+	}
+}
+
+// Connect 创建客户端、建立连接并完成初始化握手
+func Connect(ctx context.Context, transport Transport, config Config) (*Client, error) {
+	c := New(transport, config)
+	if err := c.Start(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := c.Initialize(ctx); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Start 建立传输连接，处理器应在此之前注册
+func (c *Client) Start(ctx context.Context) error {
+	c.mu.Lock()
+	if c.started {
+		c.mu.Unlock()
+		return nil
+	}
+	c.started = true
+	c.mu.Unlock()
+
+	if err := c.transport.Start(ctx, c.receive, c.disconnected); err != nil {
+		return fmt.Errorf("failed to start transport: %w", err)
+	}
+	return nil
+}
+
+// Initialize 发送initialize请求，成功后发送notifications/initialized完成握手
+func (c *Client) Initialize(ctx context.Context) (*mcp.InitializeResponse, error) {
+	c.mu.Lock()
+	request := types.InitializeRequest{
+		ProtocolVersion: c.config.ProtocolVersion,
+		Capabilities:    c.config.Capabilities,
+		ClientInfo:      c.config.ClientInfo,
+	}
+	c.mu.Unlock()
+
+	var result mcp.InitializeResponse
+	if err := c.call(ctx, types.MCPMethodInitialize, request, &result, nil); err != nil {
+		return nil, err
+	}
+	if !types.IsSupportedProtocolVersion(result.ProtocolVersion) {
+		return nil, fmt.Errorf("unsupported protocol version %q", result.ProtocolVersion)
+	}
+
+	if setter, ok := c.transport.(ProtocolVersionSetter); ok {
+		setter.SetProtocolVersion(result.ProtocolVersion)
+	}
+
+	if err := c.Notify(ctx, types.MCPMethodNotificationsInitialized, nil); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.initialized = &result
+	c.mu.Unlock()
+	return &result, nil
+}
+
+// ServerInfo 初始化结果，未初始化时返回nil
+func (c *Client) ServerInfo() *mcp.InitializeResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.initialized
+}
+
+// OnNotification 注册服务端通知处理器，同一方法可注册多个
+func (c *Client) OnNotification(method string, handler NotificationHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notifications[method] = append(c.notifications[method], handler)
+}
+
+// HandleRequest 注册服务端请求处理器
+func (c *Client) HandleRequest(method string, handler RequestHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests[method] = handler
+}
+
+// Done 客户端关闭或连接断开时关闭
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err 连接断开的原因，主动关闭时为ErrClientClosed
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeErr
+}
+
+// Close 关闭客户端与传输，等待中的请求返回ErrClientClosed
+func (c *Client) Close() error {
+	c.shutdown(ErrClientClosed)
+	return c.transport.Close()
+}
+
+// Call 发送请求并将结果解码到result（可为nil）
+func (c *Client) Call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	return c.call(ctx, method, params, result, nil)
+}
+
+// Notify 发送通知
+func (c *Client) Notify(ctx context.Context, method string, params interface{}) error {
+	data, err := json.Marshal(&types.MCPNotification{
+		MCPMessage: types.MCPMessage{JSONRPC: "2.0"},
+		Method:     method,
+		Params:     params,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s notification: %w", method, err)
+	}
+	return c.transport.Send(ctx, data)
+}
+
+// call 发送请求并等待响应
+// 上下文取消时向服务端发送notifications/cancelled（initialize除外），onProgress非nil时附带进度令牌
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}, onProgress ProgressHandler) error {
+	id := atomic.AddInt64(&c.nextID, 1)
+	key := strconv.FormatInt(id, 10)
+	ch := make(chan *message, 1)
+
+	var progressToken string
+	if onProgress != nil {
+		progressToken = "progress-" + strconv.FormatInt(atomic.AddInt64(&c.nextProgress, 1), 10)
+		withToken, err := withProgressToken(params, progressToken)
+		if err != nil {
+			return err
+		}
+		params = withToken
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		err := c.closeErr
+		c.mu.Unlock()
+		return err
+	}
+	c.pending[key] = ch
+	if onProgress != nil {
+		c.progress[progressToken] = onProgress
+	}
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, key)
+		if onProgress != nil {
+			delete(c.progress, progressToken)
+		}
+		c.mu.Unlock()
+	}()
+
+	data, err := json.Marshal(&types.MCPRequest{
+		MCPMessage: types.MCPMessage{JSONRPC: "2.0", ID: id},
+		Method:     method,
+		Params:     params,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+
+	if err := c.transport.Send(ctx, data); err != nil {
+		if ctx.Err() != nil {
+			c.cancelRequest(method, id, ctx.Err())
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+
+	select {
+	case response := <-ch:
+		if response.Error != nil {
+			return &RPCError{Code: response.Error.Code, Message: response.Error.Message, Data: response.Error.Data}
+		}
+		if result == nil || len(response.Result) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("failed to decode %s result: %w", method, err)
+		}
+		return nil
+	case <-ctx.Done():
+		c.cancelRequest(method, id, ctx.Err())
+		return ctx.Err()
+	case <-c.done:
+		return c.Err()
+	}
+}
+
+// cancelRequest 通知服务端放弃处理已取消的请求
+func (c *Client) cancelRequest(method string, id int64, reason error) {
+	if method == types.MCPMethodInitialize {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cancelNotifyTimeout)
+	defer cancel()
+	_ = c.Notify(ctx, types.MCPMethodCancelled, types.CancelledParams{
+		RequestID: id,
+		Reason:    reason.Error(),
+	})
+}
+
+// withProgressToken 在请求参数的_meta中加入进度令牌
+func withProgressToken(params interface{}, token string) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request params: %w", err)
+		}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, fmt.Errorf("request params must be an object: %w", err)
+		}
+	}
+
+	meta, _ := fields["_meta"].(map[string]interface{})
+	if meta == nil {
+		meta = map[string]interface{}{}
+	}
+	meta["progressToken"] = token
+	fields["_meta"] = meta
+	return fields, nil
+}
+
+// receive 处理传输交付的消息，批量响应逐条处理
+func (c *Client) receive(data []byte) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return
+		}
+		for _, item := range batch {
+			c.receive(item)
+		}
+		return
+	}
+
+	var msg message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+
+	hasID := len(msg.ID) > 0 && string(msg.ID) != "null"
+	switch {
+	case msg.Method == "" && hasID:
+		c.deliverResponse(&msg)
+	case msg.Method != "" && hasID:
+		go c.handleServerRequest(&msg)
+	case msg.Method != "":
+		c.handleNotification(&msg)
+	}
+}
+
+// deliverResponse 将响应交给等待中的请求，已取消的请求的迟到响应被丢弃
+func (c *Client) deliverResponse(msg *message) {
+	c.mu.Lock()
+	ch, ok := c.pending[string(msg.ID)]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	select {
+	case ch <- msg:
+	default:
+	}
+}
+
+// handleNotification 分发通知，进度通知按令牌交给对应请求的回调
+func (c *Client) handleNotification(msg *message) {
+	if msg.Method == types.MCPMethodCancelled {
+		c.cancelServerRequest(msg.Params)
+	}
+
+	c.mu.Lock()
+	handlers := append([]NotificationHandler(nil), c.notifications[msg.Method]...)
+	var onProgress ProgressHandler
+	var progress mcp.ProgressParams
+	if msg.Method == types.MCPMethodProgress && json.Unmarshal(msg.Params, &progress) == nil {
+		if token, ok := progress.ProgressToken.(string); ok {
+			onProgress = c.progress[token]
+		}
+	}
+	c.mu.Unlock()
+
+	if onProgress != nil {
+		onProgress(progress)
+	}
+	for _, handler := range handlers {
+		handler(msg.Params)
+	}
+}
+
+// cancelServerRequest 取消服务端已放弃的请求的处理
+func (c *Client) cancelServerRequest(params json.RawMessage) {
+	var cancelled struct {
+		RequestID json.RawMessage `json:"requestId"`
+	}
+	if err := json.Unmarshal(params, &cancelled); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	cancel := c.inflight[string(cancelled.RequestID)]
+	c.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// handleServerRequest 执行服务端请求并回复响应，未注册的方法返回-32601
+func (c *Client) handleServerRequest(msg *message) {
+	c.mu.Lock()
+	handler := c.requests[msg.Method]
+	c.mu.Unlock()
+
+	var id interface{}
+	if err := json.Unmarshal(msg.ID, &id); err != nil {
+		return
+	}
+
+	response := &types.MCPResponse{MCPMessage: types.MCPMessage{JSONRPC: "2.0", ID: id}}
+	switch {
+	case msg.Method == types.MCPMethodPing:
+		response.Result = map[string]interface{}{}
+	case handler == nil:
+		response.Error = &types.MCPError{Code: types.MCPMethodNotFound, Message: "Method not found: " + msg.Method}
+	default:
+		// 客户端关闭或服务端发送notifications/cancelled时取消处理
+		ctx, cancel := context.WithCancel(context.Background())
+		key := string(msg.ID)
+		c.mu.Lock()
+		c.inflight[key] = cancel
+		c.mu.Unlock()
+		go func() {
+			select {
+			case <-c.done:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		result, err := handler(ctx, msg.Params)
+
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+		cancelled := ctx.Err() != nil
+		cancel()
+		if cancelled {
+			// 已取消的请求不再响应
+			return
+		}
+
+		var rpcErr *RPCError
+		switch {
+		case errors.As(err, &rpcErr):
+			response.Error = &types.MCPError{Code: rpcErr.Code, Message: rpcErr.Message, Data: rpcErr.Data}
+		case err != nil:
+			response.Error = &types.MCPError{Code: types.MCPInternalError, Message: err.Error()}
+		default:
+			response.Result = result
+		}
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	_ = c.transport.Send(context.Background(), data)
+}
+
+// disconnected 传输异常断开
+func (c *Client) disconnected(err error) {
+	if err == nil || errors.Is(err, ErrTransportClosed) {
+		c.shutdown(ErrTransportClosed)
+		return
+	}
+	c.shutdown(fmt.Errorf("%w: %v", ErrTransportClosed, err))
+}
+
+// shutdown 标记客户端关闭并唤醒等待中的请求，只记录首次关闭的原因
+func (c *Client) shutdown(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.closeErr = err
+	close(c.done)
+}
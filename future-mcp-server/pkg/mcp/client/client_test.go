@@ -0,0 +1,282 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/handler"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp/client"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+func TestMain(m *testing.M) {
+	viper.Set("log.level", "error")
+	if err := logger.Init(); err != nil {
+		panic(err)
+	}
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+type addInput struct {
+	A int `json:"a" jsonschema:"required"`
+	B int `json:"b" jsonschema:"required"`
+}
+
+type addOutput struct {
+	Sum int `json:"sum"`
+}
+
+// newTestServer 以gin处理器挂载各传输，启动注册了测试工具的MCP服务器
+func newTestServer(t *testing.T) (*mcp.Server, *httptest.Server) {
+	t.Helper()
+
+	server := mcp.NewServer(mcp.ServerConfig{
+		ServerInfo:          mcp.ImplementationInfo{Name: "test-server", Version: "1.0.0"},
+		ListChangedDebounce: 10 * time.Millisecond,
+	})
+
+	err := mcp.RegisterTypedTool(server.Tools(), &mcp.ToolDefinition{Name: "add", Description: "两数相加"},
+		func(ctx *mcp.ToolContext, input *addInput) (*addOutput, error) {
+			return &addOutput{Sum: input.A + input.B}, nil
+		})
+	if err != nil {
+		t.Fatalf("RegisterTypedTool: %v", err)
+	}
+	err = mcp.RegisterTypedTool(server.Tools(), &mcp.ToolDefinition{Name: "fail", Description: "总是失败"},
+		func(ctx *mcp.ToolContext, input *struct{}) (*addOutput, error) {
+			return nil, &mcp.ToolError{Message: "素材不存在"}
+		})
+	if err != nil {
+		t.Fatalf("RegisterTypedTool: %v", err)
+	}
+	server.Tools().RegisterTool(&mcp.ToolDefinition{
+		Name:        "count",
+		Description: "报告三次进度",
+		InputSchema: map[string]interface{}{"type": "object"},
+		Handler: func(ctx *mcp.ToolContext, args interface{}) (*mcp.ToolsCallResponse, error) {
+			for i := 1; i <= 3; i++ {
+				ctx.ReportProgress(float64(i), 3, "")
+			}
+			return &mcp.ToolsCallResponse{Content: []mcp.Content{mcp.TextContent("counted")}}, nil
+		},
+	})
+	server.Tools().RegisterTool(&mcp.ToolDefinition{
+		Name:        "ask_model",
+		Description: "通过客户端采样生成回答",
+		InputSchema: map[string]interface{}{"type": "object"},
+		Handler: func(ctx *mcp.ToolContext, args interface{}) (*mcp.ToolsCallResponse, error) {
+			result, err := mcp.CreateMessage(ctx.Context, &mcp.CreateMessageRequest{
+				Messages:  []mcp.SamplingMessage{{Role: "user", Content: mcp.TextContent("你好")}},
+				MaxTokens: 16,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &mcp.ToolsCallResponse{Content: []mcp.Content{result.Content}}, nil
+		},
+	})
+
+	r := gin.New()
+	r.POST("/mcp/jsonrpc", handler.MCPHandler(server))
+	r.POST("/mcp", handler.MCPStreamableHandler(server))
+	r.GET("/mcp", handler.MCPSSEHandler(server))
+	r.DELETE("/mcp", handler.MCPSessionDeleteHandler(server))
+	r.GET("/mcp/ws", handler.MCPWebSocketHandler(server, handler.WebSocketConfig{}))
+
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+	return server, ts
+}
+
+// transportCase 待测传输，stateful表示支持进度、服务端请求与服务端通知
+type transportCase struct {
+	name      string
+	stateful  bool
+	transport func(baseURL string) client.Transport
+}
+
+var transportCases = []transportCase{
+	{"jsonrpc", false, func(baseURL string) client.Transport {
+		return client.NewHTTPTransport(baseURL+"/mcp/jsonrpc", client.HTTPOptions{})
+	}},
+	{"streamable", true, func(baseURL string) client.Transport {
+		return client.NewStreamableHTTPTransport(baseURL+"/mcp", client.HTTPOptions{})
+	}},
+	{"websocket", true, func(baseURL string) client.Transport {
+		return client.NewWebSocketTransport("ws"+strings.TrimPrefix(baseURL, "http")+"/mcp/ws", nil)
+	}},
+}
+
+// connect 连接测试服务器，setup在初始化之前注册处理器
+func connect(t *testing.T, tc transportCase, baseURL string, setup func(c *client.Client)) *client.Client {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c := client.New(tc.transport(baseURL), client.Config{})
+	if setup != nil {
+		setup(c)
+	}
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	if _, err := c.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	return c
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, tc := range transportCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, ts := newTestServer(t)
+			c := connect(t, tc, ts.URL, nil)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if info := c.ServerInfo(); info == nil || info.ServerInfo.Name != "test-server" {
+				t.Fatalf("ServerInfo = %+v", info)
+			}
+			if err := c.Ping(ctx); err != nil {
+				t.Fatalf("Ping: %v", err)
+			}
+
+			tools, err := c.ListAllTools(ctx)
+			if err != nil {
+				t.Fatalf("ListAllTools: %v", err)
+			}
+			if len(tools) != 4 {
+				t.Fatalf("ListAllTools returned %d tools, want 4", len(tools))
+			}
+
+			sum, err := client.CallTypedTool[addOutput](ctx, c, "add", map[string]interface{}{"a": 2, "b": 3}, nil)
+			if err != nil {
+				t.Fatalf("CallTypedTool add: %v", err)
+			}
+			if sum.Sum != 5 {
+				t.Fatalf("sum = %d, want 5", sum.Sum)
+			}
+
+			_, err = client.CallTypedTool[addOutput](ctx, c, "fail", map[string]interface{}{}, nil)
+			var toolErr *client.ToolError
+			if !errors.As(err, &toolErr) || toolErr.Message != "素材不存在" {
+				t.Fatalf("CallTypedTool fail: err = %v, want ToolError", err)
+			}
+
+			// 参数不符合Schema时返回JSON-RPC错误
+			_, err = c.CallTool(ctx, "add", map[string]interface{}{"a": 2})
+			var rpcErr *client.RPCError
+			if !errors.As(err, &rpcErr) || !strings.Contains(rpcErr.Message, "b") {
+				t.Fatalf("CallTool with missing argument: err = %v, want RPCError", err)
+			}
+		})
+	}
+}
+
+func TestRoundTripProgress(t *testing.T) {
+	for _, tc := range transportCases {
+		if !tc.stateful {
+			continue
+		}
+		t.Run(tc.name, func(t *testing.T) {
+			_, ts := newTestServer(t)
+			c := connect(t, tc, ts.URL, nil)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			var progress []float64
+			result, err := c.CallToolWithProgress(ctx, "count", map[string]interface{}{}, func(p mcp.ProgressParams) {
+				progress = append(progress, p.Progress)
+			})
+			if err != nil {
+				t.Fatalf("CallToolWithProgress: %v", err)
+			}
+			if result.IsError || result.Content[0].Text != "counted" {
+				t.Fatalf("result = %+v", result)
+			}
+			// 进度通知先于最终响应送达
+			if len(progress) != 3 || progress[2] != 3 {
+				t.Fatalf("progress = %v, want [1 2 3]", progress)
+			}
+		})
+	}
+}
+
+func TestRoundTripSampling(t *testing.T) {
+	for _, tc := range transportCases {
+		if !tc.stateful {
+			continue
+		}
+		t.Run(tc.name, func(t *testing.T) {
+			_, ts := newTestServer(t)
+			c := connect(t, tc, ts.URL, func(c *client.Client) {
+				c.HandleSampling(func(ctx context.Context, request *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+					return &mcp.CreateMessageResult{
+						Role:    "assistant",
+						Content: mcp.TextContent("回答：" + request.Messages[0].Content.Text),
+						Model:   "test-model",
+					}, nil
+				})
+			})
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := c.CallTool(ctx, "ask_model", map[string]interface{}{})
+			if err != nil {
+				t.Fatalf("CallTool: %v", err)
+			}
+			if result.IsError || result.Content[0].Text != "回答：你好" {
+				t.Fatalf("result = %+v", result)
+			}
+		})
+	}
+}
+
+func TestRoundTripListChanged(t *testing.T) {
+	for _, tc := range transportCases {
+		if !tc.stateful {
+			continue
+		}
+		t.Run(tc.name, func(t *testing.T) {
+			server, ts := newTestServer(t)
+			changed := make(chan struct{}, 1)
+			c := connect(t, tc, ts.URL, func(c *client.Client) {
+				c.OnListChanged(mcp.MethodToolsListChanged, func() {
+					select {
+					case changed <- struct{}{}:
+					default:
+					}
+				})
+			})
+
+			server.Tools().RemoveTool("count")
+
+			select {
+			case <-changed:
+			case <-time.After(5 * time.Second):
+				t.Fatal("tools/list_changed notification not received")
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			tools, err := c.ListAllTools(ctx)
+			if err != nil {
+				t.Fatalf("ListAllTools: %v", err)
+			}
+			if len(tools) != 3 {
+				t.Fatalf("ListAllTools returned %d tools, want 3", len(tools))
+			}
+		})
+	}
+}
@@ -0,0 +1,393 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// sessionIDHeader 会话ID请求/响应头
+	sessionIDHeader = "Mcp-Session-Id"
+	// protocolVersionHeader 初始化后随请求携带的协商协议版本
+	protocolVersionHeader = "Mcp-Protocol-Version"
+	// lastEventIDHeader SSE断线续传请求头
+	lastEventIDHeader = "Last-Event-ID"
+	// sseReconnectDelay 推送流断开后的重连间隔
+	sseReconnectDelay = time.Second
+	// maxErrorBodySize 错误响应体最多读取的字节数
+	maxErrorBodySize = 4096
+)
+
+// HTTPOptions HTTP类传输的选项
+type HTTPOptions struct {
+	// Client 发送请求使用的HTTP客户端，为nil时使用http.DefaultClient
+	Client *http.Client
+	// Header 附加到每个请求的请求头，如Authorization
+	Header http.Header
+}
+
+// httpClient 选项中的HTTP客户端
+func (o HTTPOptions) httpClient() *http.Client {
+	if o.Client != nil {
+		return o.Client
+	}
+	return http.DefaultClient
+}
+
+// newRequest 创建携带附加请求头的HTTP请求
+func (o HTTPOptions) newRequest(ctx context.Context, method, url string, body []byte) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range o.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// ==================== 无状态JSON-RPC ====================
+
+// HTTPTransport 无状态JSON-RPC传输（POST /mcp/jsonrpc）
+// 每个请求独立处理，不支持服务端通知、服务端请求与进度推送
+type HTTPTransport struct {
+	endpoint  string
+	options   HTTPOptions
+	onMessage MessageHandler
+}
+
+// NewHTTPTransport 创建无状态JSON-RPC传输
+func NewHTTPTransport(endpoint string, options HTTPOptions) *HTTPTransport {
+	return &HTTPTransport{endpoint: endpoint, options: options}
+}
+
+// Start 实现Transport接口
+func (t *HTTPTransport) Start(ctx context.Context, onMessage MessageHandler, onClose CloseHandler) error {
+	t.onMessage = onMessage
+	return nil
+}
+
+// Send 发送消息并在返回前交付响应
+func (t *HTTPTransport) Send(ctx context.Context, message []byte) error {
+	req, err := t.options.newRequest(ctx, http.MethodPost, t.endpoint, message)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := t.options.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusAccepted {
+		return nil
+	}
+
+	return deliverJSONResponse(resp, t.onMessage)
+}
+
+// Close 实现Transport接口
+func (t *HTTPTransport) Close() error {
+	return nil
+}
+
+// ==================== Streamable HTTP ====================
+
+// StreamableHTTPTransport Streamable HTTP传输（POST/GET/DELETE /mcp）
+// initialize响应返回的Mcp-Session-Id随后续请求携带；POST响应以SSE返回时逐条交付其中的通知与响应，
+// 初始化完成后打开GET推送流接收服务端通知与请求，断开后携带Last-Event-ID自动重连。
+type StreamableHTTPTransport struct {
+	endpoint  string
+	options   HTTPOptions
+	onMessage MessageHandler
+	onClose   CloseHandler
+
+	sessionID       string
+	protocolVersion string
+	lastEventID     string
+	streamCancel    context.CancelFunc
+	closed          bool
+	mu              sync.Mutex
+}
+
+// NewStreamableHTTPTransport 创建Streamable HTTP传输
+func NewStreamableHTTPTransport(endpoint string, options HTTPOptions) *StreamableHTTPTransport {
+	return &StreamableHTTPTransport{endpoint: endpoint, options: options}
+}
+
+// Start 实现Transport接口，推送流在协商协议版本后打开
+func (t *StreamableHTTPTransport) Start(ctx context.Context, onMessage MessageHandler, onClose CloseHandler) error {
+	t.onMessage = onMessage
+	t.onClose = onClose
+	return nil
+}
+
+// SessionID 服务端分配的会话ID，初始化前为空
+func (t *StreamableHTTPTransport) SessionID() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.sessionID
+}
+
+// SetProtocolVersion 记录协商的协议版本并打开推送流
+func (t *StreamableHTTPTransport) SetProtocolVersion(version string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.protocolVersion = version
+	if t.closed || t.streamCancel != nil || t.sessionID == "" {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.streamCancel = cancel
+	go t.runEventStream(ctx)
+}
+
+// Send 发送消息，请求的响应及相关通知在返回前交付
+func (t *StreamableHTTPTransport) Send(ctx context.Context, message []byte) error {
+	req, err := t.options.newRequest(ctx, http.MethodPost, t.endpoint, message)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if err := t.setSessionHeaders(req); err != nil {
+		return err
+	}
+
+	resp, err := t.options.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if sessionID := resp.Header.Get(sessionIDHeader); sessionID != "" {
+		t.mu.Lock()
+		t.sessionID = sessionID
+		t.mu.Unlock()
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusAccepted:
+		return nil
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrSessionNotFound
+	case strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream"):
+		return readEventStream(resp.Body, func(id, data string) {
+			t.onMessage([]byte(data))
+		})
+	}
+
+	return deliverJSONResponse(resp, t.onMessage)
+}
+
+// Close 停止推送流并通过DELETE终止服务端会话
+func (t *StreamableHTTPTransport) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	if t.streamCancel != nil {
+		t.streamCancel()
+	}
+	sessionID := t.sessionID
+	t.mu.Unlock()
+
+	if sessionID == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cancelNotifyTimeout)
+	defer cancel()
+	req, err := t.options.newRequest(ctx, http.MethodDelete, t.endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if err := t.setSessionHeaders(req); err != nil {
+		return err
+	}
+
+	resp, err := t.options.httpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to terminate session: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// setSessionHeaders 设置会话与协议版本请求头
+func (t *StreamableHTTPTransport) setSessionHeaders(req *http.Request) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed && req.Method != http.MethodDelete {
+		return ErrTransportClosed
+	}
+	if t.sessionID != "" {
+		req.Header.Set(sessionIDHeader, t.sessionID)
+	}
+	if t.protocolVersion != "" {
+		req.Header.Set(protocolVersionHeader, t.protocolVersion)
+	}
+	return nil
+}
+
+// runEventStream 维持GET推送流，异常断开后重连，会话不存在时通知客户端断开
+func (t *StreamableHTTPTransport) runEventStream(ctx context.Context) {
+	for {
+		err := t.readEventStreamOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == ErrSessionNotFound {
+			if t.onClose != nil {
+				t.onClose(err)
+			}
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(sseReconnectDelay):
+		}
+	}
+}
+
+// readEventStreamOnce 打开一次推送流并读取到连接断开
+func (t *StreamableHTTPTransport) readEventStreamOnce(ctx context.Context) error {
+	req, err := t.options.newRequest(ctx, http.MethodGet, t.endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if err := t.setSessionHeaders(req); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	if t.lastEventID != "" {
+		req.Header.Set(lastEventIDHeader, t.lastEventID)
+	}
+	t.mu.Unlock()
+
+	resp, err := t.options.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return ErrSessionNotFound
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return unexpectedStatus(resp, body)
+	}
+
+	return readEventStream(resp.Body, func(id, data string) {
+		if id != "" {
+			t.mu.Lock()
+			t.lastEventID = id
+			t.mu.Unlock()
+		}
+		t.onMessage([]byte(data))
+	})
+}
+
+// readEventStream 解析SSE事件流，对每个携带数据的事件调用handle，直到流结束
+func readEventStream(r io.Reader, handle func(id, data string)) error {
+	reader := bufio.NewReader(r)
+	var id string
+	var data []string
+
+	for {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "" && err == nil:
+			// 空行结束一个事件
+			if len(data) > 0 {
+				handle(id, strings.Join(data, "\n"))
+			}
+			id, data = "", nil
+		case strings.HasPrefix(line, ":"):
+			// 注释（保活）
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "data":
+				data = append(data, value)
+			case "id":
+				id = value
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// deliverJSONResponse 交付JSON响应体
+// JSON-RPC错误响应（如400 Parse error）同样交付给客户端，其他非2xx响应返回错误
+func deliverJSONResponse(resp *http.Response, onMessage MessageHandler) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		return unexpectedStatus(resp, body)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices && !isJSONRPCBody(body) {
+		return unexpectedStatus(resp, body)
+	}
+	onMessage(body)
+	return nil
+}
+
+// isJSONRPCBody 响应体是否为JSON-RPC消息或批量响应
+func isJSONRPCBody(body []byte) bool {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		return true
+	}
+	var envelope struct {
+		JSONRPC string `json:"jsonrpc"`
+	}
+	return json.Unmarshal(body, &envelope) == nil && envelope.JSONRPC == "2.0"
+}
+
+// unexpectedStatus 非JSON-RPC响应的HTTP错误
+func unexpectedStatus(resp *http.Response, body []byte) error {
+	if len(body) > maxErrorBodySize {
+		body = body[:maxErrorBodySize]
+	}
+	return fmt.Errorf("unexpected HTTP status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
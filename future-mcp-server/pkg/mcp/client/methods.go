@@ -0,0 +1,264 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/mcp"
+)
+
+// ToolError 工具返回isError结果，Message为结果中的文本内容
+type ToolError struct {
+	Message string
+	Result  *mcp.ToolsCallResponse
+}
+
+// Error 实现error接口
+func (e *ToolError) Error() string {
+	return "tool error: " + e.Message
+}
+
+// Ping 检测连接是否可用
+func (c *Client) Ping(ctx context.Context) error {
+	return c.call(ctx, types.MCPMethodPing, nil, nil, nil)
+}
+
+// ==================== 工具 ====================
+
+// ListTools 获取一页工具列表，cursor为空时从第一页开始
+func (c *Client) ListTools(ctx context.Context, cursor string) (*mcp.ToolsListResponse, error) {
+	var result mcp.ToolsListResponse
+	if err := c.call(ctx, types.MCPMethodToolsList, paginatedParams(cursor), &result, nil); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListAllTools 按nextCursor翻页获取全部工具
+func (c *Client) ListAllTools(ctx context.Context) ([]mcp.Tool, error) {
+	var tools []mcp.Tool
+	cursor := ""
+	for {
+		page, err := c.ListTools(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, page.Tools...)
+		if page.NextCursor == "" {
+			return tools, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// CallTool 调用工具
+// 工具执行失败以IsError结果返回而非error，协议或传输错误返回error
+func (c *Client) CallTool(ctx context.Context, name string, arguments interface{}) (*mcp.ToolsCallResponse, error) {
+	return c.CallToolWithProgress(ctx, name, arguments, nil)
+}
+
+// CallToolWithProgress 调用工具，onProgress非nil时请求服务端推送执行进度
+// 取消ctx会通知服务端放弃执行；无状态HTTP传输不支持进度推送
+func (c *Client) CallToolWithProgress(ctx context.Context, name string, arguments interface{}, onProgress ProgressHandler) (*mcp.ToolsCallResponse, error) {
+	var result mcp.ToolsCallResponse
+	params := types.ToolsCallRequest{Name: name, Arguments: arguments}
+	if err := c.call(ctx, types.MCPMethodToolsCall, params, &result, onProgress); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CallTypedTool 调用声明了outputSchema的工具并将structuredContent解码为Out
+// 工具返回isError结果时返回*ToolError；未返回structuredContent时尝试解析首个文本内容
+func CallTypedTool[Out any](ctx context.Context, c *Client, name string, arguments interface{}, onProgress ProgressHandler) (*Out, error) {
+	result, err := c.CallToolWithProgress(ctx, name, arguments, onProgress)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsError {
+		return nil, &ToolError{Message: resultText(result), Result: result}
+	}
+
+	var data []byte
+	if result.StructuredContent != nil {
+		if data, err = json.Marshal(result.StructuredContent); err != nil {
+			return nil, fmt.Errorf("tool %s: failed to encode structured content: %w", name, err)
+		}
+	} else {
+		data = []byte(resultText(result))
+	}
+
+	output := new(Out)
+	if err := json.Unmarshal(data, output); err != nil {
+		return nil, fmt.Errorf("tool %s: failed to decode result: %w", name, err)
+	}
+	return output, nil
+}
+
+// resultText 拼接结果中的文本内容
+func resultText(result *mcp.ToolsCallResponse) string {
+	var texts []string
+	for _, content := range result.Content {
+		if content.Type == types.ContentTypeText {
+			texts = append(texts, content.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// ==================== 资源 ====================
+
+// ListResources 获取一页资源列表，cursor为空时从第一页开始
+func (c *Client) ListResources(ctx context.Context, cursor string) (*mcp.ResourcesListResponse, error) {
+	var result mcp.ResourcesListResponse
+	if err := c.call(ctx, types.MCPMethodResourcesList, paginatedParams(cursor), &result, nil); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListAllResources 按nextCursor翻页获取全部资源
+func (c *Client) ListAllResources(ctx context.Context) ([]mcp.Resource, error) {
+	var resources []mcp.Resource
+	cursor := ""
+	for {
+		page, err := c.ListResources(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, page.Resources...)
+		if page.NextCursor == "" {
+			return resources, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// ListResourceTemplates 获取资源模板列表
+func (c *Client) ListResourceTemplates(ctx context.Context) ([]mcp.ResourceTemplate, error) {
+	var result types.ResourcesTemplatesListResponse
+	if err := c.call(ctx, types.MCPMethodResourcesTemplatesList, nil, &result, nil); err != nil {
+		return nil, err
+	}
+	return result.ResourceTemplates, nil
+}
+
+// ReadResource 读取资源
+func (c *Client) ReadResource(ctx context.Context, uri string) (*mcp.ResourcesReadResponse, error) {
+	var result mcp.ResourcesReadResponse
+	if err := c.call(ctx, types.MCPMethodResourcesRead, types.ResourcesReadRequest{URI: uri}, &result, nil); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SubscribeResource 订阅资源变更，变更以notifications/resources/updated推送，通过OnResourceUpdated接收
+func (c *Client) SubscribeResource(ctx context.Context, uri string) error {
+	return c.call(ctx, types.MCPMethodResourcesSubscribe, types.ResourcesSubscribeRequest{URI: uri}, nil, nil)
+}
+
+// UnsubscribeResource 取消订阅资源变更
+func (c *Client) UnsubscribeResource(ctx context.Context, uri string) error {
+	return c.call(ctx, types.MCPMethodResourcesUnsubscribe, types.ResourcesUnsubscribeRequest{URI: uri}, nil, nil)
+}
+
+// ==================== 提示与日志 ====================
+
+// ListPrompts 获取一页提示模板列表，cursor为空时从第一页开始
+func (c *Client) ListPrompts(ctx context.Context, cursor string) (*mcp.PromptsListResponse, error) {
+	var result mcp.PromptsListResponse
+	if err := c.call(ctx, types.MCPMethodPromptsList, paginatedParams(cursor), &result, nil); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetPrompt 获取渲染后的提示模板
+func (c *Client) GetPrompt(ctx context.Context, name string, arguments map[string]string) (*mcp.PromptsGetResponse, error) {
+	var result mcp.PromptsGetResponse
+	params := types.PromptsGetRequest{Name: name, Arguments: arguments}
+	if err := c.call(ctx, types.MCPMethodPromptsGet, params, &result, nil); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SetLoggingLevel 设置服务端向本会话推送日志的最低级别
+func (c *Client) SetLoggingLevel(ctx context.Context, level mcp.LoggingLevel) error {
+	return c.call(ctx, types.MCPMethodLoggingSetLevel, types.LoggingSetLevelRequest{Level: level}, nil, nil)
+}
+
+// ==================== 通知与服务端请求 ====================
+
+// OnResourceUpdated 订阅资源更新通知
+func (c *Client) OnResourceUpdated(handler func(uri string)) {
+	c.OnNotification(types.MCPMethodResourcesUpdated, func(params json.RawMessage) {
+		var updated types.ResourcesUpdatedParams
+		if json.Unmarshal(params, &updated) == nil {
+			handler(updated.URI)
+		}
+	})
+}
+
+// OnLogMessage 订阅服务端日志通知
+func (c *Client) OnLogMessage(handler func(message mcp.LoggingMessageParams)) {
+	c.OnNotification(types.MCPMethodLoggingMessage, func(params json.RawMessage) {
+		var logMessage mcp.LoggingMessageParams
+		if json.Unmarshal(params, &logMessage) == nil {
+			handler(logMessage)
+		}
+	})
+}
+
+// OnListChanged 订阅工具、资源、提示模板列表变更通知，method为mcp.MethodToolsListChanged等list_changed方法
+func (c *Client) OnListChanged(method string, handler func()) {
+	c.OnNotification(method, func(json.RawMessage) {
+		handler()
+	})
+}
+
+// HandleSampling 注册sampling/createMessage处理器并在初始化时声明sampling能力，须在Initialize之前调用
+func (c *Client) HandleSampling(handler func(ctx context.Context, request *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error)) {
+	c.mu.Lock()
+	if c.config.Capabilities.Sampling == nil {
+		c.config.Capabilities.Sampling = &mcp.SamplingCapability{}
+	}
+	c.mu.Unlock()
+
+	c.HandleRequest(types.MCPMethodSamplingCreateMessage, func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var request mcp.CreateMessageRequest
+		if err := json.Unmarshal(params, &request); err != nil {
+			return nil, &RPCError{Code: types.MCPInvalidParams, Message: "invalid sampling request: " + err.Error()}
+		}
+		return handler(ctx, &request)
+	})
+}
+
+// HandleElicitation 注册elicitation/create处理器并在初始化时声明elicitation能力，须在Initialize之前调用
+// 处理器向用户展示request.RequestedSchema描述的表单，返回accept及填写内容，或decline/cancel
+func (c *Client) HandleElicitation(handler func(ctx context.Context, request *mcp.ElicitRequest) (*mcp.ElicitResult, error)) {
+	c.mu.Lock()
+	if c.config.Capabilities.Elicitation == nil {
+		c.config.Capabilities.Elicitation = &mcp.ElicitationCapability{}
+	}
+	c.mu.Unlock()
+
+	c.HandleRequest(types.MCPMethodElicitationCreate, func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var request mcp.ElicitRequest
+		if err := json.Unmarshal(params, &request); err != nil {
+			return nil, &RPCError{Code: types.MCPInvalidParams, Message: "invalid elicitation request: " + err.Error()}
+		}
+		return handler(ctx, &request)
+	})
+}
+
+// paginatedParams 分页请求参数，第一页不携带参数
+func paginatedParams(cursor string) interface{} {
+	if cursor == "" {
+		return nil
+	}
+	return &types.PaginatedRequest{Cursor: &cursor}
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// processExitTimeout 关闭stdin后等待子进程退出的时间，超时后强制结束
+const processExitTimeout = 5 * time.Second
+
+// StdioTransport stdio传输，按行交换JSON-RPC消息
+// 可直接使用一对读写流（如进程内管道），也可通过NewCommandTransport启动服务端子进程
+type StdioTransport struct {
+	reader io.Reader
+	writer io.Writer
+	cmd    *exec.Cmd
+
+	closed  bool
+	writeMu sync.Mutex
+	mu      sync.Mutex
+}
+
+// NewStdioTransport 在给定的读写流上创建stdio传输，writer实现io.Closer时Close会关闭它
+func NewStdioTransport(reader io.Reader, writer io.Writer) *StdioTransport {
+	return &StdioTransport{reader: reader, writer: writer}
+}
+
+// NewCommandTransport 创建以子进程运行服务端的stdio传输，Start时启动进程
+// 未设置cmd.Stderr时子进程日志被丢弃
+func NewCommandTransport(cmd *exec.Cmd) *StdioTransport {
+	return &StdioTransport{cmd: cmd}
+}
+
+// Start 启动子进程（如有）和接收goroutine
+func (t *StdioTransport) Start(ctx context.Context, onMessage MessageHandler, onClose CloseHandler) error {
+	if t.cmd != nil {
+		stdin, err := t.cmd.StdinPipe()
+		if err != nil {
+			return fmt.Errorf("failed to open stdin: %w", err)
+		}
+		stdout, err := t.cmd.StdoutPipe()
+		if err != nil {
+			return fmt.Errorf("failed to open stdout: %w", err)
+		}
+		if err := t.cmd.Start(); err != nil {
+			return fmt.Errorf("failed to start server process: %w", err)
+		}
+		t.reader, t.writer = stdout, stdin
+	}
+
+	go t.readLoop(onMessage, onClose)
+	return nil
+}
+
+// readLoop 逐行读取服务端消息，直到流结束
+func (t *StdioTransport) readLoop(onMessage MessageHandler, onClose CloseHandler) {
+	reader := bufio.NewReaderSize(t.reader, 64*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			onMessage(line)
+		}
+		if err != nil {
+			t.mu.Lock()
+			closed := t.closed
+			t.mu.Unlock()
+			if !closed && onClose != nil {
+				if err == io.EOF {
+					err = errors.New("server closed stdout")
+				}
+				onClose(err)
+			}
+			return
+		}
+	}
+}
+
+// Send 写入一行消息
+func (t *StdioTransport) Send(ctx context.Context, message []byte) error {
+	t.mu.Lock()
+	closed := t.closed
+	t.mu.Unlock()
+	if closed || t.writer == nil {
+		return ErrTransportClosed
+	}
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.writer.Write(append(message, '\n')); err != nil {
+		return fmt.Errorf("failed to write stdio message: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭输入流，子进程在超时内未退出时强制结束
+func (t *StdioTransport) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	t.mu.Unlock()
+
+	var err error
+	if closer, ok := t.writer.(io.Closer); ok {
+		err = closer.Close()
+	}
+	if t.cmd == nil || t.cmd.Process == nil {
+		return err
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- t.cmd.Wait()
+	}()
+
+	select {
+	case <-exited:
+	case <-time.After(processExitTimeout):
+		_ = t.cmd.Process.Kill()
+		<-exited
+	}
+	return err
+}
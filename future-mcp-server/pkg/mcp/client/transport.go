@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrTransportClosed 传输已关闭
+	ErrTransportClosed = errors.New("transport closed")
+	// ErrSessionNotFound 服务端会话不存在或已过期，需要重新初始化
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// MessageHandler 收到一条JSON-RPC消息（或批量响应数组）时的回调
+type MessageHandler func(message []byte)
+
+// CloseHandler 传输因连接断开、子进程退出等原因不可再用时的回调，主动Close时不触发
+type CloseHandler func(err error)
+
+// Transport 客户端传输
+// Send可能在返回前通过MessageHandler交付该请求的响应及相关通知（如HTTP传输），
+// 也可能立即返回、由接收goroutine异步交付（如WebSocket、stdio）。
+type Transport interface {
+	// Start 建立连接并开始接收消息
+	Start(ctx context.Context, onMessage MessageHandler, onClose CloseHandler) error
+	// Send 发送一条已编码的JSON-RPC消息
+	Send(ctx context.Context, message []byte) error
+	// Close 关闭传输并释放连接
+	Close() error
+}
+
+// ProtocolVersionSetter 需要在初始化后随请求携带协商协议版本的传输
+type ProtocolVersionSetter interface {
+	SetProtocolVersion(version string)
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// wsSubprotocol 服务端声明的WebSocket子协议
+	wsSubprotocol = "mcp"
+	// wsWriteWait 单次写入超时
+	wsWriteWait = 10 * time.Second
+	// wsMaxMessageSize 单条消息最大字节数
+	wsMaxMessageSize = 4 * 1024 * 1024
+)
+
+// WebSocketTransport WebSocket传输（GET /mcp/ws）
+// 一个连接对应一个服务端会话，请求、响应、通知与取消在同一连接上复用；服务端ping由连接自动回复pong
+type WebSocketTransport struct {
+	url    string
+	header http.Header
+	dialer *websocket.Dialer
+
+	conn    *websocket.Conn
+	closed  bool
+	writeMu sync.Mutex
+	mu      sync.Mutex
+}
+
+// NewWebSocketTransport 创建WebSocket传输，header附加到握手请求
+func NewWebSocketTransport(url string, header http.Header) *WebSocketTransport {
+	return &WebSocketTransport{
+		url:    url,
+		header: header,
+		dialer: &websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: 10 * time.Second,
+			Subprotocols:     []string{wsSubprotocol},
+		},
+	}
+}
+
+// Start 建立连接并启动接收goroutine
+func (t *WebSocketTransport) Start(ctx context.Context, onMessage MessageHandler, onClose CloseHandler) error {
+	conn, resp, err := t.dialer.DialContext(ctx, t.url, t.header)
+	if err != nil {
+		if resp != nil {
+			return fmt.Errorf("websocket handshake failed with status %d: %w", resp.StatusCode, err)
+		}
+		return fmt.Errorf("websocket dial failed: %w", err)
+	}
+	conn.SetReadLimit(wsMaxMessageSize)
+
+	t.mu.Lock()
+	t.conn = conn
+	t.mu.Unlock()
+
+	go t.readLoop(conn, onMessage, onClose)
+	return nil
+}
+
+// readLoop 读取服务端消息直到连接关闭
+func (t *WebSocketTransport) readLoop(conn *websocket.Conn, onMessage MessageHandler, onClose CloseHandler) {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.mu.Lock()
+			closed := t.closed
+			t.mu.Unlock()
+			if !closed && onClose != nil {
+				onClose(err)
+			}
+			return
+		}
+		onMessage(data)
+	}
+}
+
+// Send 写入一条文本消息
+func (t *WebSocketTransport) Send(ctx context.Context, message []byte) error {
+	t.mu.Lock()
+	conn, closed := t.conn, t.closed
+	t.mu.Unlock()
+	if conn == nil || closed {
+		return ErrTransportClosed
+	}
+
+	deadline := time.Now().Add(wsWriteWait)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	_ = conn.SetWriteDeadline(deadline)
+	return conn.WriteMessage(websocket.TextMessage, message)
+}
+
+// Close 发送关闭帧并断开连接
+func (t *WebSocketTransport) Close() error {
+	t.mu.Lock()
+	if t.closed || t.conn == nil {
+		t.closed = true
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	conn := t.conn
+	t.mu.Unlock()
+
+	t.writeMu.Lock()
+	_ = conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(wsWriteWait))
+	t.writeMu.Unlock()
+	return conn.Close()
+}
@@ -0,0 +1,290 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+// 补全匹配得分，得分越高排序越靠前
+const (
+	completionScoreExact           = 100
+	completionScorePrefix          = 80
+	completionScorePinyinPrefix    = 60
+	completionScoreSubstring       = 40
+	completionScorePinyinSubstring = 20
+	completionScoreEmptyInput      = 1
+)
+
+// CompletionCandidate 补全候选值，Labels为参与匹配的显示名称（如年级中文名、素材标题）
+type CompletionCandidate struct {
+	Value  string
+	Labels []string
+}
+
+// ArgumentCompleter 按参数名提供的补全能力，提示模板、资源模板与工具中的同名参数共用
+type ArgumentCompleter struct {
+	// Candidates 参数定义中没有声明可选值时提供候选值，contextArgs为已填写的其他参数
+	Candidates func(ctx context.Context, contextArgs map[string]string) []CompletionCandidate
+	// Label 可选值的显示名称，用于按名称或拼音首字母匹配，也用作征询表单中的选项名称
+	Label func(value string) string
+	// List 参数为逗号分隔的列表（如material_ids），只补全最后一项
+	List bool
+}
+
+// RegisterCompleter 为参数名注册补全能力，重复注册时覆盖
+func (s *Server) RegisterCompleter(argName string, completer *ArgumentCompleter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.completers[argName] = completer
+}
+
+// completer 获取参数名对应的补全能力，未注册时返回nil
+func (s *Server) completer(argName string) *ArgumentCompleter {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.completers[argName]
+}
+
+// optionLabel 可选值的显示名称，未注册显示名称时返回空字符串
+func (s *Server) optionLabel(argName, value string) string {
+	if completer := s.completer(argName); completer != nil && completer.Label != nil {
+		return completer.Label(value)
+	}
+	return ""
+}
+
+// handleCompletionComplete 处理参数补全请求
+func (s *Server) handleCompletionComplete(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	completeReq := &types.CompletionCompleteRequest{}
+	if err := parseParams(request.Params, completeReq); err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	var contextArgs map[string]string
+	if completeReq.Context != nil {
+		contextArgs = completeReq.Context.Arguments
+	}
+
+	candidates, err := s.completionCandidates(ctx, completeReq.Ref, completeReq.Argument.Name, contextArgs)
+	if err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	// 列表型参数只补全最后一项
+	prefix, input := "", completeReq.Argument.Value
+	if completer := s.completer(completeReq.Argument.Name); completer != nil && completer.List {
+		if i := strings.LastIndex(input, ","); i >= 0 {
+			prefix, input = input[:i+1], strings.TrimSpace(input[i+1:])
+		}
+	}
+
+	values := rankCompletions(candidates, input)
+	for i := range values {
+		values[i] = prefix + values[i]
+	}
+
+	result := types.CompletionResult{
+		Values: values,
+		Total:  len(values),
+	}
+	if len(values) > types.CompletionMaxValues {
+		result.Values = values[:types.CompletionMaxValues]
+		result.HasMore = true
+	}
+
+	return createSuccessResponse(request.ID, &types.CompletionCompleteResponse{
+		Completion: result,
+	})
+}
+
+// completionCandidates 收集参数的候选值：优先使用定义中的可选值，否则使用按参数名注册的补全能力
+func (s *Server) completionCandidates(ctx context.Context, ref types.CompletionReference, argName string, contextArgs map[string]string) ([]CompletionCandidate, error) {
+	switch ref.Type {
+	case types.CompletionRefPrompt:
+		prompt := s.promptRegistry.GetPrompt(ref.Name)
+		if prompt == nil {
+			return nil, fmt.Errorf("prompt not found: %s", ref.Name)
+		}
+		for _, arg := range prompt.Arguments {
+			if arg.Name != argName {
+				continue
+			}
+			if len(arg.Enum) > 0 {
+				return s.enumCompletionCandidates(argName, arg.Enum), nil
+			}
+			return s.dynamicCompletionCandidates(ctx, argName, contextArgs), nil
+		}
+		return nil, fmt.Errorf("unknown argument %s for prompt %s", argName, ref.Name)
+
+	case types.CompletionRefResource:
+		template, variables := s.resourceRegistry.GetTemplate(ref.URI)
+		if template == nil {
+			return nil, fmt.Errorf("resource template not found: %s", ref.URI)
+		}
+		if !containsString(variables, argName) {
+			return nil, fmt.Errorf("unknown variable %s for resource template %s", argName, ref.URI)
+		}
+		if options := template.Completions[argName]; len(options) > 0 {
+			return s.enumCompletionCandidates(argName, options), nil
+		}
+		return s.dynamicCompletionCandidates(ctx, argName, contextArgs), nil
+
+	case types.CompletionRefTool:
+		tool := s.toolRegistry.GetTool(ref.Name)
+		if tool == nil {
+			return nil, fmt.Errorf("tool not found: %s", ref.Name)
+		}
+		property, ok := schemaProperty(tool.InputSchema, argName)
+		if !ok {
+			return nil, fmt.Errorf("unknown argument %s for tool %s", argName, ref.Name)
+		}
+		if options := schemaEnum(property); len(options) > 0 {
+			return s.enumCompletionCandidates(argName, options), nil
+		}
+		return s.dynamicCompletionCandidates(ctx, argName, contextArgs), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported completion reference type: %s", ref.Type)
+	}
+}
+
+// dynamicCompletionCandidates 按参数名获取注册的候选值，未注册的参数没有候选值
+func (s *Server) dynamicCompletionCandidates(ctx context.Context, argName string, contextArgs map[string]string) []CompletionCandidate {
+	completer := s.completer(argName)
+	if completer == nil || completer.Candidates == nil {
+		return nil
+	}
+	return completer.Candidates(ctx, contextArgs)
+}
+
+// enumCompletionCandidates 将定义中的可选值转换为候选值，附带注册的显示名称以便按名称或拼音匹配
+func (s *Server) enumCompletionCandidates(argName string, options []string) []CompletionCandidate {
+	completer := s.completer(argName)
+	if completer == nil {
+		return EnumCompletionCandidates(options, nil)
+	}
+	return EnumCompletionCandidates(options, completer.Label)
+}
+
+// EnumCompletionCandidates 将可选值转换为候选值，label不为nil时附带显示名称
+func EnumCompletionCandidates(options []string, label func(string) string) []CompletionCandidate {
+	candidates := make([]CompletionCandidate, len(options))
+	for i, option := range options {
+		candidates[i] = CompletionCandidate{Value: option}
+		if label == nil {
+			continue
+		}
+		if name := label(option); name != "" && name != option {
+			candidates[i].Labels = []string{name}
+		}
+	}
+	return candidates
+}
+
+// rankCompletions 按匹配程度排序并去重：完全匹配 > 前缀匹配 > 拼音首字母前缀 > 包含 > 拼音首字母包含
+// 得分相同时保持候选值原有顺序，输入为空时返回全部候选值
+func rankCompletions(candidates []CompletionCandidate, input string) []string {
+	input = strings.ToLower(strings.TrimSpace(input))
+
+	type scored struct {
+		value string
+		score int
+	}
+
+	seen := make(map[string]bool, len(candidates))
+	matches := make([]scored, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.Value == "" || seen[candidate.Value] {
+			continue
+		}
+
+		score := completionScoreEmptyInput
+		if input != "" {
+			score = 0
+			for _, key := range append([]string{candidate.Value}, candidate.Labels...) {
+				if keyScore := completionScore(key, input); keyScore > score {
+					score = keyScore
+				}
+			}
+		}
+		if score == 0 {
+			continue
+		}
+
+		seen[candidate.Value] = true
+		matches = append(matches, scored{value: candidate.Value, score: score})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	values := make([]string, len(matches))
+	for i, match := range matches {
+		values[i] = match.value
+	}
+	return values
+}
+
+// completionScore 计算单个匹配键的得分，input已转为小写
+func completionScore(key, input string) int {
+	key = strings.ToLower(key)
+	switch {
+	case key == input:
+		return completionScoreExact
+	case strings.HasPrefix(key, input):
+		return completionScorePrefix
+	}
+
+	initials := pinyinInitials(key)
+	switch {
+	case initials != key && strings.HasPrefix(initials, input):
+		return completionScorePinyinPrefix
+	case strings.Contains(key, input):
+		return completionScoreSubstring
+	case initials != key && strings.Contains(initials, input):
+		return completionScorePinyinSubstring
+	default:
+		return 0
+	}
+}
+
+// schemaProperty 获取JSON Schema中的属性定义
+func schemaProperty(schema interface{}, name string) (map[string]interface{}, bool) {
+	schemaMap, ok := schema.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	properties, ok := schemaMap["properties"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	property, ok := properties[name].(map[string]interface{})
+	return property, ok
+}
+
+// schemaEnum 获取属性的可选值，数组类型取元素的可选值
+func schemaEnum(property map[string]interface{}) []string {
+	if items, ok := property["items"].(map[string]interface{}); ok {
+		if options := schemaEnum(items); len(options) > 0 {
+			return options
+		}
+	}
+
+	switch enum := property["enum"].(type) {
+	case []string:
+		return enum
+	case []interface{}:
+		options := make([]string, 0, len(enum))
+		for _, option := range enum {
+			options = append(options, fmt.Sprint(option))
+		}
+		return options
+	default:
+		return nil
+	}
+}
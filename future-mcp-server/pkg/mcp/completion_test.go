@@ -0,0 +1,185 @@
+package mcp
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+func TestPinyinInitials(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"一元二次方程", "yyecfc"},
+		{"勾股定理", "ggdl"},
+		{"浮力", "fl"},
+		{"初中数学", "czsx"},
+		{"Grade 3 数学", "grade3sx"},
+		{"二次函数（进阶）", "echsjj"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := pinyinInitials(tt.text); got != tt.want {
+				t.Fatalf("pinyinInitials(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankCompletions(t *testing.T) {
+	candidates := []CompletionCandidate{
+		{Value: "一元一次方程"},
+		{Value: "一元二次方程"},
+		{Value: "二次函数"},
+		{Value: "grade_3", Labels: []string{"三年级"}},
+		{Value: "一元二次方程"},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"empty input keeps order and dedups", "", []string{"一元一次方程", "一元二次方程", "二次函数", "grade_3"}},
+		{"chinese prefix", "一元", []string{"一元一次方程", "一元二次方程"}},
+		{"pinyin initials prefix", "yye", []string{"一元二次方程"}},
+		{"prefix ranks before substring", "二次", []string{"二次函数", "一元二次方程"}},
+		{"pinyin initials substring", "ecf", []string{"一元二次方程"}},
+		{"label pinyin", "snj", []string{"grade_3"}},
+		{"exact ranks first", "二次函数", []string{"二次函数"}},
+		{"no match", "xyz", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rankCompletions(candidates, tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("rankCompletions(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompletionComplete(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	subjects := map[string]string{"math": "数学", "physics": "物理"}
+	s.RegisterCompleter("subject", &ArgumentCompleter{Label: func(value string) string { return subjects[value] }})
+	s.RegisterCompleter("topic", &ArgumentCompleter{
+		Candidates: func(ctx context.Context, contextArgs map[string]string) []CompletionCandidate {
+			if contextArgs["subject"] == "physics" {
+				return EnumCompletionCandidates([]string{"浮力", "杠杆"}, nil)
+			}
+			return EnumCompletionCandidates([]string{"方程", "函数"}, nil)
+		},
+	})
+	s.RegisterCompleter("ids", &ArgumentCompleter{
+		List: true,
+		Candidates: func(ctx context.Context, contextArgs map[string]string) []CompletionCandidate {
+			return EnumCompletionCandidates([]string{"m-1", "m-2"}, nil)
+		},
+	})
+	s.Prompts().RegisterPrompt(&types.PromptDefinition{
+		Name: "lesson",
+		Arguments: []types.PromptArgumentDefinition{
+			{Name: "subject", Enum: []string{"math", "physics"}},
+			{Name: "topic"},
+		},
+	})
+	if err := s.Resources().RegisterTemplate(&types.ResourceTemplateDefinition{
+		URITemplate: "graph://subject-{subject}/level-{level}",
+		Completions: map[string][]string{"level": {"primary", "junior"}},
+	}); err != nil {
+		t.Fatalf("RegisterTemplate: %v", err)
+	}
+	s.Tools().RegisterTool(&types.ToolDefinition{
+		Name: "bundle",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"ids": map[string]interface{}{"type": "string"}},
+		},
+	})
+
+	tests := []struct {
+		name     string
+		ref      map[string]string
+		argument string
+		value    string
+		context  map[string]string
+		want     []string
+		wantCode int
+	}{
+		{
+			name:     "prompt enum by label pinyin",
+			ref:      map[string]string{"type": types.CompletionRefPrompt, "name": "lesson"},
+			argument: "subject",
+			value:    "wl",
+			want:     []string{"physics"},
+		},
+		{
+			name:     "dynamic candidates use context",
+			ref:      map[string]string{"type": types.CompletionRefPrompt, "name": "lesson"},
+			argument: "topic",
+			value:    "fl",
+			context:  map[string]string{"subject": "physics"},
+			want:     []string{"浮力"},
+		},
+		{
+			name:     "resource template variable",
+			ref:      map[string]string{"type": types.CompletionRefResource, "uri": "graph://subject-{subject}/level-{level}"},
+			argument: "level",
+			value:    "ju",
+			want:     []string{"junior"},
+		},
+		{
+			name:     "tool list argument completes last item",
+			ref:      map[string]string{"type": types.CompletionRefTool, "name": "bundle"},
+			argument: "ids",
+			value:    "m-1, m-2",
+			want:     []string{"m-1,m-2"},
+		},
+		{
+			name:     "unknown prompt",
+			ref:      map[string]string{"type": types.CompletionRefPrompt, "name": "missing"},
+			argument: "subject",
+			wantCode: types.MCPInvalidParams,
+		},
+		{
+			name:     "unknown argument",
+			ref:      map[string]string{"type": types.CompletionRefPrompt, "name": "lesson"},
+			argument: "missing",
+			wantCode: types.MCPInvalidParams,
+		},
+		{
+			name:     "unsupported reference type",
+			ref:      map[string]string{"type": "ref/unknown"},
+			argument: "subject",
+			wantCode: types.MCPInvalidParams,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := map[string]interface{}{
+				"ref":      tt.ref,
+				"argument": map[string]string{"name": tt.argument, "value": tt.value},
+			}
+			if tt.context != nil {
+				params["context"] = map[string]interface{}{"arguments": tt.context}
+			}
+
+			response := handle(t, s, context.Background(), 1, types.MCPMethodCompletionComplete, params)
+			if code := errorCode(response); code != tt.wantCode {
+				t.Fatalf("error code = %d, want %d (%+v)", code, tt.wantCode, response.Error)
+			}
+			if tt.wantCode != 0 {
+				return
+			}
+
+			result := response.Result.(*types.CompletionCompleteResponse)
+			if !reflect.DeepEqual(result.Completion.Values, tt.want) {
+				t.Fatalf("values = %v, want %v", result.Completion.Values, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+// TextContent 文本内容块
+func TextContent(text string) types.Content {
+	return types.Content{Type: types.ContentTypeText, Text: text}
+}
+
+// AudioContent 音频内容块，数据以base64编码
+func AudioContent(data []byte, mimeType string) types.Content {
+	return types.Content{
+		Type:     types.ContentTypeAudio,
+		Data:     base64.StdEncoding.EncodeToString(data),
+		MimeType: mimeType,
+	}
+}
+
+// EmbeddedResourceContent 嵌入资源内容块
+func EmbeddedResourceContent(resource types.ResourceContent) types.Content {
+	return types.Content{Type: types.ContentTypeResource, Resource: &resource}
+}
+
+// ResourceLinkContent 资源链接内容块，客户端可按需通过resources/read或URL获取资源
+func ResourceLinkContent(uri, name, description, mimeType string, size int64) types.Content {
+	return types.Content{
+		Type:        types.ContentTypeResourceLink,
+		URI:         uri,
+		Name:        name,
+		Description: description,
+		MimeType:    mimeType,
+		Size:        size,
+	}
+}
+
+// validateContent 校验内容块是否包含其类型所需的字段
+func validateContent(content types.Content) error {
+	switch content.Type {
+	case types.ContentTypeText:
+		return nil
+	case types.ContentTypeImage, types.ContentTypeAudio:
+		if content.Data == "" || content.MimeType == "" {
+			return fmt.Errorf("%s content requires data and mimeType", content.Type)
+		}
+		if _, err := base64.StdEncoding.DecodeString(content.Data); err != nil {
+			return fmt.Errorf("%s content data must be base64 encoded", content.Type)
+		}
+	case types.ContentTypeResource:
+		if content.Resource == nil || content.Resource.URI == "" {
+			return errors.New("resource content requires resource.uri")
+		}
+		if content.Resource.Text != "" && content.Resource.Blob != "" {
+			return errors.New("resource content must not contain both text and blob")
+		}
+	case types.ContentTypeResourceLink:
+		if content.URI == "" || content.Name == "" {
+			return errors.New("resource_link content requires uri and name")
+		}
+	default:
+		return fmt.Errorf("unsupported content type: %s", content.Type)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+func TestValidateContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content types.Content
+		wantErr bool
+	}{
+		{"text", TextContent("hello"), false},
+		{"image", types.Content{Type: types.ContentTypeImage, Data: "aGVsbG8=", MimeType: "image/png"}, false},
+		{"audio", AudioContent([]byte("hello"), "audio/mpeg"), false},
+		{"audio without mime type", types.Content{Type: types.ContentTypeAudio, Data: "aGVsbG8="}, true},
+		{"image not base64", types.Content{Type: types.ContentTypeImage, Data: "not base64!", MimeType: "image/png"}, true},
+		{"embedded resource", EmbeddedResourceContent(types.ResourceContent{URI: "material://1", Text: "{}"}), false},
+		{"embedded resource without uri", EmbeddedResourceContent(types.ResourceContent{Text: "{}"}), true},
+		{"embedded resource with text and blob", EmbeddedResourceContent(types.ResourceContent{URI: "material://1", Text: "a", Blob: "YQ=="}), true},
+		{"resource link", ResourceLinkContent("material://1", "素材", "", "audio/mpeg", 10), false},
+		{"resource link without name", ResourceLinkContent("material://1", "", "", "", 0), true},
+		{"unknown type", types.Content{Type: "video"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateContent(tt.content); (err != nil) != tt.wantErr {
+				t.Fatalf("validateContent err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidCursor 游标格式错误、签名不匹配或不属于当前列表/查询
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursorSignatureSize 游标签名截取的字节数
+const cursorSignatureSize = 16
+
+// CursorCodec 不透明分页游标编解码器
+// 游标由base64url编码的JSON载荷与HMAC-SHA256签名组成，客户端无法伪造或篡改；
+// 载荷绑定列表范围与查询条件摘要，不能跨列表或跨查询复用
+type CursorCodec struct {
+	key []byte
+}
+
+// Cursor 游标载荷
+// 键集分页使用After（上一页最后一项的排序键），偏移分页使用Offset与Limit
+type Cursor struct {
+	Scope  string `json:"s"`
+	Filter string `json:"f,omitempty"`
+	After  string `json:"a,omitempty"`
+	Offset int    `json:"o,omitempty"`
+	Limit  int    `json:"l,omitempty"`
+}
+
+// NewCursorCodec 创建游标编解码器，secret为空时使用随机密钥（服务重启后旧游标失效）
+func NewCursorCodec(secret string) *CursorCodec {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic("failed to generate cursor key: " + err.Error())
+		}
+	}
+	return &CursorCodec{key: key}
+}
+
+// Encode 编码并签名游标
+func (c *CursorCodec) Encode(cursor Cursor) string {
+	payload, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(c.sign(payload))
+}
+
+// Decode 校验签名并解码游标，范围或查询条件不一致时返回ErrInvalidCursor
+func (c *CursorCodec) Decode(token, scope, filter string) (*Cursor, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+		return nil, ErrInvalidCursor
+	}
+
+	cursor := &Cursor{}
+	if err := json.Unmarshal(payload, cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.Scope != scope || cursor.Filter != filter || cursor.Offset < 0 || cursor.Limit < 0 {
+		return nil, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+// sign 计算载荷签名
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(payload)
+	return mac.Sum(nil)[:cursorSignatureSize]
+}
+
+// CursorFilter 计算查询条件摘要，用于将游标绑定到生成它的查询
+func CursorFilter(query interface{}) string {
+	data, _ := json.Marshal(query)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// paginateByKey 键集分页：按key升序排序后返回游标之后的limit项，以及下一页游标（无下一页时为空）
+// 分页期间新增或删除的条目不会导致重复或遗漏已返回之外的条目
+func paginateByKey[T any](codec *CursorCodec, scope string, items []T, key func(T) string, token string, limit int) ([]T, string, error) {
+	sort.Slice(items, func(i, j int) bool {
+		return key(items[i]) < key(items[j])
+	})
+
+	start := 0
+	if token != "" {
+		cursor, err := codec.Decode(token, scope, "")
+		if err != nil {
+			return nil, "", err
+		}
+		start = sort.Search(len(items), func(i int) bool {
+			return key(items[i]) > cursor.After
+		})
+	}
+
+	end := start + limit
+	if end >= len(items) {
+		return items[start:], "", nil
+	}
+
+	page := items[start:end]
+	return page, codec.Encode(Cursor{Scope: scope, After: key(page[len(page)-1])}), nil
+}
@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+// tamperCursor 修改游标载荷但保留原签名
+func tamperCursor(t *testing.T, token string, modify func(payload string) string) string {
+	t.Helper()
+
+	encodedPayload, signature, _ := strings.Cut(token, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(modify(string(payload)))) + "." + signature
+}
+
+func TestCursorCodec(t *testing.T) {
+	codec := NewCursorCodec("secret")
+	cursor := Cursor{Scope: "materials", Filter: CursorFilter(map[string]string{"q": "方程"}), After: "m2", Limit: 20}
+	token := codec.Encode(cursor)
+
+	decoded, err := codec.Decode(token, cursor.Scope, cursor.Filter)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if !reflect.DeepEqual(*decoded, cursor) {
+		t.Fatalf("Decode = %+v, want %+v", *decoded, cursor)
+	}
+
+	encodedPayload, signature, _ := strings.Cut(token, ".")
+	tests := []struct {
+		name   string
+		codec  *CursorCodec
+		token  string
+		scope  string
+		filter string
+	}{
+		{"tampered payload", codec, tamperCursor(t, token, func(p string) string { return strings.Replace(p, "m2", "m9", 1) }), cursor.Scope, cursor.Filter},
+		{"tampered signature", codec, encodedPayload + "." + strings.Repeat("A", len(signature)), cursor.Scope, cursor.Filter},
+		{"missing signature", codec, encodedPayload, cursor.Scope, cursor.Filter},
+		{"malformed", codec, "!!!.???", cursor.Scope, cursor.Filter},
+		{"other key", NewCursorCodec("other"), token, cursor.Scope, cursor.Filter},
+		{"other scope", codec, token, "tools/list", cursor.Filter},
+		{"other query", codec, token, cursor.Scope, CursorFilter(map[string]string{"q": "函数"})},
+		{"negative offset", codec, codec.Encode(Cursor{Scope: "materials", Offset: -1}), "materials", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.codec.Decode(tt.token, tt.scope, tt.filter); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("Decode err = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+// listToolNames 请求一页tools/list，返回工具名、下一页游标与JSON-RPC错误
+func listToolNames(t *testing.T, s *Server, cursor string) ([]string, string, *types.MCPError) {
+	t.Helper()
+
+	params := types.PaginatedRequest{}
+	if cursor != "" {
+		params.Cursor = &cursor
+	}
+	response, err := s.HandleRequest(context.Background(), &types.MCPRequest{
+		MCPMessage: types.MCPMessage{JSONRPC: "2.0", ID: 1},
+		Method:     types.MCPMethodToolsList,
+		Params:     params,
+	})
+	if err != nil {
+		t.Fatalf("HandleRequest: %v", err)
+	}
+	if response.Error != nil {
+		return nil, "", response.Error
+	}
+
+	result := response.Result.(*types.ToolsListResponse)
+	names := make([]string, len(result.Tools))
+	for i, tool := range result.Tools {
+		names[i] = tool.Name
+	}
+	return names, result.NextCursor, nil
+}
+
+func registerNamedTools(s *Server, names ...string) {
+	for _, name := range names {
+		s.Tools().RegisterTool(&types.ToolDefinition{Name: name, InputSchema: map[string]interface{}{"type": "object"}})
+	}
+}
+
+func TestToolsListKeysetPagination(t *testing.T) {
+	s := NewServer(ServerConfig{ListPageSize: 2, Cursors: NewCursorCodec("secret")})
+	for name := range s.Tools().ListTools() {
+		s.Tools().RemoveTool(name)
+	}
+	registerNamedTools(s, "e", "a", "d", "b", "c")
+
+	first, cursor, rpcErr := listToolNames(t, s, "")
+	if rpcErr != nil || !reflect.DeepEqual(first, []string{"a", "b"}) || cursor == "" {
+		t.Fatalf("first page = %v, cursor %q, error %v", first, cursor, rpcErr)
+	}
+
+	// 翻页期间新增与删除条目：已返回之前的变化不影响后续页，不重复也不遗漏
+	registerNamedTools(s, "aa", "bb")
+	s.Tools().RemoveTool("c")
+
+	var rest []string
+	for cursor != "" {
+		var page []string
+		page, cursor, rpcErr = listToolNames(t, s, cursor)
+		if rpcErr != nil {
+			t.Fatalf("list page: %v", rpcErr)
+		}
+		rest = append(rest, page...)
+	}
+	if !reflect.DeepEqual(rest, []string{"bb", "d", "e"}) {
+		t.Fatalf("remaining pages = %v", rest)
+	}
+
+	// 篡改或跨列表使用的游标被拒绝
+	_, cursor, _ = listToolNames(t, s, "")
+	tampered := tamperCursor(t, cursor, func(p string) string { return strings.Replace(p, `"a":"aa"`, `"a":"c"`, 1) })
+	foreign := s.cursors.Encode(Cursor{Scope: types.MCPMethodPromptsList, After: "aa"})
+	for _, invalid := range []string{tampered, foreign, "not-a-cursor"} {
+		if _, _, rpcErr := listToolNames(t, s, invalid); rpcErr == nil || rpcErr.Code != types.MCPInvalidParams {
+			t.Fatalf("cursor %q: error = %v, want invalid params", invalid, rpcErr)
+		}
+	}
+}
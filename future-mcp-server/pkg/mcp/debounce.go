@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"sync"
+	"time"
+)
+
+// debouncer 合并短时间内的多次触发：首次触发后等待delay再执行一次，等待期间的触发被合并
+// 与每次触发都重置计时的做法不同，持续的变更也能在delay内得到执行
+type debouncer struct {
+	delay   time.Duration
+	fn      func()
+	pending bool
+	mu      sync.Mutex
+}
+
+// newDebouncer 创建防抖器，delay不大于0时每次触发立即执行
+func newDebouncer(delay time.Duration, fn func()) *debouncer {
+	return &debouncer{delay: delay, fn: fn}
+}
+
+// Trigger 触发一次执行
+func (d *debouncer) Trigger() {
+	if d.delay <= 0 {
+		d.fn()
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.pending {
+		return
+	}
+	d.pending = true
+
+	time.AfterFunc(d.delay, func() {
+		d.mu.Lock()
+		d.pending = false
+		d.mu.Unlock()
+
+		d.fn()
+	})
+}
@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+func TestDebouncerCoalescesTriggers(t *testing.T) {
+	var calls atomic.Int32
+	d := newDebouncer(50*time.Millisecond, func() { calls.Add(1) })
+
+	for i := 0; i < 10; i++ {
+		d.Trigger()
+	}
+	if got := calls.Load(); got != 0 {
+		t.Fatalf("calls before delay = %d, want 0", got)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("calls after burst = %d, want 1", got)
+	}
+
+	// 窗口结束后的触发开启新的窗口
+	d.Trigger()
+	time.Sleep(150 * time.Millisecond)
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("calls after second trigger = %d, want 2", got)
+	}
+}
+
+func TestDebouncerWithoutDelay(t *testing.T) {
+	var calls atomic.Int32
+	d := newDebouncer(0, func() { calls.Add(1) })
+
+	d.Trigger()
+	d.Trigger()
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("calls = %d, want 2", got)
+	}
+}
+
+// drainListChanged 在wait时间内收集会话收到的list_changed通知方法名
+func drainListChanged(session *Session, wait time.Duration) []string {
+	var methods []string
+	timeout := time.After(wait)
+	for {
+		select {
+		case event := <-session.Events():
+			if notification, ok := event.Message.(*types.MCPNotification); ok {
+				methods = append(methods, notification.Method)
+			}
+		case <-timeout:
+			return methods
+		}
+	}
+}
+
+func TestListChangedBroadcast(t *testing.T) {
+	noop := func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) { return nil, nil }
+
+	tests := []struct {
+		name   string
+		change func(s *Server)
+		want   []string
+	}{
+		{
+			name: "tool burst coalesced",
+			change: func(s *Server) {
+				s.Tools().RegisterTool(&types.ToolDefinition{Name: "a", Handler: noop})
+				s.Tools().RegisterTool(&types.ToolDefinition{Name: "b", Handler: noop})
+				s.Tools().RemoveTool("a")
+			},
+			want: []string{types.MCPMethodToolsChanged},
+		},
+		{
+			name: "resource added and removed",
+			change: func(s *Server) {
+				s.Resources().RegisterResource(&types.ResourceDefinition{URI: "test://syllabus", Name: "syllabus"})
+				s.Resources().RemoveResource("test://syllabus")
+			},
+			want: []string{types.MCPMethodResourcesChanged},
+		},
+		{
+			name: "removing unknown tool is silent",
+			change: func(s *Server) {
+				s.Tools().RemoveTool("missing")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(ServerConfig{ListChangedDebounce: 20 * time.Millisecond})
+			ready, _ := readySession(t, s)
+			pending := s.Sessions().Create()
+			t.Cleanup(pending.Close)
+
+			tt.change(s)
+
+			got := drainListChanged(ready, 200*time.Millisecond)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ready session notifications = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("ready session notifications = %v, want %v", got, tt.want)
+				}
+			}
+
+			// 未完成初始化的会话不接收list_changed
+			if got := drainListChanged(pending, 10*time.Millisecond); len(got) != 0 {
+				t.Fatalf("uninitialized session notifications = %v, want none", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+)
+
+// ErrElicitationNotSupported 当前连接无会话或客户端未声明elicitation能力
+var ErrElicitationNotSupported = errors.New("client does not support elicitation")
+
+const (
+	// elicitationListSeparators 字符串数组字段在表单中以单个文本框填写，按这些分隔符拆分
+	elicitationListSeparators = "\n;；"
+	// defaultElicitationTimeout 默认等待用户填写缺失参数的时间
+	defaultElicitationTimeout = 5 * time.Minute
+)
+
+// SupportsElicitation 客户端是否声明了elicitation能力
+func SupportsElicitation(ctx context.Context) bool {
+	session := SessionFromContext(ctx)
+	return session != nil && session.ClientCapabilities().Elicitation != nil
+}
+
+// Elicit 通过elicitation/create请求客户端向用户征询信息
+func Elicit(ctx context.Context, request *types.ElicitRequest) (*types.ElicitResult, error) {
+	if !SupportsElicitation(ctx) {
+		return nil, ErrElicitationNotSupported
+	}
+
+	response, err := SessionFromContext(ctx).Request(ctx, types.MCPMethodElicitationCreate, request)
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != nil {
+		return nil, fmt.Errorf("elicitation request rejected by client (%d): %s", response.Error.Code, response.Error.Message)
+	}
+
+	data, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+	result := &types.ElicitResult{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("invalid elicitation result: %w", err)
+	}
+	return result, nil
+}
+
+// elicitationTimeout 等待用户填写缺失参数的时间
+func (s *Server) elicitationTimeout() time.Duration {
+	if s.config.ElicitationTimeout > 0 {
+		return s.config.ElicitationTimeout
+	}
+	return defaultElicitationTimeout
+}
+
+// elicitMissingArguments 工具缺少可征询的必填参数且客户端支持elicitation时，向用户展示表单并将填写结果合并到参数中
+// 同时询问尚未填写的可选征询字段。用户拒绝、取消或超时未填写时返回*ToolError；
+// 客户端不支持或征询失败时原样返回参数，由参数校验报告缺失的字段
+func (s *Server) elicitMissingArguments(ctx context.Context, tool *types.ToolDefinition, arguments interface{}) (interface{}, error) {
+	if len(tool.ElicitFields) == 0 || tool.InputSchema == nil || !SupportsElicitation(ctx) {
+		return arguments, nil
+	}
+
+	schema, err := toJSONValue(tool.InputSchema)
+	if err != nil {
+		return arguments, nil
+	}
+	schemaNode, _ := schema.(map[string]interface{})
+	properties, _ := schemaNode["properties"].(map[string]interface{})
+	required, _ := schemaNode["required"].([]interface{})
+
+	args := map[string]interface{}{}
+	if arguments != nil {
+		value, err := toJSONValue(arguments)
+		if err != nil {
+			return arguments, nil
+		}
+		if args, _ = value.(map[string]interface{}); args == nil {
+			return arguments, nil
+		}
+	}
+
+	formProperties := map[string]interface{}{}
+	var formRequired, missingLabels []string
+	listFields := map[string]bool{}
+	for _, field := range tool.ElicitFields {
+		if value, exists := args[field]; exists && value != nil {
+			continue
+		}
+		property, _ := properties[field].(map[string]interface{})
+		formProperty, isList, ok := s.elicitationProperty(field, property)
+		if !ok {
+			continue
+		}
+
+		formProperties[field] = formProperty
+		listFields[field] = isList
+		if containsJSONValue(required, field) {
+			formRequired = append(formRequired, field)
+			missingLabels = append(missingLabels, formProperty["title"].(string))
+		}
+	}
+
+	// 仅缺少可选字段时不打扰用户
+	if len(formRequired) == 0 {
+		return arguments, nil
+	}
+
+	requestedSchema := map[string]interface{}{
+		"type":       "object",
+		"properties": formProperties,
+		"required":   formRequired,
+	}
+	// 征询发生在工具超时计时之前，单独限制等待时间，避免用户不响应时请求无限期挂起
+	elicitCtx, cancel := context.WithTimeout(ctx, s.elicitationTimeout())
+	defer cancel()
+	result, err := Elicit(elicitCtx, &types.ElicitRequest{
+		Message:         fmt.Sprintf("%s还需要以下信息：%s", toolDisplayTitle(tool), strings.Join(missingLabels, "、")),
+		RequestedSchema: requestedSchema,
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if elicitCtx.Err() != nil {
+			return nil, &ToolError{Message: fmt.Sprintf("等待用户填写%s超时，请在参数中直接提供 %s 后重试",
+				strings.Join(missingLabels, "、"), strings.Join(formRequired, "、"))}
+		}
+		logger.Warn("Elicitation failed, continuing with original arguments",
+			logger.Any("tool", tool.Name),
+			logger.Any("error", err))
+		return arguments, nil
+	}
+
+	switch result.Action {
+	case types.ElicitActionAccept:
+		for field, value := range result.Content {
+			if _, asked := formProperties[field]; !asked {
+				continue
+			}
+			if text, ok := value.(string); ok && listFields[field] {
+				value = splitElicitedList(text)
+			}
+			if text, ok := value.(string); ok && strings.TrimSpace(text) == "" {
+				continue
+			}
+			args[field] = value
+		}
+		return args, nil
+	case types.ElicitActionDecline:
+		return nil, &ToolError{Message: fmt.Sprintf("用户拒绝提供%s，请在参数中直接提供 %s 后重试",
+			strings.Join(missingLabels, "、"), strings.Join(formRequired, "、"))}
+	case types.ElicitActionCancel:
+		return nil, &ToolError{Message: fmt.Sprintf("用户取消了信息填写，调用缺少 %s", strings.Join(formRequired, "、"))}
+	default:
+		logger.Warn("Unknown elicitation action",
+			logger.Any("tool", tool.Name),
+			logger.Any("action", result.Action))
+		return arguments, nil
+	}
+}
+
+// elicitationProperty 将输入Schema中的字段转换为受限表单字段
+// 字符串数组转换为文本框，返回值isList表示提交后需拆分；不支持的类型返回ok=false
+func (s *Server) elicitationProperty(field string, property map[string]interface{}) (form map[string]interface{}, isList bool, ok bool) {
+	if property == nil {
+		return nil, false, false
+	}
+
+	title := field
+	if description, ok := property["description"].(string); ok && description != "" {
+		title = description
+	}
+	form = map[string]interface{}{"title": title}
+
+	schemaType, _ := property["type"].(string)
+	switch schemaType {
+	case "string":
+		form["type"] = "string"
+		copySchemaKeys(form, property, "minLength", "maxLength")
+		if enum, ok := property["enum"].([]interface{}); ok {
+			form["enum"] = enum
+			form["enumNames"] = s.elicitationEnumNames(field, enum)
+		}
+	case "number", "integer":
+		form["type"] = schemaType
+		copySchemaKeys(form, property, "minimum", "maximum")
+	case "boolean":
+		form["type"] = "boolean"
+		copySchemaKeys(form, property, "default")
+	case "array":
+		items, _ := property["items"].(map[string]interface{})
+		if itemType, _ := items["type"].(string); itemType != "string" {
+			return nil, false, false
+		}
+		form["type"] = "string"
+		form["description"] = title + "，多项请分行或用分号分隔"
+		if minItems, ok := property["minItems"].(float64); ok && minItems > 0 {
+			form["minLength"] = 1
+		}
+		isList = true
+	default:
+		return nil, false, false
+	}
+	return form, isList, true
+}
+
+// elicitationEnumNames 枚举选项的显示名称，取参数注册的补全显示名称，未注册时使用选项值
+func (s *Server) elicitationEnumNames(field string, enum []interface{}) []string {
+	names := make([]string, len(enum))
+	for i, option := range enum {
+		value := fmt.Sprint(option)
+		names[i] = value
+		if label := s.optionLabel(field, value); label != "" {
+			names[i] = label
+		}
+	}
+	return names
+}
+
+// splitElicitedList 拆分文本框中填写的多项内容
+func splitElicitedList(text string) []interface{} {
+	items := []interface{}{}
+	for _, item := range strings.FieldsFunc(text, func(r rune) bool {
+		return strings.ContainsRune(elicitationListSeparators, r)
+	}) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// copySchemaKeys 复制Schema中存在的关键字
+func copySchemaKeys(target, source map[string]interface{}, keys ...string) {
+	for _, key := range keys {
+		if value, ok := source[key]; ok {
+			target[key] = value
+		}
+	}
+}
+
+// toolDisplayTitle 工具的显示名称，优先使用注解中的标题
+func toolDisplayTitle(tool *types.ToolDefinition) string {
+	if tool.Annotations != nil && tool.Annotations.Title != "" {
+		return tool.Annotations.Title
+	}
+	return tool.Name
+}
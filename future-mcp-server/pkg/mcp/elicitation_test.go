@@ -0,0 +1,281 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+// elicitationClient 创建声明了elicitation能力的会话，以reply答复服务端的征询请求
+// reply为nil时以JSON-RPC错误拒绝；返回的切片记录客户端收到的征询请求
+func elicitationClient(t *testing.T, reply *types.ElicitResult) (*Server, context.Context, *[]*types.ElicitRequest) {
+	t.Helper()
+
+	s := NewServer(ServerConfig{})
+	session := s.Sessions().Create()
+	t.Cleanup(session.Close)
+	capabilities := types.ClientCapabilities{Elicitation: &types.ElicitationCapability{}}
+	if err := session.Initialize(types.MCPProtocolVersion, types.ImplementationInfo{Name: "test"}, capabilities); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+
+	var received []*types.ElicitRequest
+	ctx := ContextWithRequestNotifier(ContextWithSession(context.Background(), session), func(message interface{}) {
+		request, ok := message.(*types.MCPRequest)
+		if !ok || request.Method != types.MCPMethodElicitationCreate {
+			return
+		}
+		received = append(received, request.Params.(*types.ElicitRequest))
+
+		response := &types.MCPResponse{MCPMessage: types.MCPMessage{JSONRPC: "2.0", ID: request.ID}}
+		if reply == nil {
+			response.Error = &types.MCPError{Code: types.MCPMethodNotFound, Message: "not supported"}
+		} else {
+			response.Result = reply
+		}
+		session.DeliverResponse(response)
+	})
+	return s, ctx, &received
+}
+
+// elicitTool 测试用的可征询工具
+var elicitTool = &types.ToolDefinition{
+	Name:        "plan",
+	Annotations: &types.ToolAnnotations{Title: "生成教案"},
+	InputSchema: map[string]interface{}{
+		"type":     "object",
+		"required": []string{"topic", "grade", "objectives"},
+		"properties": map[string]interface{}{
+			"topic":      map[string]interface{}{"type": "string"},
+			"grade":      map[string]interface{}{"type": "string", "description": "年级", "enum": []string{"grade_7", "grade_8"}},
+			"objectives": map[string]interface{}{"type": "array", "description": "教学目标", "minItems": 1, "items": map[string]interface{}{"type": "string"}},
+			"level":      map[string]interface{}{"type": "string", "description": "学生水平"},
+		},
+	},
+	ElicitFields: []string{"grade", "objectives", "level"},
+}
+
+func TestElicitMissingArguments(t *testing.T) {
+	tests := []struct {
+		name         string
+		arguments    map[string]interface{}
+		reply        *types.ElicitResult
+		want         interface{}
+		wantToolErr  string
+		wantRequests int
+		wantFields   []string
+	}{
+		{
+			name:      "accepted values merged",
+			arguments: map[string]interface{}{"topic": "方程"},
+			reply: &types.ElicitResult{Action: types.ElicitActionAccept, Content: map[string]interface{}{
+				"grade": "grade_8", "objectives": "理解概念；会解方程\n", "level": " ", "topic": "覆盖",
+			}},
+			want: map[string]interface{}{
+				"topic": "方程", "grade": "grade_8", "objectives": []interface{}{"理解概念", "会解方程"},
+			},
+			wantRequests: 1,
+			wantFields:   []string{"grade", "level", "objectives"},
+		},
+		{
+			name:         "only optional fields missing",
+			arguments:    map[string]interface{}{"topic": "方程", "grade": "grade_7", "objectives": []string{"a"}},
+			want:         map[string]interface{}{"topic": "方程", "grade": "grade_7", "objectives": []string{"a"}},
+			wantRequests: 0,
+		},
+		{
+			name:         "declined",
+			arguments:    map[string]interface{}{"topic": "方程", "grade": "grade_7"},
+			reply:        &types.ElicitResult{Action: types.ElicitActionDecline},
+			wantToolErr:  "用户拒绝提供教学目标",
+			wantRequests: 1,
+			wantFields:   []string{"level", "objectives"},
+		},
+		{
+			name:         "cancelled",
+			arguments:    map[string]interface{}{"topic": "方程"},
+			reply:        &types.ElicitResult{Action: types.ElicitActionCancel},
+			wantToolErr:  "用户取消了信息填写",
+			wantRequests: 1,
+			wantFields:   []string{"grade", "level", "objectives"},
+		},
+		{
+			name:         "client error keeps original arguments",
+			arguments:    map[string]interface{}{"topic": "方程"},
+			want:         map[string]interface{}{"topic": "方程"},
+			wantRequests: 1,
+			wantFields:   []string{"grade", "level", "objectives"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ctx, received := elicitationClient(t, tt.reply)
+
+			got, err := s.elicitMissingArguments(ctx, elicitTool, tt.arguments)
+			if len(*received) != tt.wantRequests {
+				t.Fatalf("elicitation requests = %d, want %d", len(*received), tt.wantRequests)
+			}
+			if tt.wantRequests > 0 {
+				request := (*received)[0]
+				if !strings.HasPrefix(request.Message, "生成教案还需要以下信息") {
+					t.Fatalf("message = %q", request.Message)
+				}
+				properties := request.RequestedSchema.(map[string]interface{})["properties"].(map[string]interface{})
+				var fields []string
+				for field := range properties {
+					fields = append(fields, field)
+				}
+				sort.Strings(fields)
+				if !reflect.DeepEqual(fields, tt.wantFields) {
+					t.Fatalf("form fields = %v, want %v", fields, tt.wantFields)
+				}
+			}
+
+			if tt.wantToolErr != "" {
+				var toolErr *ToolError
+				if !errors.As(err, &toolErr) || !strings.Contains(toolErr.Message, tt.wantToolErr) {
+					t.Fatalf("err = %v, want tool error containing %q", err, tt.wantToolErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("arguments = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// silentElicitationClient 创建声明了elicitation能力但从不答复征询请求的会话
+func silentElicitationClient(t *testing.T, s *Server) context.Context {
+	t.Helper()
+
+	session := s.Sessions().Create()
+	t.Cleanup(session.Close)
+	capabilities := types.ClientCapabilities{Elicitation: &types.ElicitationCapability{}}
+	if err := session.Initialize(types.MCPProtocolVersion, types.ImplementationInfo{Name: "test"}, capabilities); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	return ContextWithRequestNotifier(ContextWithSession(context.Background(), session), func(message interface{}) {})
+}
+
+func TestElicitationTimeout(t *testing.T) {
+	s := NewServer(ServerConfig{ElicitationTimeout: 20 * time.Millisecond})
+	ctx := silentElicitationClient(t, s)
+
+	// 用户未在时限内填写，以工具错误结果结束，提示直接提供参数
+	_, err := s.elicitMissingArguments(ctx, elicitTool, map[string]interface{}{"topic": "方程"})
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) || !strings.Contains(toolErr.Message, "超时") || !strings.Contains(toolErr.Message, "grade、objectives") {
+		t.Fatalf("err = %v, want timeout tool error", err)
+	}
+
+	// 请求本身被取消时返回取消原因而不是工具错误
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := s.elicitMissingArguments(cancelled, elicitTool, map[string]interface{}{"topic": "方程"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestElicitationTimeoutToolCall(t *testing.T) {
+	s := NewServer(ServerConfig{ElicitationTimeout: 20 * time.Millisecond})
+	tool := *elicitTool
+	tool.Handler = func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+		t.Error("handler should not run when elicitation times out")
+		return nil, nil
+	}
+	s.Tools().RegisterTool(&tool)
+
+	result := toolResult(t, callTool(t, silentElicitationClient(t, s), s, "plan", map[string]interface{}{"topic": "方程"}))
+	if !result.IsError || !strings.Contains(result.Content[0].Text, "超时") {
+		t.Fatalf("result = %+v, want timeout tool error", result)
+	}
+}
+
+func TestElicitationNotSupported(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	_, ctx := readySession(t, s)
+
+	arguments := map[string]interface{}{"topic": "方程"}
+	got, err := s.elicitMissingArguments(ctx, elicitTool, arguments)
+	if err != nil || !reflect.DeepEqual(got, arguments) {
+		t.Fatalf("elicitMissingArguments = %v, %v, want original arguments", got, err)
+	}
+	if _, err := Elicit(ctx, &types.ElicitRequest{}); !errors.Is(err, ErrElicitationNotSupported) {
+		t.Fatalf("err = %v, want ErrElicitationNotSupported", err)
+	}
+}
+
+func TestElicitationProperty(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	difficulties := map[string]string{"easy": "简单"}
+	s.RegisterCompleter("difficulty", &ArgumentCompleter{Label: func(value string) string { return difficulties[value] }})
+
+	tests := []struct {
+		name     string
+		field    string
+		property map[string]interface{}
+		want     map[string]interface{}
+		wantList bool
+		wantOK   bool
+	}{
+		{
+			name:     "enum with registered labels",
+			field:    "difficulty",
+			property: map[string]interface{}{"type": "string", "description": "难度", "enum": []interface{}{"easy", "custom"}},
+			want:     map[string]interface{}{"title": "难度", "type": "string", "enum": []interface{}{"easy", "custom"}, "enumNames": []string{"简单", "custom"}},
+			wantOK:   true,
+		},
+		{
+			name:     "enum without labels",
+			field:    "grade",
+			property: map[string]interface{}{"type": "string", "enum": []interface{}{"grade_7"}},
+			want:     map[string]interface{}{"title": "grade", "type": "string", "enum": []interface{}{"grade_7"}, "enumNames": []string{"grade_7"}},
+			wantOK:   true,
+		},
+		{
+			name:     "integer keeps bounds",
+			field:    "count",
+			property: map[string]interface{}{"type": "integer", "minimum": 1.0, "maximum": 20.0, "default": 5.0},
+			want:     map[string]interface{}{"title": "count", "type": "integer", "minimum": 1.0, "maximum": 20.0},
+			wantOK:   true,
+		},
+		{
+			name:     "string array as text field",
+			field:    "objectives",
+			property: map[string]interface{}{"type": "array", "minItems": 1.0, "items": map[string]interface{}{"type": "string"}},
+			want:     map[string]interface{}{"title": "objectives", "type": "string", "description": "objectives，多项请分行或用分号分隔", "minLength": 1},
+			wantList: true,
+			wantOK:   true,
+		},
+		{
+			name:     "object array unsupported",
+			field:    "sections",
+			property: map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "object"}},
+		},
+		{
+			name:  "unknown field",
+			field: "missing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, isList, ok := s.elicitationProperty(tt.field, tt.property)
+			if ok != tt.wantOK || isList != tt.wantList {
+				t.Fatalf("ok = %v, isList = %v, want %v, %v", ok, isList, tt.wantOK, tt.wantList)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("property = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// ==================== 方法级拦截器 ====================
+
+// Handler JSON-RPC方法处理器，通知返回nil响应
+type Handler func(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error)
+
+// Middleware 包装方法处理器的拦截器（around），可在分发前后执行鉴权、审计、限流等逻辑，或直接返回响应以短路请求
+// 拦截器返回*RPCError时以对应的JSON-RPC错误响应客户端，其他错误按内部错误处理
+type Middleware func(next Handler) Handler
+
+// Use 追加方法级拦截器，先添加的位于外层，对之后的请求生效
+func (s *Server) Use(middleware ...Middleware) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.middleware = append(s.middleware, middleware...)
+}
+
+// chain 以已注册的方法级拦截器包装处理器
+func (s *Server) chain(handler Handler) Handler {
+	s.mu.RLock()
+	middleware := s.middleware
+	s.mu.RUnlock()
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// Before 创建在分发前执行的拦截器，fn可返回派生的上下文（如写入鉴权信息），返回错误时不再分发
+func Before(fn func(ctx context.Context, request *types.MCPRequest) (context.Context, error)) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+			ctx, err := fn(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// After 创建在分发后执行的拦截器，fn可观察或改写响应与错误，通知的响应为nil
+func After(fn func(ctx context.Context, request *types.MCPRequest, response *types.MCPResponse, err error) (*types.MCPResponse, error)) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+			response, err := next(ctx, request)
+			return fn(ctx, request, response, err)
+		}
+	}
+}
+
+// ForMethods 仅对指定方法生效的拦截器，其他方法直接分发
+func ForMethods(middleware Middleware, methods ...string) Middleware {
+	return func(next Handler) Handler {
+		wrapped := middleware(next)
+		return func(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+			if containsString(methods, request.Method) {
+				return wrapped(ctx, request)
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// LogRequests 记录每个请求的方法、参数、耗时与错误，NewServer默认安装在最外层
+func LogRequests() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+			mcpLogger := logger.NewMCPLogger(getRequestID(request.ID))
+			mcpLogger.LogMCPRequest(request.Method, request.Params)
+
+			startTime := time.Now()
+			response, err := next(ctx, request)
+
+			var rpcErr *RPCError
+			switch {
+			case errors.As(err, &rpcErr):
+				mcpLogger.LogMCPError(err, rpcErr.Code)
+			case err != nil:
+				mcpLogger.LogMCPError(err, types.MCPInternalError)
+			case response != nil && response.Error != nil:
+				mcpLogger.LogMCPError(response.Error, response.Error.Code)
+			}
+			mcpLogger.LogMCPResponse(nil, time.Since(startTime))
+			return response, err
+		}
+	}
+}
+
+// RecoverPanics 将处理器与拦截器中的panic转换为内部错误响应，错误数据中的关联ID对应服务端日志中的堆栈，
+// NewServer默认安装在请求日志拦截器之内
+func RecoverPanics() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request *types.MCPRequest) (response *types.MCPResponse, err error) {
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				correlationID := uuid.New().String()
+				logger.Error("MCP handler panicked",
+					logger.Any("method", request.Method),
+					logger.Any("request_id", request.ID),
+					logger.Any("correlation_id", correlationID),
+					logger.Any("panic", fmt.Sprint(recovered)),
+					logger.Any("stack", string(debug.Stack())))
+				// 通知没有响应
+				if request.ID == nil {
+					response, err = nil, nil
+					return
+				}
+				response, err = createErrorResponseWithData(request.ID, types.MCPInternalError, "Internal error",
+					map[string]interface{}{"correlationId": correlationID})
+			}()
+			return next(ctx, request)
+		}
+	}
+}
+
+// ==================== 工具级拦截器 ====================
+
+// ToolCall 一次工具调用，参数已通过InputSchema校验并注入默认值
+type ToolCall struct {
+	Tool      *types.ToolDefinition
+	Arguments interface{}
+	Context   *types.ToolContext
+}
+
+// ToolInvoker 执行工具调用
+type ToolInvoker func(call *ToolCall) (*types.ToolsCallResponse, error)
+
+// ToolMiddleware 包装工具调用的拦截器（around），用于配额、缓存、指标、审计等按工具组合的逻辑
+// 返回*ToolError时以isError结果返回，返回*RPCError时以对应的JSON-RPC错误返回
+type ToolMiddleware func(next ToolInvoker) ToolInvoker
+
+// ToolSelector 选择拦截器生效的工具
+type ToolSelector func(tool *types.ToolDefinition) bool
+
+// UseTool 追加工具级拦截器，先添加的位于外层，对之后的调用生效
+func (s *Server) UseTool(middleware ...ToolMiddleware) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolMiddleware = append(s.toolMiddleware, middleware...)
+}
+
+// chainTool 以已注册的工具级拦截器包装工具执行
+func (s *Server) chainTool(invoker ToolInvoker) ToolInvoker {
+	s.mu.RLock()
+	middleware := s.toolMiddleware
+	s.mu.RUnlock()
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		invoker = middleware[i](invoker)
+	}
+	return invoker
+}
+
+// BeforeTool 创建在工具执行前运行的拦截器，返回错误时不再执行工具
+func BeforeTool(fn func(call *ToolCall) error) ToolMiddleware {
+	return func(next ToolInvoker) ToolInvoker {
+		return func(call *ToolCall) (*types.ToolsCallResponse, error) {
+			if err := fn(call); err != nil {
+				return nil, err
+			}
+			return next(call)
+		}
+	}
+}
+
+// AfterTool 创建在工具执行后运行的拦截器，fn可观察或改写结果与错误
+func AfterTool(fn func(call *ToolCall, result *types.ToolsCallResponse, err error) (*types.ToolsCallResponse, error)) ToolMiddleware {
+	return func(next ToolInvoker) ToolInvoker {
+		return func(call *ToolCall) (*types.ToolsCallResponse, error) {
+			result, err := next(call)
+			return fn(call, result, err)
+		}
+	}
+}
+
+// ForTools 仅对选中的工具生效的拦截器，其他工具直接执行
+func ForTools(selector ToolSelector, middleware ToolMiddleware) ToolMiddleware {
+	return func(next ToolInvoker) ToolInvoker {
+		wrapped := middleware(next)
+		return func(call *ToolCall) (*types.ToolsCallResponse, error) {
+			if selector(call.Tool) {
+				return wrapped(call)
+			}
+			return next(call)
+		}
+	}
+}
+
+// ToolNames 按工具名称选择
+func ToolNames(names ...string) ToolSelector {
+	return func(tool *types.ToolDefinition) bool {
+		return containsString(names, tool.Name)
+	}
+}
+
+// ToolCategories 按工具分类选择
+func ToolCategories(categories ...types.ToolCategory) ToolSelector {
+	return func(tool *types.ToolDefinition) bool {
+		for _, category := range categories {
+			if tool.Category == category {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// LogToolCalls 记录工具调用的参数、耗时与结果，可配合ForTools用于审计指定工具
+func LogToolCalls() ToolMiddleware {
+	return func(next ToolInvoker) ToolInvoker {
+		return func(call *ToolCall) (*types.ToolsCallResponse, error) {
+			toolLogger := logger.NewToolLogger(call.Tool.Name, call.Context.RequestID)
+			startTime := time.Now()
+			toolLogger.LogToolExecution(call.Arguments, startTime)
+
+			result, err := next(call)
+
+			switch {
+			case err != nil:
+				toolLogger.LogToolError(err, time.Since(startTime))
+			case result != nil && result.IsError:
+				toolLogger.LogToolError(errors.New(toolResultText(result)), time.Since(startTime))
+			default:
+				toolLogger.LogToolResult(toolResultSummary(result), time.Since(startTime))
+			}
+			return result, err
+		}
+	}
+}
+
+// toolResultText 工具结果中的第一段文本
+func toolResultText(result *types.ToolsCallResponse) string {
+	for _, content := range result.Content {
+		if content.Type == types.ContentTypeText {
+			return content.Text
+		}
+	}
+	return ""
+}
+
+// toolResultSummary 审计日志中的结果摘要，不记录结果正文
+func toolResultSummary(result *types.ToolsCallResponse) map[string]interface{} {
+	if result == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"content_blocks": len(result.Content),
+		"structured":     result.StructuredContent != nil,
+	}
+}
+
+// rpcErrorResponse 拦截器或处理器返回*RPCError时转换为对应的JSON-RPC错误响应
+func rpcErrorResponse(id interface{}, err error) (*types.MCPResponse, bool) {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		return nil, false
+	}
+	response, _ := createErrorResponseWithData(id, rpcErr.Code, rpcErr.Message, rpcErr.Data)
+	return response, true
+}
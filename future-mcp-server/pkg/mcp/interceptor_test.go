@@ -0,0 +1,161 @@
+package mcp
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+// recorder 按执行顺序记录拦截器事件
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) middleware(name string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+			r.events = append(r.events, name+" before")
+			response, err := next(ctx, request)
+			r.events = append(r.events, name+" after")
+			return response, err
+		}
+	}
+}
+
+func (r *recorder) toolMiddleware(name string) ToolMiddleware {
+	return func(next ToolInvoker) ToolInvoker {
+		return func(call *ToolCall) (*types.ToolsCallResponse, error) {
+			r.events = append(r.events, name+" before")
+			result, err := next(call)
+			r.events = append(r.events, name+" after")
+			return result, err
+		}
+	}
+}
+
+// newRecordingServer 注册一个记录执行事件的方法与工具
+func newRecordingServer(r *recorder) *Server {
+	s := NewServer(ServerConfig{})
+	s.Handle("test/echo", func(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+		r.events = append(r.events, "handler")
+		return createSuccessResponse(request.ID, "ok")
+	})
+	for _, name := range []string{"search", "generate"} {
+		s.Tools().RegisterTool(&types.ToolDefinition{
+			Name:        name,
+			InputSchema: map[string]interface{}{"type": "object"},
+			Handler: func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+				r.events = append(r.events, "tool")
+				return &types.ToolsCallResponse{Content: []types.Content{TextContent("ok")}}, nil
+			},
+		})
+	}
+	return s
+}
+
+func request(method string) *types.MCPRequest {
+	return &types.MCPRequest{MCPMessage: types.MCPMessage{JSONRPC: "2.0", ID: 1}, Method: method}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	r := &recorder{}
+	s := newRecordingServer(r)
+	s.Use(r.middleware("outer"), r.middleware("inner"))
+	s.Use(ForMethods(r.middleware("echo only"), "test/echo"))
+
+	if _, err := s.HandleRequest(context.Background(), request("test/echo")); err != nil {
+		t.Fatalf("HandleRequest: %v", err)
+	}
+	want := []string{"outer before", "inner before", "echo only before", "handler", "echo only after", "inner after", "outer after"}
+	if !reflect.DeepEqual(r.events, want) {
+		t.Fatalf("events = %v, want %v", r.events, want)
+	}
+
+	// ForMethods不作用于其他方法
+	r.events = nil
+	if _, err := s.HandleRequest(context.Background(), request(types.MCPMethodPing)); err != nil {
+		t.Fatalf("HandleRequest: %v", err)
+	}
+	want = []string{"outer before", "inner before", "inner after", "outer after"}
+	if !reflect.DeepEqual(r.events, want) {
+		t.Fatalf("events = %v, want %v", r.events, want)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	r := &recorder{}
+	s := newRecordingServer(r)
+	s.Use(r.middleware("outer"))
+	s.Use(Before(func(ctx context.Context, request *types.MCPRequest) (context.Context, error) {
+		r.events = append(r.events, "reject")
+		return nil, &RPCError{Code: types.MCPPermissionDenied, Message: "denied"}
+	}))
+	s.Use(r.middleware("inner"))
+
+	response, err := s.HandleRequest(context.Background(), request("test/echo"))
+	if err != nil {
+		t.Fatalf("HandleRequest: %v", err)
+	}
+	if response.Error == nil || response.Error.Code != types.MCPPermissionDenied || response.Error.Message != "denied" {
+		t.Fatalf("response error = %+v, want permission denied", response.Error)
+	}
+	want := []string{"outer before", "reject", "outer after"}
+	if !reflect.DeepEqual(r.events, want) {
+		t.Fatalf("events = %v, want %v", r.events, want)
+	}
+}
+
+func TestToolMiddlewareOrder(t *testing.T) {
+	r := &recorder{}
+	s := newRecordingServer(r)
+	s.Use(r.middleware("method"))
+	s.UseTool(r.toolMiddleware("outer"), r.toolMiddleware("inner"))
+	s.UseTool(ForTools(ToolNames("generate"), r.toolMiddleware("generate only")))
+
+	toolResult(t, callTool(t, context.Background(), s, "generate", nil))
+	want := []string{"method before", "outer before", "inner before", "generate only before", "tool",
+		"generate only after", "inner after", "outer after", "method after"}
+	if !reflect.DeepEqual(r.events, want) {
+		t.Fatalf("events = %v, want %v", r.events, want)
+	}
+
+	r.events = nil
+	toolResult(t, callTool(t, context.Background(), s, "search", nil))
+	want = []string{"method before", "outer before", "inner before", "tool", "inner after", "outer after", "method after"}
+	if !reflect.DeepEqual(r.events, want) {
+		t.Fatalf("events = %v, want %v", r.events, want)
+	}
+}
+
+func TestToolMiddlewareErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int // 0表示期望isError结果
+	}{
+		{"tool error becomes isError result", &ToolError{Message: "配额已用完"}, 0},
+		{"rpc error becomes JSON-RPC error", &RPCError{Code: types.MCPPermissionDenied, Message: "denied"}, types.MCPPermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			s := newRecordingServer(r)
+			s.UseTool(BeforeTool(func(call *ToolCall) error { return tt.err }))
+
+			response := callTool(t, context.Background(), s, "search", nil)
+			if tt.wantCode != 0 {
+				if response.Error == nil || response.Error.Code != tt.wantCode {
+					t.Fatalf("response error = %+v, want code %d", response.Error, tt.wantCode)
+				}
+			} else if result := toolResult(t, response); !result.IsError || result.Content[0].Text != "配额已用完" {
+				t.Fatalf("result = %+v", result)
+			}
+			if len(r.events) != 0 {
+				t.Fatalf("tool should not run, events = %v", r.events)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// handleLoggingSetLevel 处理设置日志级别请求
+func (s *Server) handleLoggingSetLevel(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	setLevelReq := &types.LoggingSetLevelRequest{}
+	if err := parseParams(request.Params, setLevelReq); err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	if !setLevelReq.Level.IsValid() {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, "Invalid logging level: "+string(setLevelReq.Level))
+	}
+
+	session := SessionFromContext(ctx)
+	if session == nil {
+		return createErrorResponse(request.ID, types.MCPInvalidRequest, "logging/setLevel requires a session")
+	}
+	session.SetLogLevel(setLevelReq.Level)
+
+	return createSuccessResponse(request.ID, map[string]interface{}{})
+}
+
+// newClientLogger 创建转发给客户端的日志回调
+// 日志同时写入服务端日志；达到会话日志级别的记录以notifications/message发送给客户端
+func newClientLogger(ctx context.Context, loggerName string) types.LogFunc {
+	return func(level types.LoggingLevel, data interface{}) {
+		logToServer(level, loggerName, data)
+		logToClient(ctx, level, loggerName, data)
+	}
+}
+
+// logToClient 按会话日志级别向客户端发送日志消息，无会话时忽略
+func logToClient(ctx context.Context, level types.LoggingLevel, loggerName string, data interface{}) {
+	session := SessionFromContext(ctx)
+	if session == nil || !level.AtLeast(session.LogLevel()) {
+		return
+	}
+
+	notifyRequest(ctx, &types.MCPNotification{
+		MCPMessage: types.MCPMessage{
+			JSONRPC: "2.0",
+		},
+		Method: types.MCPMethodLoggingMessage,
+		Params: types.LoggingMessageParams{
+			Level:  level,
+			Logger: loggerName,
+			Data:   data,
+		},
+	})
+}
+
+// logToServer 将MCP日志级别映射到服务端日志
+func logToServer(level types.LoggingLevel, loggerName string, data interface{}) {
+	fields := []zap.Field{
+		logger.Any("logger", loggerName),
+		logger.Any("level", level),
+		logger.Any("data", data),
+	}
+
+	switch {
+	case level.AtLeast(types.LoggingLevelError):
+		logger.Error("MCP client log", fields...)
+	case level.AtLeast(types.LoggingLevelWarning):
+		logger.Warn("MCP client log", fields...)
+	case level.AtLeast(types.LoggingLevelInfo):
+		logger.Info("MCP client log", fields...)
+	default:
+		logger.Debug("MCP client log", fields...)
+	}
+}
@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+func TestLoggingSetLevel(t *testing.T) {
+	s := NewServer(ServerConfig{})
+
+	tests := []struct {
+		name      string
+		withSess  bool
+		level     string
+		wantCode  int
+		wantLevel types.LoggingLevel
+	}{
+		{"valid level", true, "debug", 0, types.LoggingLevelDebug},
+		{"invalid level", true, "verbose", types.MCPInvalidParams, defaultSessionLogLevel},
+		{"stateless request", false, "info", types.MCPInvalidRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			var session *Session
+			if tt.withSess {
+				session, ctx = readySession(t, s)
+			}
+
+			response := handle(t, s, ctx, 1, types.MCPMethodLoggingSetLevel, map[string]string{"level": tt.level})
+			if code := errorCode(response); code != tt.wantCode {
+				t.Fatalf("error code = %d, want %d", code, tt.wantCode)
+			}
+			if session != nil && session.LogLevel() != tt.wantLevel {
+				t.Fatalf("session level = %s, want %s", session.LogLevel(), tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestToolLogFiltering(t *testing.T) {
+	emitted := []types.LoggingLevel{
+		types.LoggingLevelDebug,
+		types.LoggingLevelInfo,
+		types.LoggingLevelWarning,
+		types.LoggingLevelError,
+		types.LoggingLevelEmergency,
+	}
+
+	tests := []struct {
+		name     string
+		setLevel types.LoggingLevel
+		want     []types.LoggingLevel
+	}{
+		{"default warning", "", []types.LoggingLevel{types.LoggingLevelWarning, types.LoggingLevelError, types.LoggingLevelEmergency}},
+		{"debug", types.LoggingLevelDebug, emitted},
+		{"notice", types.LoggingLevelNotice, []types.LoggingLevel{types.LoggingLevelWarning, types.LoggingLevelError, types.LoggingLevelEmergency}},
+		{"emergency", types.LoggingLevelEmergency, []types.LoggingLevel{types.LoggingLevelEmergency}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(ServerConfig{})
+			s.toolRegistry.RegisterTool(&types.ToolDefinition{
+				Name: "chatty",
+				Handler: func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+					for _, level := range emitted {
+						ctx.Log(level, string(level))
+					}
+					return &types.ToolsCallResponse{Content: []types.Content{{Type: "text", Text: "done"}}}, nil
+				},
+			})
+
+			session, ctx := readySession(t, s)
+			if tt.setLevel != "" {
+				session.SetLogLevel(tt.setLevel)
+			}
+
+			var (
+				mu  sync.Mutex
+				got []types.LoggingLevel
+			)
+			ctx = ContextWithRequestNotifier(ctx, func(message interface{}) {
+				notification := message.(*types.MCPNotification)
+				if notification.Method != types.MCPMethodLoggingMessage {
+					return
+				}
+				params := notification.Params.(types.LoggingMessageParams)
+				if params.Logger != "tool/chatty" {
+					t.Errorf("logger = %q, want tool/chatty", params.Logger)
+				}
+				mu.Lock()
+				got = append(got, params.Level)
+				mu.Unlock()
+			})
+
+			if response := handle(t, s, ctx, 1, types.MCPMethodToolsCall, map[string]interface{}{"name": "chatty"}); response.Error != nil {
+				t.Fatalf("tools/call error: %+v", response.Error)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("forwarded levels = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoggingLevelAtLeast(t *testing.T) {
+	tests := []struct {
+		level, min types.LoggingLevel
+		want       bool
+	}{
+		{types.LoggingLevelError, types.LoggingLevelWarning, true},
+		{types.LoggingLevelWarning, types.LoggingLevelWarning, true},
+		{types.LoggingLevelNotice, types.LoggingLevelWarning, false},
+		{types.LoggingLevelDebug, types.LoggingLevelDebug, true},
+	}
+	for _, tt := range tests {
+		if got := tt.level.AtLeast(tt.min); got != tt.want {
+			t.Errorf("%s.AtLeast(%s) = %v, want %v", tt.level, tt.min, got, tt.want)
+		}
+	}
+}
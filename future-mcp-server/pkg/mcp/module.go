@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"fmt"
+
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+)
+
+// Module 一组可注册到Server的工具、资源、提示模板与参数补全
+// 各业务方实现自己的模块，由同一个Server提供MCP协议与传输能力
+type Module interface {
+	// Name 模块名称，同一Server内唯一
+	Name() string
+	// Register 向Server注册模块提供的能力
+	Register(server *Server) error
+}
+
+// RegisterModule 注册模块，同名模块不能重复注册
+func (s *Server) RegisterModule(module Module) error {
+	name := module.Name()
+
+	s.mu.Lock()
+	if containsString(s.modules, name) {
+		s.mu.Unlock()
+		return fmt.Errorf("module %s already registered", name)
+	}
+	s.modules = append(s.modules, name)
+	s.mu.Unlock()
+
+	// 注册失败时释放模块名称，已注册的部分能力需由模块自行清理
+	if err := module.Register(s); err != nil {
+		s.mu.Lock()
+		for i, registered := range s.modules {
+			if registered == name {
+				s.modules = append(s.modules[:i], s.modules[i+1:]...)
+				break
+			}
+		}
+		s.mu.Unlock()
+		return fmt.Errorf("module %s: %w", name, err)
+	}
+
+	logger.Info("MCP module registered", logger.Any("module", name))
+	return nil
+}
+
+// Modules 按注册顺序列出已注册的模块名称
+func (s *Server) Modules() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string(nil), s.modules...)
+}
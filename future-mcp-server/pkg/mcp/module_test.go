@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+// testModule 注册一个同名工具的测试模块，err非nil时注册失败
+type testModule struct {
+	name string
+	err  error
+}
+
+func (m testModule) Name() string { return m.name }
+
+func (m testModule) Register(server *Server) error {
+	if m.err != nil {
+		return m.err
+	}
+	server.Tools().RegisterTool(&types.ToolDefinition{
+		Name:        m.name + "_tool",
+		InputSchema: map[string]interface{}{"type": "object"},
+		Handler: func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+			return &types.ToolsCallResponse{Content: []types.Content{TextContent(m.name)}}, nil
+		},
+	})
+	return nil
+}
+
+func TestRegisterModule(t *testing.T) {
+	s := NewServer(ServerConfig{})
+
+	for _, name := range []string{"education", "homework"} {
+		if err := s.RegisterModule(testModule{name: name}); err != nil {
+			t.Fatalf("RegisterModule(%s): %v", name, err)
+		}
+	}
+	if modules := s.Modules(); !reflect.DeepEqual(modules, []string{"education", "homework"}) {
+		t.Fatalf("Modules() = %v", modules)
+	}
+
+	// 模块注册的工具可被调用
+	response := handle(t, s, context.Background(), 1, types.MCPMethodToolsCall, map[string]interface{}{"name": "homework_tool"})
+	if response.Error != nil {
+		t.Fatalf("tools/call error: %+v", response.Error)
+	}
+	if result := response.Result.(*types.ToolsCallResponse); result.Content[0].Text != "homework" {
+		t.Fatalf("result = %+v", result)
+	}
+
+	// 同名模块不能重复注册
+	err := s.RegisterModule(testModule{name: "education"})
+	if err == nil || !strings.Contains(err.Error(), "already registered") {
+		t.Fatalf("duplicate RegisterModule err = %v", err)
+	}
+
+	// 注册失败时返回带模块名的错误并释放名称
+	failure := errors.New("repository unavailable")
+	err = s.RegisterModule(testModule{name: "reports", err: failure})
+	if !errors.Is(err, failure) || !strings.HasPrefix(err.Error(), "module reports:") {
+		t.Fatalf("failing RegisterModule err = %v", err)
+	}
+	if err := s.RegisterModule(testModule{name: "reports"}); err != nil {
+		t.Fatalf("RegisterModule after failure: %v", err)
+	}
+	if modules := s.Modules(); !reflect.DeepEqual(modules, []string{"education", "homework", "reports"}) {
+		t.Fatalf("Modules() = %v", modules)
+	}
+}
@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// gb2312InitialBoundaries GB2312一级汉字按拼音排序，各声母首字的区位编码
+// 一级汉字（0xB0A1-0xD7F9）覆盖常用字，二级汉字按部首排序，无法据此推断拼音
+var gb2312InitialBoundaries = []struct {
+	code    int
+	initial byte
+}{
+	{0xB0A1, 'a'}, {0xB0C5, 'b'}, {0xB2C1, 'c'}, {0xB4EE, 'd'}, {0xB6EA, 'e'},
+	{0xB7A2, 'f'}, {0xB8C1, 'g'}, {0xB9FE, 'h'}, {0xBBF7, 'j'}, {0xBFA6, 'k'},
+	{0xC0AC, 'l'}, {0xC2E8, 'm'}, {0xC4C3, 'n'}, {0xC5B6, 'o'}, {0xC5BE, 'p'},
+	{0xC6DA, 'q'}, {0xC8BB, 'r'}, {0xC8F6, 's'}, {0xCBFA, 't'}, {0xCDDA, 'w'},
+	{0xCEF4, 'x'}, {0xD1B9, 'y'}, {0xD4D1, 'z'},
+}
+
+// gb2312Level1End GB2312一级汉字结束编码
+const gb2312Level1End = 0xD7F9
+
+// pinyinInitials 提取文本的拼音首字母，如"一元二次方程"得到"yyecfc"
+// 字母与数字原样保留（转为小写），无法识别的汉字与其他字符忽略
+func pinyinInitials(text string) string {
+	encoder := simplifiedchinese.GBK.NewEncoder()
+
+	var initials strings.Builder
+	for _, r := range text {
+		switch {
+		case r < unicode.MaxASCII:
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				initials.WriteRune(unicode.ToLower(r))
+			}
+		case unicode.Is(unicode.Han, r):
+			if initial, ok := hanInitial(encoder.String, r); ok {
+				initials.WriteByte(initial)
+			}
+		}
+	}
+	return initials.String()
+}
+
+// hanInitial 查找单个汉字的拼音首字母
+func hanInitial(encode func(string) (string, error), r rune) (byte, bool) {
+	encoded, err := encode(string(r))
+	if err != nil || len(encoded) != 2 {
+		return 0, false
+	}
+
+	code := int(encoded[0])<<8 | int(encoded[1])
+	if code < gb2312InitialBoundaries[0].code || code > gb2312Level1End {
+		return 0, false
+	}
+
+	initial := gb2312InitialBoundaries[0].initial
+	for _, boundary := range gb2312InitialBoundaries {
+		if code < boundary.code {
+			break
+		}
+		initial = boundary.initial
+	}
+	return initial, true
+}
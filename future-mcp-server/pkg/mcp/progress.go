@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+// RequestNotifier 请求级消息发送函数
+// 传输层可以为单个请求提供专用的发送通道（如Streamable HTTP中POST请求的SSE响应流），
+// 与该请求相关的进度、日志等通知优先通过它发送
+type RequestNotifier func(message interface{})
+
+// requestNotifierContextKey 请求级发送函数的上下文键
+type requestNotifierContextKey struct{}
+
+// ContextWithRequestNotifier 将请求级发送函数写入上下文
+func ContextWithRequestNotifier(ctx context.Context, notifier RequestNotifier) context.Context {
+	return context.WithValue(ctx, requestNotifierContextKey{}, notifier)
+}
+
+// notifyRequest 发送与当前请求相关的通知
+// 优先使用请求级发送函数，否则推送到会话，无状态请求没有可用通道时丢弃
+func notifyRequest(ctx context.Context, notification *types.MCPNotification) bool {
+	if notifier, ok := ctx.Value(requestNotifierContextKey{}).(RequestNotifier); ok && notifier != nil {
+		notifier(notification)
+		return true
+	}
+	if session := SessionFromContext(ctx); session != nil {
+		return session.Send(notification) > 0
+	}
+	return false
+}
+
+// newProgressReporter 创建工具进度回调，按MCP要求丢弃不递增的进度值
+func newProgressReporter(ctx context.Context, progressToken interface{}) types.ProgressFunc {
+	var (
+		mu       sync.Mutex
+		last     float64
+		reported bool
+	)
+
+	return func(progress, total float64, message string) {
+		mu.Lock()
+		if reported && progress <= last {
+			mu.Unlock()
+			return
+		}
+		last, reported = progress, true
+		mu.Unlock()
+
+		// 请求已结束或被取消后不再发送进度
+		if ctx.Err() != nil {
+			return
+		}
+
+		params := types.ProgressParams{
+			ProgressToken: progressToken,
+			Progress:      progress,
+			Message:       message,
+		}
+		if total > 0 {
+			params.Total = &total
+		}
+
+		notifyRequest(ctx, &types.MCPNotification{
+			MCPMessage: types.MCPMessage{
+				JSONRPC: "2.0",
+			},
+			Method: types.MCPMethodProgress,
+			Params: params,
+		})
+	}
+}
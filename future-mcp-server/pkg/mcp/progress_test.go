@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+// progressRecorder 记录通过请求级发送函数送达的进度通知
+type progressRecorder struct {
+	mu     sync.Mutex
+	params []types.ProgressParams
+}
+
+func (r *progressRecorder) notify(message interface{}) {
+	notification, ok := message.(*types.MCPNotification)
+	if !ok || notification.Method != types.MCPMethodProgress {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.params = append(r.params, notification.Params.(types.ProgressParams))
+}
+
+func (r *progressRecorder) progress() []float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	values := make([]float64, 0, len(r.params))
+	for _, p := range r.params {
+		values = append(values, p.Progress)
+	}
+	return values
+}
+
+func TestToolProgressNotifications(t *testing.T) {
+	tests := []struct {
+		name         string
+		meta         map[string]interface{}
+		reports      []float64
+		wantProgress []float64
+	}{
+		{
+			name:         "monotonic progress",
+			meta:         map[string]interface{}{"progressToken": "tok"},
+			reports:      []float64{0, 1, 2},
+			wantProgress: []float64{0, 1, 2},
+		},
+		{
+			name:         "non-increasing progress dropped",
+			meta:         map[string]interface{}{"progressToken": float64(5)},
+			reports:      []float64{1, 1, 0.5, 3},
+			wantProgress: []float64{1, 3},
+		},
+		{
+			name:         "no progress token",
+			reports:      []float64{0, 1},
+			wantProgress: []float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(ServerConfig{})
+			s.toolRegistry.RegisterTool(&types.ToolDefinition{
+				Name: "work",
+				Handler: func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+					for _, p := range tt.reports {
+						ctx.ReportProgress(p, 3, "working")
+					}
+					return &types.ToolsCallResponse{Content: []types.Content{{Type: "text", Text: "done"}}}, nil
+				},
+			})
+
+			recorder := &progressRecorder{}
+			ctx := ContextWithRequestNotifier(context.Background(), recorder.notify)
+			params := map[string]interface{}{"name": "work"}
+			if tt.meta != nil {
+				params["_meta"] = tt.meta
+			}
+
+			if response := handle(t, s, ctx, 1, types.MCPMethodToolsCall, params); response.Error != nil {
+				t.Fatalf("tools/call error: %+v", response.Error)
+			}
+			if got := recorder.progress(); !reflect.DeepEqual(got, tt.wantProgress) {
+				t.Fatalf("progress = %v, want %v", got, tt.wantProgress)
+			}
+			for _, p := range recorder.params {
+				if p.ProgressToken != tt.meta["progressToken"] {
+					t.Fatalf("progressToken = %v, want %v", p.ProgressToken, tt.meta["progressToken"])
+				}
+				if p.Total == nil || *p.Total != 3 {
+					t.Fatalf("total = %v, want 3", p.Total)
+				}
+			}
+		})
+	}
+}
+
+func TestProgressFallsBackToSession(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	session, ctx := readySession(t, s)
+
+	report := newProgressReporter(ctx, "tok")
+	report(1, 0, "")
+
+	event := <-session.Events()
+	notification, ok := event.Message.(*types.MCPNotification)
+	if !ok || notification.Method != types.MCPMethodProgress {
+		t.Fatalf("event = %+v, want progress notification", event.Message)
+	}
+	if params := notification.Params.(types.ProgressParams); params.Total != nil {
+		t.Fatalf("total = %v, want omitted when unknown", *params.Total)
+	}
+}
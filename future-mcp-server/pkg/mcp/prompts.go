@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+// handlePromptsList 处理提示列表请求
+func (s *Server) handlePromptsList(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	listReq := &types.PromptsListRequest{}
+	if err := parseParams(request.Params, listReq); err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	prompts := s.promptRegistry.ListPrompts()
+	promptDefs := make([]types.Prompt, 0, len(prompts))
+
+	for _, prompt := range prompts {
+		arguments := make([]types.PromptArgument, len(prompt.Arguments))
+		for i, arg := range prompt.Arguments {
+			arguments[i] = types.PromptArgument{
+				Name:        arg.Name,
+				Description: arg.Description,
+				Required:    arg.Required,
+			}
+		}
+
+		promptDefs = append(promptDefs, types.Prompt{
+			Name:        prompt.Name,
+			Description: prompt.Description,
+			Arguments:   arguments,
+		})
+	}
+
+	cursor, limit := s.paginationParams(listReq.PaginatedRequest)
+	page, nextCursor, err := paginateByKey(s.cursors, types.MCPMethodPromptsList, promptDefs, func(prompt types.Prompt) string {
+		return prompt.Name
+	}, cursor, limit)
+	if err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	return createSuccessResponse(request.ID, &types.PromptsListResponse{
+		Prompts:    page,
+		NextCursor: nextCursor,
+	})
+}
+
+// handlePromptsGet 处理获取提示请求
+func (s *Server) handlePromptsGet(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	getReq := &types.PromptsGetRequest{}
+	if err := parseParams(request.Params, getReq); err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	prompt := s.promptRegistry.GetPrompt(getReq.Name)
+	if prompt == nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, "Prompt not found")
+	}
+
+	args, err := resolvePromptArguments(prompt, getReq.Arguments)
+	if err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	result, err := prompt.Handler(ctx, args)
+	if err != nil {
+		return createErrorResponse(request.ID, types.MCPInternalError, err.Error())
+	}
+
+	return createSuccessResponse(request.ID, result)
+}
+
+// resolvePromptArguments 校验必填参数与可选值，并填充默认值
+func resolvePromptArguments(prompt *types.PromptDefinition, args map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(prompt.Arguments))
+
+	for _, def := range prompt.Arguments {
+		value := strings.TrimSpace(args[def.Name])
+		if value == "" {
+			value = def.Default
+		}
+
+		if value == "" {
+			if def.Required {
+				return nil, fmt.Errorf("missing required argument: %s", def.Name)
+			}
+			continue
+		}
+
+		if len(def.Enum) > 0 && !containsString(def.Enum, value) {
+			return nil, fmt.Errorf("invalid value for argument %s: %s", def.Name, value)
+		}
+
+		resolved[def.Name] = value
+	}
+
+	return resolved, nil
+}
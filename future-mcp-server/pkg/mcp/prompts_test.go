@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+func TestResolvePromptArguments(t *testing.T) {
+	prompt := &types.PromptDefinition{
+		Name: "test",
+		Arguments: []types.PromptArgumentDefinition{
+			{Name: "topic", Required: true},
+			{Name: "grade", Required: true, Enum: []string{"grade_1", "grade_2"}},
+			{Name: "duration", Default: "45"},
+			{Name: "note"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "defaults filled and optional omitted",
+			args: map[string]string{"topic": "分数", "grade": "grade_1"},
+			want: map[string]string{"topic": "分数", "grade": "grade_1", "duration": "45"},
+		},
+		{
+			name: "values trimmed",
+			args: map[string]string{"topic": "  分数 ", "grade": "grade_2", "duration": "40", "note": "x"},
+			want: map[string]string{"topic": "分数", "grade": "grade_2", "duration": "40", "note": "x"},
+		},
+		{
+			name:    "missing required",
+			args:    map[string]string{"grade": "grade_1"},
+			wantErr: "missing required argument: topic",
+		},
+		{
+			name:    "blank required",
+			args:    map[string]string{"topic": "   ", "grade": "grade_1"},
+			wantErr: "missing required argument: topic",
+		},
+		{
+			name:    "value outside enum",
+			args:    map[string]string{"topic": "分数", "grade": "grade_9"},
+			wantErr: "invalid value for argument grade: grade_9",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolvePromptArguments(prompt, tt.args)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Fatalf("%s = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestPromptsGet(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	s.Prompts().RegisterPrompt(&types.PromptDefinition{
+		Name: "lesson",
+		Arguments: []types.PromptArgumentDefinition{
+			{Name: "topic", Required: true},
+			{Name: "grade", Required: true, Enum: []string{"grade_7", "grade_8"}},
+			{Name: "duration", Default: "45"},
+		},
+		Handler: func(ctx context.Context, args map[string]string) (*types.PromptsGetResponse, error) {
+			return &types.PromptsGetResponse{
+				Messages: []types.PromptMessage{{
+					Role:    "user",
+					Content: TextContent(args["topic"] + "，" + args["duration"] + "分钟"),
+				}},
+			}, nil
+		},
+	})
+
+	tests := []struct {
+		name     string
+		params   map[string]interface{}
+		wantCode int
+		wantText string
+	}{
+		{"unknown prompt", map[string]interface{}{"name": "missing"}, types.MCPInvalidParams, ""},
+		{"missing required argument", map[string]interface{}{"name": "lesson", "arguments": map[string]string{"grade": "grade_8"}}, types.MCPInvalidParams, ""},
+		{"invalid enum value", map[string]interface{}{"name": "lesson", "arguments": map[string]string{"topic": "浮力", "grade": "grade_13"}}, types.MCPInvalidParams, ""},
+		{"default applied", map[string]interface{}{"name": "lesson", "arguments": map[string]string{"topic": "浮力", "grade": "grade_8"}}, 0, "浮力，45分钟"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := handle(t, s, context.Background(), 1, types.MCPMethodPromptsGet, tt.params)
+			if code := errorCode(response); code != tt.wantCode {
+				t.Fatalf("error code = %d, want %d (%+v)", code, tt.wantCode, response.Error)
+			}
+			if tt.wantCode != 0 {
+				return
+			}
+
+			result := response.Result.(*types.PromptsGetResponse)
+			if len(result.Messages) != 1 || !strings.Contains(result.Messages[0].Content.Text, tt.wantText) {
+				t.Fatalf("messages = %+v, want text containing %q", result.Messages, tt.wantText)
+			}
+		})
+	}
+}
@@ -6,8 +6,34 @@ import (
 	"github.com/future-mcp/future-mcp-server/internal/types"
 )
 
+// registryObservers 注册器变更观察者
+type registryObservers struct {
+	observers []func()
+	mu        sync.RWMutex
+}
+
+// OnChange 注册列表变更回调
+func (ro *registryObservers) OnChange(fn func()) {
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	ro.observers = append(ro.observers, fn)
+}
+
+// notifyChange 调用变更回调，调用方不应持有注册器的锁
+func (ro *registryObservers) notifyChange() {
+	ro.mu.RLock()
+	callbacks := append([]func(){}, ro.observers...)
+	ro.mu.RUnlock()
+
+	for _, fn := range callbacks {
+		fn()
+	}
+}
+
 // ToolRegistry 工具注册器
 type ToolRegistry struct {
+	registryObservers
+
 	tools map[string]*types.ToolDefinition
 	mu    sync.RWMutex
 }
@@ -22,8 +48,10 @@ func NewToolRegistry() *ToolRegistry {
 // RegisterTool 注册工具
 func (tr *ToolRegistry) RegisterTool(tool *types.ToolDefinition) {
 	tr.mu.Lock()
-	defer tr.mu.Unlock()
 	tr.tools[tool.Name] = tool
+	tr.mu.Unlock()
+
+	tr.notifyChange()
 }
 
 // GetTool 获取工具
@@ -48,16 +76,30 @@ func (tr *ToolRegistry) ListTools() map[string]*types.ToolDefinition {
 // RemoveTool 移除工具
 func (tr *ToolRegistry) RemoveTool(name string) {
 	tr.mu.Lock()
-	defer tr.mu.Unlock()
+	_, exists := tr.tools[name]
 	delete(tr.tools, name)
+	tr.mu.Unlock()
+
+	if exists {
+		tr.notifyChange()
+	}
 }
 
 // ResourceRegistry 资源注册器
 type ResourceRegistry struct {
+	registryObservers
+
 	resources map[string]*types.ResourceDefinition
+	templates []*resourceTemplateEntry
 	mu        sync.RWMutex
 }
 
+// resourceTemplateEntry 已解析的资源模板
+type resourceTemplateEntry struct {
+	definition *types.ResourceTemplateDefinition
+	template   *URITemplate
+}
+
 // NewResourceRegistry 创建资源注册器
 func NewResourceRegistry() *ResourceRegistry {
 	return &ResourceRegistry{
@@ -68,8 +110,10 @@ func NewResourceRegistry() *ResourceRegistry {
 // RegisterResource 注册资源
 func (rr *ResourceRegistry) RegisterResource(resource *types.ResourceDefinition) {
 	rr.mu.Lock()
-	defer rr.mu.Unlock()
 	rr.resources[resource.URI] = resource
+	rr.mu.Unlock()
+
+	rr.notifyChange()
 }
 
 // GetResource 获取资源
@@ -94,8 +138,161 @@ func (rr *ResourceRegistry) ListResources() map[string]*types.ResourceDefinition
 // RemoveResource 移除资源
 func (rr *ResourceRegistry) RemoveResource(uri string) {
 	rr.mu.Lock()
-	defer rr.mu.Unlock()
+	_, exists := rr.resources[uri]
 	delete(rr.resources, uri)
+	rr.mu.Unlock()
+
+	if exists {
+		rr.notifyChange()
+	}
+}
+
+// RegisterTemplate 注册资源模板，同一模板重复注册时覆盖原定义
+func (rr *ResourceRegistry) RegisterTemplate(definition *types.ResourceTemplateDefinition) error {
+	template, err := ParseURITemplate(definition.URITemplate)
+	if err != nil {
+		return err
+	}
+
+	rr.mu.Lock()
+	entry := &resourceTemplateEntry{definition: definition, template: template}
+	replaced := false
+	for i, existing := range rr.templates {
+		if existing.definition.URITemplate == definition.URITemplate {
+			rr.templates[i] = entry
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		rr.templates = append(rr.templates, entry)
+	}
+	rr.mu.Unlock()
+
+	rr.notifyChange()
+	return nil
+}
+
+// ListTemplates 按注册顺序列出所有资源模板
+func (rr *ResourceRegistry) ListTemplates() []*types.ResourceTemplateDefinition {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+
+	templates := make([]*types.ResourceTemplateDefinition, len(rr.templates))
+	for i, entry := range rr.templates {
+		templates[i] = entry.definition
+	}
+	return templates
+}
+
+// GetTemplate 按模板字符串获取资源模板及其变量名
+func (rr *ResourceRegistry) GetTemplate(uriTemplate string) (*types.ResourceTemplateDefinition, []string) {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+
+	for _, entry := range rr.templates {
+		if entry.definition.URITemplate == uriTemplate {
+			return entry.definition, entry.template.Variables()
+		}
+	}
+	return nil, nil
+}
+
+// MatchTemplate 查找与URI匹配的资源模板并提取变量，按注册顺序取第一个匹配项
+func (rr *ResourceRegistry) MatchTemplate(uri string) (*types.ResourceTemplateDefinition, map[string]string) {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+
+	for _, entry := range rr.templates {
+		if vars, ok := entry.template.Match(uri); ok {
+			return entry.definition, vars
+		}
+	}
+	return nil, nil
+}
+
+// RemoveTemplate 移除资源模板
+func (rr *ResourceRegistry) RemoveTemplate(uriTemplate string) {
+	rr.mu.Lock()
+	removed := false
+	for i, entry := range rr.templates {
+		if entry.definition.URITemplate == uriTemplate {
+			rr.templates = append(rr.templates[:i], rr.templates[i+1:]...)
+			removed = true
+			break
+		}
+	}
+	rr.mu.Unlock()
+
+	if removed {
+		rr.notifyChange()
+	}
+}
+
+// Resolve 解析URI对应的处理器：优先精确匹配资源，其次匹配资源模板
+func (rr *ResourceRegistry) Resolve(uri string) (types.ResourceHandler, map[string]string, bool) {
+	if resource := rr.GetResource(uri); resource != nil {
+		return resource.Handler, nil, true
+	}
+	if template, vars := rr.MatchTemplate(uri); template != nil {
+		return template.Handler, vars, true
+	}
+	return nil, nil, false
+}
+
+// PromptRegistry 提示模板注册器
+type PromptRegistry struct {
+	registryObservers
+
+	prompts map[string]*types.PromptDefinition
+	mu      sync.RWMutex
+}
+
+// NewPromptRegistry 创建提示模板注册器
+func NewPromptRegistry() *PromptRegistry {
+	return &PromptRegistry{
+		prompts: make(map[string]*types.PromptDefinition),
+	}
+}
+
+// RegisterPrompt 注册提示模板
+func (pr *PromptRegistry) RegisterPrompt(prompt *types.PromptDefinition) {
+	pr.mu.Lock()
+	pr.prompts[prompt.Name] = prompt
+	pr.mu.Unlock()
+
+	pr.notifyChange()
+}
+
+// GetPrompt 获取提示模板
+func (pr *PromptRegistry) GetPrompt(name string) *types.PromptDefinition {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+	return pr.prompts[name]
+}
+
+// ListPrompts 列出所有提示模板
+func (pr *PromptRegistry) ListPrompts() map[string]*types.PromptDefinition {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+
+	prompts := make(map[string]*types.PromptDefinition)
+	for name, prompt := range pr.prompts {
+		prompts[name] = prompt
+	}
+	return prompts
+}
+
+// RemovePrompt 移除提示模板
+func (pr *PromptRegistry) RemovePrompt(name string) {
+	pr.mu.Lock()
+	_, exists := pr.prompts[name]
+	delete(pr.prompts, name)
+	pr.mu.Unlock()
+
+	if exists {
+		pr.notifyChange()
+	}
 }
 
 // SubscriptionManager 订阅管理器
@@ -111,8 +308,8 @@ func NewSubscriptionManager() *SubscriptionManager {
 	}
 }
 
-// Subscribe 订阅资源
-func (sm *SubscriptionManager) Subscribe(uri, clientID string) chan *types.MCPNotification {
+// Subscribe 订阅资源，同一客户端重复订阅时返回已有通道，created为false
+func (sm *SubscriptionManager) Subscribe(uri, clientID string) (ch chan *types.MCPNotification, created bool) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
@@ -120,10 +317,14 @@ func (sm *SubscriptionManager) Subscribe(uri, clientID string) chan *types.MCPNo
 		sm.subscriptions[uri] = make(map[string]chan *types.MCPNotification)
 	}
 
-	ch := make(chan *types.MCPNotification, 10) // 缓冲通道
+	if existing, exists := sm.subscriptions[uri][clientID]; exists {
+		return existing, false
+	}
+
+	ch = make(chan *types.MCPNotification, 10) // 缓冲通道
 	sm.subscriptions[uri][clientID] = ch
 
-	return ch
+	return ch, true
 }
 
 // Unsubscribe 取消订阅资源
@@ -0,0 +1,26 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+// InvalidResourceError URI格式正确但变量取值无效，返回给客户端的是参数错误而非内部错误
+type InvalidResourceError struct {
+	URI    string
+	Reason string
+}
+
+// Error 实现error接口
+func (e *InvalidResourceError) Error() string {
+	return fmt.Sprintf("invalid resource %s: %s", e.URI, e.Reason)
+}
+
+// WithResourceVars 为固定URI资源绑定变量，使其与资源模板共用同一处理器
+func WithResourceVars(handler types.ResourceHandler, vars map[string]string) types.ResourceHandler {
+	return func(ctx context.Context, uri string, _ map[string]string) (*types.ResourcesReadResponse, error) {
+		return handler(ctx, uri, vars)
+	}
+}
@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+)
+
+var (
+	// ErrSamplingNotSupported 当前连接无会话或客户端未声明sampling能力
+	ErrSamplingNotSupported = errors.New("client does not support sampling")
+	// ErrSamplingInvalidOutput 多次重试后客户端LLM仍未返回符合要求的内容
+	ErrSamplingInvalidOutput = errors.New("sampling returned invalid output")
+)
+
+// samplingMaxAttempts 采样结果校验失败时的最大尝试次数
+const samplingMaxAttempts = 3
+
+// SamplingError 客户端拒绝或处理采样请求失败
+type SamplingError struct {
+	Code    int
+	Message string
+}
+
+// Error 实现error接口
+func (e *SamplingError) Error() string {
+	return fmt.Sprintf("sampling request rejected by client (%d): %s", e.Code, e.Message)
+}
+
+// SupportsSampling 客户端是否声明了sampling能力
+func SupportsSampling(ctx context.Context) bool {
+	session := SessionFromContext(ctx)
+	return session != nil && session.ClientCapabilities().Sampling != nil
+}
+
+// CreateMessage 通过sampling/createMessage请求客户端LLM生成内容
+func CreateMessage(ctx context.Context, request *types.CreateMessageRequest) (*types.CreateMessageResult, error) {
+	if !SupportsSampling(ctx) {
+		return nil, ErrSamplingNotSupported
+	}
+
+	response, err := SessionFromContext(ctx).Request(ctx, types.MCPMethodSamplingCreateMessage, request)
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != nil {
+		return nil, &SamplingError{Code: response.Error.Code, Message: response.Error.Message}
+	}
+
+	data, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+	result := &types.CreateMessageResult{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("invalid sampling result: %w", err)
+	}
+	return result, nil
+}
+
+// SampleJSON 请求客户端LLM生成JSON并解析为T，解析或校验失败时将错误反馈给模型重试
+// 客户端拒绝、会话关闭等非内容问题不重试
+func SampleJSON[T any](ctx context.Context, request *types.CreateMessageRequest, validate func(*T) error) (*T, error) {
+	attempt := *request
+	attempt.Messages = append([]types.SamplingMessage(nil), request.Messages...)
+
+	var lastErr error
+	for i := 1; i <= samplingMaxAttempts; i++ {
+		result, err := CreateMessage(ctx, &attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		target := new(T)
+		lastErr = decodeSampledJSON(result.Content, target)
+		if lastErr == nil {
+			lastErr = validate(target)
+		}
+		if lastErr == nil {
+			return target, nil
+		}
+
+		logger.Warn("Sampled content rejected",
+			logger.Any("attempt", i),
+			logger.Any("model", result.Model),
+			logger.Any("error", lastErr))
+
+		attempt.Messages = append(attempt.Messages,
+			types.SamplingMessage{Role: "assistant", Content: result.Content},
+			types.SamplingMessage{Role: "user", Content: types.Content{
+				Type: "text",
+				Text: fmt.Sprintf("上述输出不符合要求：%v。请只输出修正后的完整JSON，不要包含其他说明文字。", lastErr),
+			}},
+		)
+	}
+
+	return nil, fmt.Errorf("%w after %d attempts: %v", ErrSamplingInvalidOutput, samplingMaxAttempts, lastErr)
+}
+
+// decodeSampledJSON 从模型输出中提取JSON对象，兼容Markdown代码块等包裹形式
+func decodeSampledJSON(content types.Content, target interface{}) error {
+	if content.Type != "text" {
+		return fmt.Errorf("expected text content, got %s", content.Type)
+	}
+
+	text := strings.TrimSpace(content.Text)
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return errors.New("output does not contain a JSON object")
+	}
+
+	if err := json.Unmarshal([]byte(text[start:end+1]), target); err != nil {
+		return fmt.Errorf("invalid JSON: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+// samplingReply 模拟客户端对一次sampling/createMessage的答复，error非空时返回JSON-RPC错误
+type samplingReply struct {
+	text  string
+	error *types.MCPError
+}
+
+// samplingClient 创建声明了sampling能力的会话，按顺序以replies答复服务端的采样请求
+// 返回的切片记录客户端收到的采样请求
+func samplingClient(t *testing.T, replies ...samplingReply) (context.Context, *[]*types.CreateMessageRequest) {
+	t.Helper()
+
+	s := NewServer(ServerConfig{})
+	session := s.Sessions().Create()
+	t.Cleanup(session.Close)
+	capabilities := types.ClientCapabilities{Sampling: &types.SamplingCapability{}}
+	if err := session.Initialize(types.MCPProtocolVersion, types.ImplementationInfo{Name: "test"}, capabilities); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+
+	var received []*types.CreateMessageRequest
+	ctx := ContextWithRequestNotifier(ContextWithSession(context.Background(), session), func(message interface{}) {
+		request, ok := message.(*types.MCPRequest)
+		if !ok || request.Method != types.MCPMethodSamplingCreateMessage {
+			return
+		}
+		params := request.Params.(*types.CreateMessageRequest)
+		snapshot := *params
+		snapshot.Messages = append([]types.SamplingMessage(nil), params.Messages...)
+		received = append(received, &snapshot)
+
+		if len(received) > len(replies) {
+			t.Errorf("unexpected sampling request %d", len(received))
+			return
+		}
+		reply := replies[len(received)-1]
+		response := &types.MCPResponse{MCPMessage: types.MCPMessage{JSONRPC: "2.0", ID: request.ID}, Error: reply.error}
+		if reply.error == nil {
+			response.Result = map[string]interface{}{
+				"role":    "assistant",
+				"model":   "test-model",
+				"content": map[string]string{"type": "text", "text": reply.text},
+			}
+		}
+		session.DeliverResponse(response)
+	})
+	return ctx, &received
+}
+
+// sampledAnswer 测试用的采样结果结构
+type sampledAnswer struct {
+	Answer string `json:"answer"`
+}
+
+func validateAnswer(a *sampledAnswer) error {
+	if a.Answer == "" {
+		return errors.New("answer is required")
+	}
+	return nil
+}
+
+func TestSampleJSONRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		replies      []samplingReply
+		want         string
+		wantErr      error
+		wantRequests int
+	}{
+		{
+			name:         "first attempt valid",
+			replies:      []samplingReply{{text: `{"answer":"42"}`}},
+			want:         "42",
+			wantRequests: 1,
+		},
+		{
+			name:         "markdown fenced json",
+			replies:      []samplingReply{{text: "```json\n{\"answer\":\"x\"}\n```"}},
+			want:         "x",
+			wantRequests: 1,
+		},
+		{
+			name:         "retry after invalid json and failed validation",
+			replies:      []samplingReply{{text: "不是JSON"}, {text: `{"answer":""}`}, {text: `{"answer":"ok"}`}},
+			want:         "ok",
+			wantRequests: 3,
+		},
+		{
+			name:         "gives up after max attempts",
+			replies:      []samplingReply{{text: "a"}, {text: "b"}, {text: "c"}},
+			wantErr:      ErrSamplingInvalidOutput,
+			wantRequests: samplingMaxAttempts,
+		},
+		{
+			name:         "client rejection is not retried",
+			replies:      []samplingReply{{error: &types.MCPError{Code: -1, Message: "user declined"}}},
+			wantRequests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, received := samplingClient(t, tt.replies...)
+
+			got, err := SampleJSON(ctx, &types.CreateMessageRequest{
+				Messages:  []types.SamplingMessage{{Role: "user", Content: types.Content{Type: "text", Text: "question"}}},
+				MaxTokens: 100,
+			}, validateAnswer)
+
+			if len(*received) != tt.wantRequests {
+				t.Fatalf("sampling requests = %d, want %d", len(*received), tt.wantRequests)
+			}
+			switch {
+			case tt.want != "":
+				if err != nil || got.Answer != tt.want {
+					t.Fatalf("sampleJSON = %+v, %v, want %q", got, err, tt.want)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+			default:
+				var rejected *SamplingError
+				if !errors.As(err, &rejected) {
+					t.Fatalf("err = %v, want SamplingError", err)
+				}
+			}
+
+			// 每次重试都附带上一次的输出和修正要求
+			for i, request := range *received {
+				if len(request.Messages) != 1+2*i {
+					t.Fatalf("attempt %d has %d messages, want %d", i+1, len(request.Messages), 1+2*i)
+				}
+				if i > 0 && request.Messages[len(request.Messages)-2].Role != "assistant" {
+					t.Fatalf("attempt %d does not replay previous output", i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestSamplingNotSupported(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	_, ctx := readySession(t, s)
+
+	if _, err := CreateMessage(ctx, &types.CreateMessageRequest{}); !errors.Is(err, ErrSamplingNotSupported) {
+		t.Fatalf("err = %v, want ErrSamplingNotSupported", err)
+	}
+
+}
@@ -0,0 +1,344 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SchemaError 单个字段的JSON Schema校验错误
+type SchemaError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Error 实现error接口
+func (e SchemaError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// SchemaErrors 校验错误列表
+type SchemaErrors []SchemaError
+
+// Error 实现error接口，合并全部错误信息
+func (e SchemaErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// toJSONValue 将Go值转换为通用JSON值（map[string]interface{}、[]interface{}、float64等）
+func toJSONValue(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// validateJSONSchema 按JSON Schema（draft 2020-12子集）校验值，返回全部字段错误
+// 支持type（含类型数组）、enum、required、properties、additionalProperties、items、
+// minimum/maximum/exclusiveMinimum/exclusiveMaximum、minLength/maxLength、minItems/maxItems；
+// schema与value可以是任意可JSON序列化的Go值
+func validateJSONSchema(schema interface{}, value interface{}) error {
+	genericSchema, err := toJSONValue(schema)
+	if err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+	genericValue, err := toJSONValue(value)
+	if err != nil {
+		return fmt.Errorf("invalid value: %w", err)
+	}
+
+	var errs SchemaErrors
+	validateSchemaNode(genericSchema, genericValue, "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateSchemaNode 递归校验单个节点
+func validateSchemaNode(schema interface{}, value interface{}, path string, errs *SchemaErrors) {
+	node, ok := schema.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if types := schemaTypes(node["type"]); len(types) > 0 && !matchesAnySchemaType(types, value) {
+		*errs = append(*errs, SchemaError{Path: path, Message: fmt.Sprintf("expected %s, got %s", strings.Join(types, " or "), jsonTypeName(value))})
+		return
+	}
+
+	if enum, ok := node["enum"].([]interface{}); ok && !containsJSONValue(enum, value) {
+		*errs = append(*errs, SchemaError{Path: path, Message: fmt.Sprintf("must be one of %s", formatJSONValues(enum))})
+	}
+
+	switch v := value.(type) {
+	case float64:
+		validateNumberBounds(node, v, path, errs)
+	case string:
+		length := float64(utf8.RuneCountInString(v))
+		if min, ok := node["minLength"].(float64); ok && length < min {
+			*errs = append(*errs, SchemaError{Path: path, Message: fmt.Sprintf("must be at least %s characters", formatSchemaNumber(min))})
+		}
+		if max, ok := node["maxLength"].(float64); ok && length > max {
+			*errs = append(*errs, SchemaError{Path: path, Message: fmt.Sprintf("must be at most %s characters", formatSchemaNumber(max))})
+		}
+	case map[string]interface{}:
+		if required, ok := node["required"].([]interface{}); ok {
+			for _, name := range required {
+				if key, ok := name.(string); ok {
+					if _, exists := v[key]; !exists {
+						*errs = append(*errs, SchemaError{Path: joinSchemaPath(path, key), Message: "is required"})
+					}
+				}
+			}
+		}
+		if properties, ok := node["properties"].(map[string]interface{}); ok {
+			// 按属性名排序，使错误顺序稳定
+			keys := make([]string, 0, len(properties))
+			for key := range properties {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				if propertyValue, exists := v[key]; exists {
+					validateSchemaNode(properties[key], propertyValue, joinSchemaPath(path, key), errs)
+				}
+			}
+		}
+		validateAdditionalProperties(node, v, path, errs)
+	case []interface{}:
+		count := float64(len(v))
+		if min, ok := node["minItems"].(float64); ok && count < min {
+			*errs = append(*errs, SchemaError{Path: path, Message: fmt.Sprintf("must contain at least %s items", formatSchemaNumber(min))})
+		}
+		if max, ok := node["maxItems"].(float64); ok && count > max {
+			*errs = append(*errs, SchemaError{Path: path, Message: fmt.Sprintf("must contain at most %s items", formatSchemaNumber(max))})
+		}
+		if items, ok := node["items"]; ok {
+			for i, item := range v {
+				validateSchemaNode(items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	}
+}
+
+// validateNumberBounds 校验数值范围
+func validateNumberBounds(node map[string]interface{}, value float64, path string, errs *SchemaErrors) {
+	if min, ok := node["minimum"].(float64); ok && value < min {
+		*errs = append(*errs, SchemaError{Path: path, Message: fmt.Sprintf("must be >= %s", formatSchemaNumber(min))})
+	}
+	if max, ok := node["maximum"].(float64); ok && value > max {
+		*errs = append(*errs, SchemaError{Path: path, Message: fmt.Sprintf("must be <= %s", formatSchemaNumber(max))})
+	}
+	if min, ok := node["exclusiveMinimum"].(float64); ok && value <= min {
+		*errs = append(*errs, SchemaError{Path: path, Message: fmt.Sprintf("must be > %s", formatSchemaNumber(min))})
+	}
+	if max, ok := node["exclusiveMaximum"].(float64); ok && value >= max {
+		*errs = append(*errs, SchemaError{Path: path, Message: fmt.Sprintf("must be < %s", formatSchemaNumber(max))})
+	}
+}
+
+// validateAdditionalProperties 校验properties未声明的字段：additionalProperties为false时拒绝，为Schema时按其校验
+func validateAdditionalProperties(node map[string]interface{}, value map[string]interface{}, path string, errs *SchemaErrors) {
+	additional, ok := node["additionalProperties"]
+	if !ok {
+		return
+	}
+	properties, _ := node["properties"].(map[string]interface{})
+
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		if _, declared := properties[key]; !declared {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if allowed, ok := additional.(bool); ok {
+			if !allowed {
+				*errs = append(*errs, SchemaError{Path: joinSchemaPath(path, key), Message: "is not allowed"})
+			}
+			continue
+		}
+		validateSchemaNode(additional, value[key], joinSchemaPath(path, key), errs)
+	}
+}
+
+// applySchemaDefaults 为对象中缺省的属性填入Schema声明的default值，递归处理嵌套对象与数组元素
+// value须为通用JSON值，原地修改对象并返回处理后的值
+func applySchemaDefaults(schema interface{}, value interface{}) interface{} {
+	node, ok := schema.(map[string]interface{})
+	if !ok {
+		return value
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := node["properties"].(map[string]interface{})
+		for key, property := range properties {
+			if current, exists := v[key]; exists {
+				v[key] = applySchemaDefaults(property, current)
+				continue
+			}
+			if propertyNode, ok := property.(map[string]interface{}); ok {
+				if defaultValue, ok := propertyNode["default"]; ok {
+					v[key] = defaultValue
+				}
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = applySchemaDefaults(node["items"], item)
+		}
+	}
+	return value
+}
+
+// validateToolArguments 按工具的InputSchema注入默认值并校验参数，返回处理后的参数
+// 参数缺省时视为空对象；校验失败时返回SchemaErrors
+func validateToolArguments(inputSchema interface{}, arguments interface{}) (interface{}, error) {
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	if inputSchema == nil {
+		return arguments, nil
+	}
+
+	schema, err := toJSONValue(inputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid input schema: %w", err)
+	}
+	value, err := toJSONValue(arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	value = applySchemaDefaults(schema, value)
+
+	var errs SchemaErrors
+	validateSchemaNode(schema, value, "", &errs)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return value, nil
+}
+
+// schemaTypes 解析type关键字，支持字符串与字符串数组
+func schemaTypes(raw interface{}) []string {
+	switch t := raw.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, item := range t {
+			if name, ok := item.(string); ok {
+				types = append(types, name)
+			}
+		}
+		return types
+	default:
+		return nil
+	}
+}
+
+// matchesAnySchemaType 值是否匹配任一JSON Schema类型
+func matchesAnySchemaType(types []string, value interface{}) bool {
+	for _, t := range types {
+		if matchesSchemaType(t, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesSchemaType 值是否匹配JSON Schema类型
+func matchesSchemaType(schemaType string, value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return schemaType == "null"
+	case bool:
+		return schemaType == "boolean"
+	case string:
+		return schemaType == "string"
+	case float64:
+		return schemaType == "number" || (schemaType == "integer" && v == math.Trunc(v))
+	case []interface{}:
+		return schemaType == "array"
+	case map[string]interface{}:
+		return schemaType == "object"
+	default:
+		return false
+	}
+}
+
+// jsonTypeName 通用JSON值的类型名
+func jsonTypeName(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// containsJSONValue 检查通用JSON值列表是否包含指定值
+func containsJSONValue(values []interface{}, target interface{}) bool {
+	for _, value := range values {
+		if reflect.DeepEqual(value, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatJSONValues 格式化可选值列表
+func formatJSONValues(values []interface{}) string {
+	data, _ := json.Marshal(values)
+	return string(data)
+}
+
+// formatSchemaNumber 格式化Schema中的数值，整数不带小数部分
+func formatSchemaNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// joinSchemaPath 拼接字段路径
+func joinSchemaPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,200 @@
+package mcp
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// lessonSchema 覆盖必填、枚举、数值与长度范围、嵌套对象与数组的测试Schema
+var lessonSchema = map[string]interface{}{
+	"type":                 "object",
+	"required":             []string{"title", "grade"},
+	"additionalProperties": false,
+	"properties": map[string]interface{}{
+		"title": map[string]interface{}{"type": "string", "minLength": 2, "maxLength": 10},
+		"grade": map[string]interface{}{"type": "string", "enum": []string{"grade_7", "grade_8"}},
+		"hours": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 4, "default": 1},
+		"score": map[string]interface{}{"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 100},
+		"tags": map[string]interface{}{
+			"type":     "array",
+			"minItems": 1,
+			"maxItems": 2,
+			"items":    map[string]interface{}{"type": "string", "enum": []string{"algebra", "geometry"}},
+		},
+		"author": map[string]interface{}{
+			"type":                 "object",
+			"required":             []string{"name"},
+			"additionalProperties": false,
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{"type": "string"},
+				"age":  map[string]interface{}{"type": []string{"integer", "null"}},
+			},
+		},
+		"sections": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":     "object",
+				"required": []string{"minutes"},
+				"properties": map[string]interface{}{
+					"minutes": map[string]interface{}{"type": "integer", "minimum": 5, "default": 10},
+					"kind":    map[string]interface{}{"type": "string", "default": "lecture"},
+				},
+			},
+		},
+		"extra": map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": map[string]interface{}{"type": "integer"},
+		},
+	},
+}
+
+func TestValidateToolArguments(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments interface{}
+		wantErrs  SchemaErrors
+	}{
+		{
+			name:      "valid",
+			arguments: map[string]interface{}{"title": "一元二次", "grade": "grade_8", "hours": 2, "score": 99.5, "tags": []string{"algebra"}},
+		},
+		{
+			name:      "missing required",
+			arguments: nil,
+			wantErrs:  SchemaErrors{{"title", "is required"}, {"grade", "is required"}},
+		},
+		{
+			name:      "wrong type",
+			arguments: map[string]interface{}{"title": 1, "grade": "grade_8"},
+			wantErrs:  SchemaErrors{{"title", "expected string, got integer"}},
+		},
+		{
+			name:      "integer rejects fraction",
+			arguments: map[string]interface{}{"title": "方程", "grade": "grade_8", "hours": 1.5},
+			wantErrs:  SchemaErrors{{"hours", "expected integer, got number"}},
+		},
+		{
+			name:      "enum",
+			arguments: map[string]interface{}{"title": "方程", "grade": "grade_9"},
+			wantErrs:  SchemaErrors{{"grade", `must be one of ["grade_7","grade_8"]`}},
+		},
+		{
+			name:      "minimum and maximum",
+			arguments: map[string]interface{}{"title": "方程", "grade": "grade_8", "hours": 5, "score": 0},
+			wantErrs:  SchemaErrors{{"hours", "must be <= 4"}, {"score", "must be > 0"}},
+		},
+		{
+			name:      "exclusive maximum",
+			arguments: map[string]interface{}{"title": "方程", "grade": "grade_8", "hours": 0, "score": 100},
+			wantErrs:  SchemaErrors{{"hours", "must be >= 1"}, {"score", "must be < 100"}},
+		},
+		{
+			name:      "string length counts characters",
+			arguments: map[string]interface{}{"title": "方", "grade": "grade_8"},
+			wantErrs:  SchemaErrors{{"title", "must be at least 2 characters"}},
+		},
+		{
+			name:      "max length",
+			arguments: map[string]interface{}{"title": "一元二次方程的解法与应用", "grade": "grade_8"},
+			wantErrs:  SchemaErrors{{"title", "must be at most 10 characters"}},
+		},
+		{
+			name:      "array bounds and item enum",
+			arguments: map[string]interface{}{"title": "方程", "grade": "grade_8", "tags": []string{"algebra", "physics", "geometry"}},
+			wantErrs:  SchemaErrors{{"tags", "must contain at most 2 items"}, {"tags[1]", `must be one of ["algebra","geometry"]`}},
+		},
+		{
+			name:      "empty array",
+			arguments: map[string]interface{}{"title": "方程", "grade": "grade_8", "tags": []string{}},
+			wantErrs:  SchemaErrors{{"tags", "must contain at least 1 items"}},
+		},
+		{
+			name: "nested object",
+			arguments: map[string]interface{}{"title": "方程", "grade": "grade_8",
+				"author": map[string]interface{}{"age": "十二", "email": "a@example.com"}},
+			wantErrs: SchemaErrors{{"author.name", "is required"}, {"author.age", "expected integer or null, got string"}, {"author.email", "is not allowed"}},
+		},
+		{
+			name: "nullable type",
+			arguments: map[string]interface{}{"title": "方程", "grade": "grade_8",
+				"author": map[string]interface{}{"name": "王老师", "age": nil}},
+		},
+		{
+			name: "array of objects",
+			arguments: map[string]interface{}{"title": "方程", "grade": "grade_8",
+				"sections": []interface{}{map[string]interface{}{"minutes": 20}, map[string]interface{}{"minutes": 3}}},
+			wantErrs: SchemaErrors{{"sections[1].minutes", "must be >= 5"}},
+		},
+		{
+			name:      "additional properties",
+			arguments: map[string]interface{}{"title": "方程", "grade": "grade_8", "unknown": true},
+			wantErrs:  SchemaErrors{{"unknown", "is not allowed"}},
+		},
+		{
+			name:      "additional properties schema",
+			arguments: map[string]interface{}{"title": "方程", "grade": "grade_8", "extra": map[string]interface{}{"a": 1, "b": "x"}},
+			wantErrs:  SchemaErrors{{"extra.b", "expected integer, got string"}},
+		},
+		{
+			name:      "root type",
+			arguments: []string{"方程"},
+			wantErrs:  SchemaErrors{{"", "expected object, got array"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateToolArguments(lessonSchema, tt.arguments)
+			if tt.wantErrs == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var errs SchemaErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("err = %v, want SchemaErrors", err)
+			}
+			if !reflect.DeepEqual(errs, tt.wantErrs) {
+				t.Fatalf("errors = %q, want %q", errs, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestValidateToolArgumentsDefaults(t *testing.T) {
+	arguments := map[string]interface{}{
+		"title":    "方程",
+		"grade":    "grade_8",
+		"sections": []interface{}{map[string]interface{}{"minutes": 20}, map[string]interface{}{}},
+	}
+
+	// 缺省的必填字段由default补齐后通过校验
+	value, err := validateToolArguments(lessonSchema, arguments)
+	if err != nil {
+		t.Fatalf("validateToolArguments: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"title": "方程",
+		"grade": "grade_8",
+		"hours": float64(1),
+		"sections": []interface{}{
+			map[string]interface{}{"minutes": float64(20), "kind": "lecture"},
+			map[string]interface{}{"minutes": float64(10), "kind": "lecture"},
+		},
+	}
+	if !reflect.DeepEqual(value, want) {
+		t.Fatalf("value = %#v, want %#v", value, want)
+	}
+}
+
+func TestSchemaErrorMessage(t *testing.T) {
+	errs := SchemaErrors{{"", "expected object, got array"}, {"author.name", "is required"}}
+	want := "expected object, got array; author.name: is required"
+	if errs.Error() != want {
+		t.Fatalf("Error() = %q, want %q", errs.Error(), want)
+	}
+}
@@ -0,0 +1,306 @@
+package mcp
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// schemaMode 生成Schema的用途
+type schemaMode int
+
+const (
+	// schemaModeInput 工具输入：字段仅在标记required时必填，对象不允许未声明的字段
+	schemaModeInput schemaMode = iota
+	// schemaModeOutput 工具输出：未标记omitempty的字段总会序列化，视为必填；nil切片、映射与指针序列化为null
+	schemaModeOutput
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	uuidType          = reflect.TypeOf(uuid.UUID{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaForType 根据Go类型生成JSON Schema
+// 字段名取自json标签，description标签为字段说明，jsonschema标签以逗号分隔支持：
+// required、enum=a|b|c、minimum=值、maximum=值、minLength=值、maxLength=值、minItems=值、maxItems=值、default=值；
+// 切片字段的enum作用于元素。default必须是最后一个选项，其后的内容（含逗号）都作为取值，以便书写JSON数组或对象
+func schemaForType(t reflect.Type, mode schemaMode) (map[string]interface{}, error) {
+	return (&schemaGenerator{mode: mode, visiting: map[reflect.Type]bool{}}).schema(t)
+}
+
+// schemaGenerator 递归生成Schema，visiting用于截断自引用类型
+type schemaGenerator struct {
+	mode     schemaMode
+	visiting map[reflect.Type]bool
+}
+
+// schema 生成单个类型的Schema
+func (g *schemaGenerator) schema(t reflect.Type) (map[string]interface{}, error) {
+	if t.Kind() == reflect.Ptr {
+		schema, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return g.nullable(schema), nil
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+	case t == uuidType:
+		return map[string]interface{}{"type": "string", "format": "uuid"}, nil
+	case t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType):
+		return map[string]interface{}{"type": "string"}, nil
+	case t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType):
+		// 自定义序列化的类型无法推断结构，不做约束
+		return map[string]interface{}{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case reflect.Interface:
+		return map[string]interface{}{}, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte按base64字符串序列化
+			return g.nullable(map[string]interface{}{"type": "string"}), nil
+		}
+		items, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return g.nullable(map[string]interface{}{"type": "array", "items": items}), nil
+	case reflect.Array:
+		items, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": items, "minItems": t.Len(), "maxItems": t.Len()}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+		values, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return g.nullable(map[string]interface{}{"type": "object", "additionalProperties": values}), nil
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// structSchema 生成结构体的Schema，匿名嵌入的结构体字段提升到外层
+func (g *schemaGenerator) structSchema(t reflect.Type) (map[string]interface{}, error) {
+	if g.visiting[t] {
+		return map[string]interface{}{"type": "object"}, nil
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	properties := map[string]interface{}{}
+	required := []string{}
+	if err := g.collectFields(t, properties, &required); err != nil {
+		return nil, err
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if g.mode == schemaModeInput {
+		schema["additionalProperties"] = false
+	}
+	return schema, nil
+}
+
+// collectFields 收集结构体字段的Schema
+func (g *schemaGenerator) collectFields(t reflect.Type, properties map[string]interface{}, required *[]string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitEmpty, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := g.collectFields(embedded, properties, required); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema, err := g.schema(field.Type)
+		if err != nil {
+			return fmt.Errorf("field %s.%s: %w", t.Name(), field.Name, err)
+		}
+
+		isRequired, err := applySchemaTags(schema, field)
+		if err != nil {
+			return fmt.Errorf("field %s.%s: %w", t.Name(), field.Name, err)
+		}
+
+		properties[name] = schema
+		if isRequired || (g.mode == schemaModeOutput && !omitEmpty) {
+			*required = append(*required, name)
+		}
+	}
+	return nil
+}
+
+// nullable 输出模式下允许null
+func (g *schemaGenerator) nullable(schema map[string]interface{}) map[string]interface{} {
+	if g.mode != schemaModeOutput {
+		return schema
+	}
+	if schemaType, ok := schema["type"].(string); ok {
+		schema["type"] = []string{schemaType, "null"}
+	}
+	return schema
+}
+
+// jsonFieldName 解析json标签，返回字段名（未指定时为空）、是否omitempty以及是否跳过
+func jsonFieldName(field reflect.StructField) (string, bool, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	return name, containsString(strings.Split(options, ","), "omitempty"), false
+}
+
+// applySchemaTags 将description与jsonschema标签写入字段Schema，返回字段是否必填
+func applySchemaTags(schema map[string]interface{}, field reflect.StructField) (bool, error) {
+	if description := field.Tag.Get("description"); description != "" {
+		schema["description"] = description
+	}
+
+	tag := field.Tag.Get("jsonschema")
+	if tag == "" {
+		return false, nil
+	}
+
+	// 切片字段的enum约束元素取值
+	valueSchema, valueType := schema, field.Type
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+	if valueType.Kind() == reflect.Slice {
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			valueSchema = items
+			valueType = valueType.Elem()
+		}
+	}
+
+	// default的取值可能包含逗号（如JSON数组），取标签剩余部分整体解析
+	options, defaultValue, hasDefault := tag, "", false
+	if strings.HasPrefix(strings.TrimSpace(tag), "default=") {
+		options, defaultValue, hasDefault = "", strings.TrimPrefix(strings.TrimSpace(tag), "default="), true
+	} else if i := strings.Index(tag, ",default="); i >= 0 {
+		options, defaultValue, hasDefault = tag[:i], tag[i+len(",default="):], true
+	}
+	if hasDefault {
+		parsed, err := parseSchemaTagValue(field.Type, defaultValue)
+		if err != nil {
+			return false, fmt.Errorf("invalid default value %q: %w", defaultValue, err)
+		}
+		schema["default"] = parsed
+	}
+
+	required := false
+	for _, option := range strings.Split(options, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch key {
+		case "required":
+			required = true
+		case "enum":
+			options := strings.Split(value, "|")
+			enum := make([]interface{}, len(options))
+			for i, option := range options {
+				parsed, err := parseSchemaTagValue(valueType, option)
+				if err != nil {
+					return false, fmt.Errorf("invalid enum value %q: %w", option, err)
+				}
+				enum[i] = parsed
+			}
+			valueSchema["enum"] = enum
+		case "minimum", "maximum":
+			bound, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return false, fmt.Errorf("invalid %s value %q: %w", key, value, err)
+			}
+			schema[key] = bound
+		case "minLength", "maxLength", "minItems", "maxItems":
+			bound, err := strconv.Atoi(value)
+			if err != nil || bound < 0 {
+				return false, fmt.Errorf("invalid %s value %q", key, value)
+			}
+			schema[key] = bound
+		case "":
+		default:
+			return false, fmt.Errorf("unknown jsonschema option %q", key)
+		}
+	}
+	return required, nil
+}
+
+// parseSchemaTagValue 按字段类型解析标签中的取值
+func parseSchemaTagValue(t reflect.Type, value string) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return value, nil
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseInt(value, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	default:
+		// 复杂类型的取值以JSON书写
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+			return nil, err
+		}
+		return parsed, nil
+	}
+}
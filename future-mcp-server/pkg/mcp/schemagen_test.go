@@ -0,0 +1,367 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/google/uuid"
+)
+
+type schemaAuthor struct {
+	Name string `json:"name" jsonschema:"required,minLength=1"`
+}
+
+type schemaSection struct {
+	Minutes int    `json:"minutes" jsonschema:"required,minimum=5,maximum=45"`
+	Kind    string `json:"kind,omitempty" jsonschema:"enum=lecture|practice,default=lecture"`
+}
+
+type schemaBase struct {
+	ID uuid.UUID `json:"id"`
+}
+
+type schemaLesson struct {
+	schemaBase
+	Title     string          `json:"title" description:"课程标题" jsonschema:"required,maxLength=20"`
+	Grade     string          `json:"grade" jsonschema:"required,enum=grade_7|grade_8"`
+	Hours     int             `json:"hours,omitempty" jsonschema:"minimum=1,default=1"`
+	Score     *float64        `json:"score,omitempty"`
+	Tags      []string        `json:"tags,omitempty" jsonschema:"enum=algebra|geometry,maxItems=3"`
+	Author    *schemaAuthor   `json:"author,omitempty"`
+	Sections  []schemaSection `json:"sections" jsonschema:"minItems=1"`
+	Metadata  map[string]int  `json:"metadata,omitempty"`
+	StartsAt  time.Time       `json:"startsAt"`
+	Published bool            `json:"published"`
+	Internal  string          `json:"-"`
+	hidden    string
+}
+
+// schemaNode 按路径取出嵌套的Schema节点
+func schemaNode(t *testing.T, schema map[string]interface{}, path ...string) map[string]interface{} {
+	t.Helper()
+
+	node := schema
+	for _, key := range path {
+		next, ok := node[key].(map[string]interface{})
+		if !ok {
+			t.Fatalf("schema has no %s in %v", strings.Join(path, "."), node)
+		}
+		node = next
+	}
+	return node
+}
+
+func TestSchemaForTypeInput(t *testing.T) {
+	schema, err := schemaForType(reflect.TypeOf(schemaLesson{}), schemaModeInput)
+	if err != nil {
+		t.Fatalf("schemaForType: %v", err)
+	}
+
+	if schema["additionalProperties"] != false {
+		t.Fatalf("input schema should reject additional properties: %v", schema)
+	}
+	if required := schema["required"]; !reflect.DeepEqual(required, []string{"title", "grade"}) {
+		t.Fatalf("required = %v", required)
+	}
+
+	properties := schemaNode(t, schema, "properties")
+	for _, name := range []string{"Internal", "hidden", "schemaBase"} {
+		if _, ok := properties[name]; ok {
+			t.Fatalf("property %s should be skipped", name)
+		}
+	}
+
+	tests := []struct {
+		name string
+		path []string
+		want map[string]interface{}
+	}{
+		{"embedded field promoted", []string{"id"}, map[string]interface{}{"type": "string", "format": "uuid"}},
+		{"description and maxLength", []string{"title"}, map[string]interface{}{"type": "string", "description": "课程标题", "maxLength": 20}},
+		{"enum", []string{"grade"}, map[string]interface{}{"type": "string", "enum": []interface{}{"grade_7", "grade_8"}}},
+		{"default and minimum", []string{"hours"}, map[string]interface{}{"type": "integer", "default": int64(1), "minimum": float64(1)}},
+		{"pointer", []string{"score"}, map[string]interface{}{"type": "number"}},
+		{"slice enum applies to items", []string{"tags"}, map[string]interface{}{
+			"type": "array", "maxItems": 3,
+			"items": map[string]interface{}{"type": "string", "enum": []interface{}{"algebra", "geometry"}},
+		}},
+		{"nested object", []string{"author"}, map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{"name": map[string]interface{}{"type": "string", "minLength": 1}},
+			"required":             []string{"name"},
+			"additionalProperties": false,
+		}},
+		{"array of objects", []string{"sections"}, map[string]interface{}{
+			"type": "array", "minItems": 1,
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"minutes": map[string]interface{}{"type": "integer", "minimum": float64(5), "maximum": float64(45)},
+					"kind":    map[string]interface{}{"type": "string", "enum": []interface{}{"lecture", "practice"}, "default": "lecture"},
+				},
+				"required":             []string{"minutes"},
+				"additionalProperties": false,
+			},
+		}},
+		{"map", []string{"metadata"}, map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "integer"}}},
+		{"time", []string{"startsAt"}, map[string]interface{}{"type": "string", "format": "date-time"}},
+		{"bool", []string{"published"}, map[string]interface{}{"type": "boolean"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schemaNode(t, properties, tt.path...); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("schema = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaForTypeOutput(t *testing.T) {
+	schema, err := schemaForType(reflect.TypeOf(schemaLesson{}), schemaModeOutput)
+	if err != nil {
+		t.Fatalf("schemaForType: %v", err)
+	}
+
+	if _, ok := schema["additionalProperties"]; ok {
+		t.Fatalf("output schema should allow additional properties: %v", schema)
+	}
+	// 未标记omitempty的字段总会序列化，视为必填
+	wantRequired := []string{"id", "title", "grade", "sections", "startsAt", "published"}
+	if required := schema["required"]; !reflect.DeepEqual(required, wantRequired) {
+		t.Fatalf("required = %v, want %v", required, wantRequired)
+	}
+
+	properties := schemaNode(t, schema, "properties")
+	tests := []struct {
+		name string
+		want interface{}
+	}{
+		{"score", []string{"number", "null"}},
+		{"tags", []string{"array", "null"}},
+		{"author", []string{"object", "null"}},
+		{"metadata", []string{"object", "null"}},
+		{"title", "string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schemaNode(t, properties, tt.name)["type"]; !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("type = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaForTypeValidatesValues(t *testing.T) {
+	schema, err := schemaForType(reflect.TypeOf(schemaLesson{}), schemaModeInput)
+	if err != nil {
+		t.Fatalf("schemaForType: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		arguments string
+		wantErrs  SchemaErrors
+	}{
+		{
+			name:      "valid",
+			arguments: `{"title":"方程","grade":"grade_8","sections":[{"minutes":10}],"tags":["algebra"],"author":{"name":"王老师"}}`,
+		},
+		{
+			name:      "generated constraints",
+			arguments: `{"title":"方程","grade":"grade_9","hours":0,"sections":[{"minutes":50,"kind":"exam"}],"tags":["physics"],"author":{"name":""}}`,
+			wantErrs: SchemaErrors{
+				{"author.name", "must be at least 1 characters"},
+				{"grade", `must be one of ["grade_7","grade_8"]`},
+				{"hours", "must be >= 1"},
+				{"sections[0].kind", `must be one of ["lecture","practice"]`},
+				{"sections[0].minutes", "must be <= 45"},
+				{"tags[0]", `must be one of ["algebra","geometry"]`},
+			},
+		},
+		{
+			name:      "nested required and unknown fields",
+			arguments: `{"title":"方程","grade":"grade_8","sections":[{}],"author":{},"Internal":"x"}`,
+			wantErrs: SchemaErrors{
+				{"author.name", "is required"},
+				{"sections[0].minutes", "is required"},
+				{"Internal", "is not allowed"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var arguments interface{}
+			if err := json.Unmarshal([]byte(tt.arguments), &arguments); err != nil {
+				t.Fatalf("decode arguments: %v", err)
+			}
+
+			_, err := validateToolArguments(schema, arguments)
+			if tt.wantErrs == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var errs SchemaErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("err = %v, want SchemaErrors", err)
+			}
+			if !reflect.DeepEqual(errs, tt.wantErrs) {
+				t.Fatalf("errors = %q, want %q", errs, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestSchemaTagDefaultWithCommas(t *testing.T) {
+	type input struct {
+		Tags   []string          `json:"tags,omitempty" jsonschema:"minItems=1,default=[\"a\",\"b\"]"`
+		Labels map[string]string `json:"labels,omitempty" jsonschema:"default={\"x\":\"1\",\"y\":\"2\"}"`
+		Title  string            `json:"title,omitempty" jsonschema:"maxLength=20,default=a,b"`
+	}
+
+	schema, err := schemaForType(reflect.TypeOf(input{}), schemaModeInput)
+	if err != nil {
+		t.Fatalf("schemaForType: %v", err)
+	}
+	properties := schema["properties"].(map[string]interface{})
+
+	tags := properties["tags"].(map[string]interface{})
+	if want := []interface{}{"a", "b"}; !reflect.DeepEqual(tags["default"], want) || tags["minItems"] != 1 {
+		t.Fatalf("tags schema = %v, want default %v and minItems 1", tags, want)
+	}
+	labels := properties["labels"].(map[string]interface{})
+	if want := map[string]interface{}{"x": "1", "y": "2"}; !reflect.DeepEqual(labels["default"], want) {
+		t.Fatalf("labels default = %v, want %v", labels["default"], want)
+	}
+	title := properties["title"].(map[string]interface{})
+	if title["default"] != "a,b" || title["maxLength"] != 20 {
+		t.Fatalf("title schema = %v", title)
+	}
+}
+
+func TestSchemaForTypeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantErr string
+	}{
+		{"unknown option", struct {
+			A string `json:"a" jsonschema:"pattern=^a"`
+		}{}, `field .A: unknown jsonschema option "pattern"`},
+		{"invalid enum value", struct {
+			A int `json:"a" jsonschema:"enum=1|two"`
+		}{}, `field .A: invalid enum value "two"`},
+		{"invalid default", struct {
+			A bool `json:"a" jsonschema:"default=maybe"`
+		}{}, `field .A: invalid default value "maybe"`},
+		{"default before other options", struct {
+			A int `json:"a" jsonschema:"default=1,minimum=1"`
+		}{}, `field .A: invalid default value "1,minimum=1"`},
+		{"invalid minimum", struct {
+			A int `json:"a" jsonschema:"minimum=low"`
+		}{}, `field .A: invalid minimum value "low"`},
+		{"negative maxLength", struct {
+			A string `json:"a" jsonschema:"maxLength=-1"`
+		}{}, `field .A: invalid maxLength value "-1"`},
+		{"unsupported map key", struct {
+			A map[int]string `json:"a"`
+		}{}, "field .A: unsupported map key type int"},
+		{"unsupported type", struct {
+			A chan int `json:"a"`
+		}{}, "field .A: unsupported type chan int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := schemaForType(reflect.TypeOf(tt.value), schemaModeInput)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want prefix %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// typedLessonInput 测试用的强类型工具输入
+type typedLessonInput struct {
+	Topic string `json:"topic" jsonschema:"required"`
+	Hours int    `json:"hours,omitempty" jsonschema:"default=1"`
+}
+
+// typedLessonOutput 测试用的强类型工具输出
+type typedLessonOutput struct {
+	Summary string `json:"summary"`
+}
+
+func TestRegisterTypedTool(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	err := RegisterTypedTool(s.Tools(), &types.ToolDefinition{Name: "lesson"},
+		func(ctx *types.ToolContext, input *typedLessonInput) (*typedLessonOutput, error) {
+			if input.Topic == "fail" {
+				return nil, &ToolError{Message: "无法生成"}
+			}
+			return &typedLessonOutput{Summary: fmt.Sprintf("%s/%d", input.Topic, input.Hours)}, nil
+		})
+	if err != nil {
+		t.Fatalf("RegisterTypedTool: %v", err)
+	}
+
+	tool := s.Tools().GetTool("lesson")
+	if tool == nil || tool.InputSchema == nil || tool.OutputSchema == nil {
+		t.Fatalf("schemas not generated: %+v", tool)
+	}
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		wantCode  int
+		wantText  string
+		wantError bool
+	}{
+		{"decoded input", map[string]interface{}{"topic": "方程", "hours": 2}, 0, "方程/2", false},
+		{"unknown field rejected", map[string]interface{}{"topic": "方程", "extra": true}, types.MCPInvalidParams, "", false},
+		{"tool error as result", map[string]interface{}{"topic": "fail"}, 0, "无法生成", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := handle(t, s, context.Background(), 1, types.MCPMethodToolsCall, map[string]interface{}{
+				"name": "lesson", "arguments": tt.arguments,
+			})
+			if code := errorCode(response); code != tt.wantCode {
+				t.Fatalf("error code = %d, want %d (%+v)", code, tt.wantCode, response.Error)
+			}
+			if tt.wantCode != 0 {
+				return
+			}
+			result := response.Result.(*types.ToolsCallResponse)
+			if result.IsError != tt.wantError || len(result.Content) != 1 || !strings.Contains(result.Content[0].Text, tt.wantText) {
+				t.Fatalf("result = %+v", result)
+			}
+		})
+	}
+}
+
+func TestRegisterTypedToolSchemaError(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	err := RegisterTypedTool(s.Tools(), &types.ToolDefinition{Name: "broken"},
+		func(ctx *types.ToolContext, input *struct {
+			C chan int `json:"c"`
+		}) (*typedLessonOutput, error) {
+			return nil, nil
+		})
+	if err == nil {
+		t.Fatal("expected schema generation error")
+	}
+	if s.Tools().GetTool("broken") != nil {
+		t.Fatal("tool with invalid schema should not be registered")
+	}
+}
@@ -0,0 +1,750 @@
+// Package mcp 提供与传输无关的MCP服务端框架：JSON-RPC方法分发、工具/资源/提示模板注册器、
+// 中间件链与会话管理。业务方以Module的形式注册自己的工具、资源与提示模板，
+// 由HTTP、SSE、WebSocket或stdio等传输层将消息交给Server.HandleRequest处理。
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// Server MCP服务端
+type Server struct {
+	config              ServerConfig
+	methods             map[string]Handler
+	middleware          []Middleware
+	toolMiddleware      []ToolMiddleware
+	modules             []string
+	completers          map[string]*ArgumentCompleter
+	toolRegistry        *ToolRegistry
+	resourceRegistry    *ResourceRegistry
+	promptRegistry      *PromptRegistry
+	subscriptionManager *SubscriptionManager
+	sessionManager      *SessionManager
+	cursors             *CursorCodec
+	limiter             *toolLimiter
+	mu                  sync.RWMutex
+}
+
+// ServerConfig MCP服务端配置
+type ServerConfig struct {
+	// ServerInfo initialize响应中返回的服务端名称与版本
+	ServerInfo types.ImplementationInfo
+	// Instructions initialize响应中返回的使用说明，供客户端提示模型
+	Instructions string
+
+	// Permissions 校验工具声明的权限，为nil时不校验
+	Permissions PermissionChecker
+
+	// SessionIdleTimeout 会话空闲超时，为0时使用默认值
+	SessionIdleTimeout time.Duration
+
+	// BatchMaxSize 单个批量请求允许的最大消息数，为0时使用默认值
+	BatchMaxSize int
+	// BatchConcurrency 批量请求内并发执行的最大消息数，为0时使用默认值
+	BatchConcurrency int
+
+	// ListChangedDebounce 注册器变更后合并发送list_changed通知的等待时间，为0时使用默认值
+	ListChangedDebounce time.Duration
+
+	// Cursors 分页游标编解码器，可与业务服务共用以便游标格式一致，为nil时使用随机密钥
+	Cursors *CursorCodec
+	// ListPageSize tools/list等列表的每页条数上限，为0时使用默认值
+	ListPageSize int
+
+	// ToolTimeout 未单独设置超时的工具的执行超时，为0时使用默认值
+	ToolTimeout time.Duration
+	// ToolTimeouts 按工具名设置的执行超时，优先于工具定义中的超时
+	ToolTimeouts map[string]time.Duration
+
+	// ToolConcurrency 未单独设置并发上限的工具同时执行的调用数上限，为0时使用默认值
+	ToolConcurrency int
+	// ToolConcurrencyLimits 按工具名设置的并发上限，优先于工具定义中的上限
+	ToolConcurrencyLimits map[string]int
+	// ToolQueueTimeout 工具执行槽位已满时的排队等待时间，为0时使用默认值
+	ToolQueueTimeout time.Duration
+	// ElicitationTimeout 调用工具前等待用户填写缺失参数的时间，为0时使用默认值
+	ElicitationTimeout time.Duration
+	// UserLimits 按用户配额限制同时执行的工具调用数，为nil时不限制
+	UserLimits UserConcurrencyLimiter
+}
+
+// PermissionChecker 校验用户是否具备资源上的操作权限，ctx为请求上下文，携带传输层认证后的调用者信息
+type PermissionChecker interface {
+	ValidateUserPermission(ctx context.Context, userID uuid.UUID, resource string, action string) (bool, error)
+}
+
+const (
+	// defaultListChangedDebounce 默认list_changed通知合并窗口
+	defaultListChangedDebounce = 200 * time.Millisecond
+	// defaultListPageSize 默认列表每页条数
+	defaultListPageSize = 100
+)
+
+// NewServer 创建MCP服务端，工具、资源与提示模板通过RegisterModule或各注册器添加
+func NewServer(config ServerConfig) *Server {
+	s := &Server{
+		config:              config,
+		methods:             make(map[string]Handler),
+		completers:          make(map[string]*ArgumentCompleter),
+		toolRegistry:        NewToolRegistry(),
+		resourceRegistry:    NewResourceRegistry(),
+		promptRegistry:      NewPromptRegistry(),
+		subscriptionManager: NewSubscriptionManager(),
+		sessionManager:      NewSessionManager(config.SessionIdleTimeout),
+		cursors:             config.Cursors,
+		limiter:             newToolLimiter(),
+		middleware:          []Middleware{LogRequests(), RecoverPanics()},
+	}
+	if s.cursors == nil {
+		s.cursors = NewCursorCodec("")
+	}
+
+	s.registerDefaultMethods()
+
+	// 注册器变更以list_changed通知已初始化的会话，启动阶段没有会话时通知为空操作
+	debounce := config.ListChangedDebounce
+	if debounce <= 0 {
+		debounce = defaultListChangedDebounce
+	}
+	s.toolRegistry.OnChange(newDebouncer(debounce, func() {
+		s.broadcastListChanged(types.MCPMethodToolsChanged)
+	}).Trigger)
+	s.resourceRegistry.OnChange(newDebouncer(debounce, func() {
+		s.broadcastListChanged(types.MCPMethodResourcesChanged)
+	}).Trigger)
+	s.promptRegistry.OnChange(newDebouncer(debounce, func() {
+		s.broadcastListChanged(types.MCPMethodPromptsChanged)
+	}).Trigger)
+
+	return s
+}
+
+// registerDefaultMethods 注册MCP规范定义的方法
+func (s *Server) registerDefaultMethods() {
+	s.methods[types.MCPMethodInitialize] = s.handleInitialize
+	s.methods[types.MCPMethodInitialized] = s.handleInitialized
+	s.methods[types.MCPMethodNotificationsInitialized] = s.handleInitialized
+	s.methods[types.MCPMethodToolsList] = s.handleToolsList
+	s.methods[types.MCPMethodToolsCall] = s.handleToolsCall
+	s.methods[types.MCPMethodResourcesList] = s.handleResourcesList
+	s.methods[types.MCPMethodResourcesRead] = s.handleResourcesRead
+	s.methods[types.MCPMethodResourcesTemplatesList] = s.handleResourcesTemplatesList
+	s.methods[types.MCPMethodResourcesSubscribe] = s.handleResourcesSubscribe
+	s.methods[types.MCPMethodResourcesUnsubscribe] = s.handleResourcesUnsubscribe
+	s.methods[types.MCPMethodPromptsList] = s.handlePromptsList
+	s.methods[types.MCPMethodPromptsGet] = s.handlePromptsGet
+	s.methods[types.MCPMethodCompletionComplete] = s.handleCompletionComplete
+	s.methods[types.MCPMethodLoggingSetLevel] = s.handleLoggingSetLevel
+	s.methods[types.MCPMethodCancelled] = s.handleCancelled
+	s.methods[types.MCPMethodCancel] = s.handleCancelled
+	s.methods[types.MCPMethodPing] = s.handlePing
+}
+
+// Handle 注册或替换JSON-RPC方法的处理器，用于扩展MCP规范之外的方法
+func (s *Server) Handle(method string, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.methods[method] = handler
+}
+
+// Tools 获取工具注册器
+func (s *Server) Tools() *ToolRegistry {
+	return s.toolRegistry
+}
+
+// Resources 获取资源注册器
+func (s *Server) Resources() *ResourceRegistry {
+	return s.resourceRegistry
+}
+
+// Prompts 获取提示模板注册器
+func (s *Server) Prompts() *PromptRegistry {
+	return s.promptRegistry
+}
+
+// Sessions 获取会话管理器
+func (s *Server) Sessions() *SessionManager {
+	return s.sessionManager
+}
+
+// Close 关闭服务端持有的所有会话并停止后台清理，在传输层停止接收请求后调用
+func (s *Server) Close() {
+	s.sessionManager.Close()
+}
+
+// NotifyResourceUpdated 通知订阅者资源已更新
+func (s *Server) NotifyResourceUpdated(uri string) {
+	s.subscriptionManager.NotifyResourceUpdate(uri, &types.MCPNotification{
+		MCPMessage: types.MCPMessage{
+			JSONRPC: "2.0",
+		},
+		Method: types.MCPMethodResourcesUpdated,
+		Params: types.ResourcesUpdatedParams{
+			URI: uri,
+		},
+	})
+}
+
+// HandleRequest 处理MCP请求，请求依次经过方法级拦截器后分发
+func (s *Server) HandleRequest(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	response, err := s.chain(s.handle)(ctx, request)
+	if errResponse, ok := rpcErrorResponse(request.ID, err); ok {
+		return errResponse, nil
+	}
+	return response, err
+}
+
+// handle 校验会话状态并分发请求，位于拦截器链最内层
+func (s *Server) handle(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	// 有状态传输在初始化完成前只允许initialize和ping
+	if err := checkSessionState(ctx, request.Method); err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidRequest, err.Error())
+	}
+
+	// 登记会话内处理中的请求，使其可被notifications/cancelled取消
+	if session := SessionFromContext(ctx); session != nil && request.ID != nil && request.Method != types.MCPMethodInitialize {
+		var done func()
+		ctx, done = session.TrackRequest(ctx, request.ID)
+		defer done()
+	}
+
+	response, err := s.dispatch(ctx, request)
+
+	// 已被客户端取消的请求不再返回响应
+	if errors.Is(context.Cause(ctx), ErrRequestCancelled) {
+		logger.Info("MCP request cancelled by client",
+			logger.Any("method", request.Method),
+			logger.Any("request_id", request.ID))
+		return nil, nil
+	}
+
+	return response, err
+}
+
+// dispatch 按方法名分发请求
+func (s *Server) dispatch(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	s.mu.RLock()
+	handler, ok := s.methods[request.Method]
+	s.mu.RUnlock()
+
+	if !ok {
+		return createErrorResponse(request.ID, types.MCPMethodNotFound, "Method not found")
+	}
+	return handler(ctx, request)
+}
+
+// handleInitialize 处理初始化请求
+// 协商协议版本并在会话中记录客户端信息与能力
+func (s *Server) handleInitialize(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	initReq := &types.InitializeRequest{}
+	if err := parseParams(request.Params, initReq); err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	protocolVersion := types.NegotiateProtocolVersion(initReq.ProtocolVersion)
+	if session := SessionFromContext(ctx); session != nil {
+		if err := session.Initialize(protocolVersion, initReq.ClientInfo, initReq.Capabilities); err != nil {
+			return createErrorResponse(request.ID, types.MCPInvalidRequest, err.Error())
+		}
+
+		logger.Info("MCP session initialized",
+			logger.Any("session_id", session.ID),
+			logger.Any("client", initReq.ClientInfo.Name),
+			logger.Any("requested_version", initReq.ProtocolVersion),
+			logger.Any("protocol_version", protocolVersion))
+	}
+
+	response := &types.InitializeResponse{
+		ProtocolVersion: protocolVersion,
+		Capabilities: types.ServerCapabilities{
+			Tools: &types.ServerToolsCapability{
+				ListChanged: true,
+			},
+			Resources: &types.ServerResourcesCapability{
+				ListChanged: true,
+				Subscribe:   true,
+			},
+			Prompts: &types.ServerPromptsCapability{
+				ListChanged: true,
+			},
+			Logging:     &types.ServerLoggingCapability{},
+			Completions: &types.ServerCompletionsCapability{},
+		},
+		ServerInfo:   s.config.ServerInfo,
+		Instructions: s.config.Instructions,
+	}
+
+	return createSuccessResponse(request.ID, response)
+}
+
+// handleInitialized 处理客户端initialized通知，会话进入就绪状态
+func (s *Server) handleInitialized(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	if session := SessionFromContext(ctx); session != nil {
+		if err := session.MarkInitialized(); err != nil {
+			return createErrorResponse(request.ID, types.MCPInvalidRequest, err.Error())
+		}
+	}
+
+	// 通知不返回响应
+	if request.ID == nil {
+		return nil, nil
+	}
+	return createSuccessResponse(request.ID, map[string]interface{}{})
+}
+
+// handleToolsList 处理工具列表请求
+func (s *Server) handleToolsList(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	listReq := &types.ToolsListRequest{}
+	if err := parseParams(request.Params, listReq); err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	tools := s.toolRegistry.ListTools()
+	toolDefs := make([]types.Tool, 0, len(tools))
+
+	for _, tool := range tools {
+		toolDefs = append(toolDefs, types.Tool{
+			Name:         tool.Name,
+			Description:  tool.Description,
+			InputSchema:  tool.InputSchema,
+			OutputSchema: tool.OutputSchema,
+			Annotations:  tool.Annotations,
+		})
+	}
+
+	cursor, limit := s.paginationParams(listReq.PaginatedRequest)
+	page, nextCursor, err := paginateByKey(s.cursors, types.MCPMethodToolsList, toolDefs, func(tool types.Tool) string {
+		return tool.Name
+	}, cursor, limit)
+	if err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	response := &types.ToolsListResponse{
+		Tools:      page,
+		NextCursor: nextCursor,
+	}
+
+	return createSuccessResponse(request.ID, response)
+}
+
+// paginationParams 解析列表分页参数，limit缺省或超出上限时取每页条数上限
+func (s *Server) paginationParams(req types.PaginatedRequest) (string, int) {
+	pageSize := s.config.ListPageSize
+	if pageSize <= 0 {
+		pageSize = defaultListPageSize
+	}
+
+	limit := pageSize
+	if req.Limit != nil && *req.Limit > 0 && *req.Limit < pageSize {
+		limit = *req.Limit
+	}
+
+	var cursor string
+	if req.Cursor != nil {
+		cursor = *req.Cursor
+	}
+	return cursor, limit
+}
+
+// handleToolsCall 处理工具调用请求
+func (s *Server) handleToolsCall(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	callReq := &types.ToolsCallRequest{}
+	if err := parseParams(request.Params, callReq); err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	tool := s.toolRegistry.GetTool(callReq.Name)
+	if tool == nil {
+		return createErrorResponse(request.ID, types.MCPMethodNotFound, "Tool not found")
+	}
+
+	userID := UserIDFromContext(ctx)
+	if err := s.checkToolPermission(ctx, userID, tool); err != nil {
+		if errors.Is(err, ErrToolPermissionDenied) {
+			return createErrorResponse(request.ID, types.MCPPermissionDenied, err.Error())
+		}
+		return createErrorResponse(request.ID, types.MCPInternalError, err.Error())
+	}
+
+	// 缺少可征询的必填参数时先向用户询问，用户拒绝或取消时以工具错误结果返回
+	arguments, err := s.elicitMissingArguments(ctx, tool, callReq.Arguments)
+	var toolErr *ToolError
+	var panicErr *ToolPanicError
+	var rpcErr *RPCError
+	if errors.As(err, &toolErr) {
+		return createSuccessResponse(request.ID, toolErr.Result())
+	}
+	if err != nil {
+		return createErrorResponse(request.ID, types.MCPInternalError, err.Error())
+	}
+
+	// 按InputSchema注入默认值并校验参数，不合法的参数不会到达处理器
+	arguments, err = validateToolArguments(tool.InputSchema, arguments)
+	if err != nil {
+		var schemaErrs SchemaErrors
+		if errors.As(err, &schemaErrs) {
+			return createErrorResponseWithData(request.ID, types.MCPInvalidParams,
+				fmt.Sprintf("%v: %v", ErrInvalidToolArguments, schemaErrs), schemaErrs)
+		}
+		return createErrorResponse(request.ID, types.MCPInternalError, err.Error())
+	}
+
+	toolContext := &types.ToolContext{
+		Context:    ctx,
+		UserID:     userID,
+		SessionID:  getSessionIDFromContext(ctx),
+		RequestID:  getRequestID(request.ID),
+		StartTime:  time.Now(),
+		Parameters: map[string]interface{}{},
+	}
+
+	toolContext.OnLog = newClientLogger(ctx, "tool/"+tool.Name)
+
+	// 客户端请求进度通知时，工具可通过ToolContext.ReportProgress报告进度
+	if callReq.Meta != nil && callReq.Meta.ProgressToken != nil {
+		toolContext.OnProgress = newProgressReporter(ctx, callReq.Meta.ProgressToken)
+	}
+
+	execute := func(call *ToolCall) (*types.ToolsCallResponse, error) {
+		return s.executeTool(call.Context, call.Tool, call.Arguments)
+	}
+	result, err := s.chainTool(execute)(&ToolCall{
+		Tool:      tool,
+		Arguments: arguments,
+		Context:   toolContext,
+	})
+	switch {
+	case errors.Is(err, ErrToolTimeout):
+		logger.Warn("Tool execution timed out",
+			logger.Any("tool", tool.Name),
+			logger.Any("timeout", s.toolTimeout(tool)))
+		result, err = toolTimeoutResult(tool, s.toolTimeout(tool)), nil
+	case errors.Is(err, ErrToolBusy), errors.Is(err, ErrUserConcurrencyLimit):
+		logger.Warn("Tool call rejected by concurrency limit",
+			logger.Any("tool", tool.Name),
+			logger.Any("user_id", userID),
+			logger.Any("error", err))
+		result, err = toolBusyResult(tool, err), nil
+	case errors.As(err, &toolErr):
+		result, err = toolErr.Result(), nil
+	case errors.As(err, &panicErr):
+		result, err = panicErr.Result(), nil
+	case errors.Is(err, ErrInvalidToolArguments):
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	case errors.As(err, &rpcErr):
+		return createErrorResponseWithData(request.ID, rpcErr.Code, rpcErr.Message, rpcErr.Data)
+	}
+	if err != nil {
+		return createErrorResponse(request.ID, types.MCPInternalError, err.Error())
+	}
+
+	if err := finalizeToolResult(tool, result); err != nil {
+		logger.Error("Invalid tool output",
+			logger.Any("tool", tool.Name),
+			logger.Any("error", err))
+		return createErrorResponse(request.ID, types.MCPInternalError, err.Error())
+	}
+
+	return createSuccessResponse(request.ID, result)
+}
+
+// handleResourcesList 处理资源列表请求
+func (s *Server) handleResourcesList(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	listReq := &types.ResourcesListRequest{}
+	if err := parseParams(request.Params, listReq); err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	resources := s.resourceRegistry.ListResources()
+	resourceDefs := make([]types.Resource, 0, len(resources))
+
+	for _, resource := range resources {
+		resourceDefs = append(resourceDefs, types.Resource{
+			URI:         resource.URI,
+			Name:        resource.Name,
+			Description: resource.Description,
+			MimeType:    resource.MimeType,
+		})
+	}
+
+	cursor, limit := s.paginationParams(listReq.PaginatedRequest)
+	page, nextCursor, err := paginateByKey(s.cursors, types.MCPMethodResourcesList, resourceDefs, func(resource types.Resource) string {
+		return resource.URI
+	}, cursor, limit)
+	if err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	response := &types.ResourcesListResponse{
+		Resources:  page,
+		NextCursor: nextCursor,
+	}
+
+	return createSuccessResponse(request.ID, response)
+}
+
+// handleResourcesRead 处理资源读取请求
+func (s *Server) handleResourcesRead(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	readReq := &types.ResourcesReadRequest{}
+	if err := parseParams(request.Params, readReq); err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	handler, vars, ok := s.resourceRegistry.Resolve(readReq.URI)
+	if !ok {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, "Resource not found")
+	}
+
+	result, err := handler(ctx, readReq.URI, vars)
+	if err != nil {
+		var invalid *InvalidResourceError
+		if errors.As(err, &invalid) {
+			return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+		}
+		return createErrorResponse(request.ID, types.MCPInternalError, err.Error())
+	}
+
+	return createSuccessResponse(request.ID, result)
+}
+
+// handleResourcesTemplatesList 处理资源模板列表请求
+func (s *Server) handleResourcesTemplatesList(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	templates := s.resourceRegistry.ListTemplates()
+	templateDefs := make([]types.ResourceTemplate, 0, len(templates))
+
+	for _, template := range templates {
+		templateDefs = append(templateDefs, types.ResourceTemplate{
+			URITemplate: template.URITemplate,
+			Name:        template.Name,
+			Description: template.Description,
+			MimeType:    template.MimeType,
+		})
+	}
+
+	return createSuccessResponse(request.ID, &types.ResourcesTemplatesListResponse{
+		ResourceTemplates: templateDefs,
+	})
+}
+
+// handleResourcesSubscribe 处理资源订阅请求
+func (s *Server) handleResourcesSubscribe(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	subscribeReq := &types.ResourcesSubscribeRequest{}
+	if err := parseParams(request.Params, subscribeReq); err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	// 检查资源是否存在
+	if _, _, ok := s.resourceRegistry.Resolve(subscribeReq.URI); !ok {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, "Resource not found")
+	}
+
+	// 创建订阅，有会话时以会话ID作为客户端ID；重复订阅不再启动转发
+	clientID := getClientIDFromContext(ctx)
+	ch, created := s.subscriptionManager.Subscribe(subscribeReq.URI, clientID)
+
+	// 将资源更新通知转发到会话传输层
+	if session := SessionFromContext(ctx); session != nil && created {
+		session.OnClose(func(*Session) {
+			s.subscriptionManager.Unsubscribe(subscribeReq.URI, clientID)
+		})
+		go s.forwardNotifications(session, ch)
+	}
+
+	return createSuccessResponse(request.ID, map[string]string{"status": "subscribed"})
+}
+
+// handleResourcesUnsubscribe 处理资源取消订阅请求
+func (s *Server) handleResourcesUnsubscribe(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	unsubscribeReq := &types.ResourcesUnsubscribeRequest{}
+	if err := parseParams(request.Params, unsubscribeReq); err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	clientID := getClientIDFromContext(ctx)
+	s.subscriptionManager.Unsubscribe(unsubscribeReq.URI, clientID)
+
+	return createSuccessResponse(request.ID, map[string]string{"status": "unsubscribed"})
+}
+
+// handleCancelled 处理客户端取消通知，中止同一会话内对应的请求
+func (s *Server) handleCancelled(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	cancelParams := &types.CancelledParams{}
+	if err := parseParams(request.Params, cancelParams); err != nil {
+		return createErrorResponse(request.ID, types.MCPInvalidParams, err.Error())
+	}
+
+	session := SessionFromContext(ctx)
+	if session != nil && cancelParams.RequestID != nil {
+		// 请求可能已经完成，找不到时忽略
+		if session.CancelRequest(cancelParams.RequestID) {
+			logger.Info("MCP request cancelled",
+				logger.Any("session_id", session.ID),
+				logger.Any("request_id", cancelParams.RequestID),
+				logger.Any("reason", cancelParams.Reason))
+		}
+	}
+
+	// 通知不返回响应
+	if request.ID == nil {
+		return nil, nil
+	}
+	return createSuccessResponse(request.ID, map[string]interface{}{})
+}
+
+// handlePing 处理ping请求
+func (s *Server) handlePing(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+	return createSuccessResponse(request.ID, map[string]string{"status": "pong"})
+}
+
+// forwardNotifications 将订阅通道中的通知转发到会话，通道关闭或会话关闭后退出
+// 客户端消费过慢时转发会阻塞，订阅通道写满后多余的更新通知将被合并丢弃
+func (s *Server) forwardNotifications(session *Session, ch chan *types.MCPNotification) {
+	for notification := range ch {
+		if err := session.SendWait(context.Background(), notification); err != nil {
+			return
+		}
+	}
+}
+
+// broadcastListChanged 向所有已就绪的会话推送列表变更通知
+func (s *Server) broadcastListChanged(method string) {
+	notification := &types.MCPNotification{
+		MCPMessage: types.MCPMessage{
+			JSONRPC: "2.0",
+		},
+		Method: method,
+	}
+
+	for _, session := range s.sessionManager.List() {
+		if session.IsReady() {
+			session.Send(notification)
+		}
+	}
+}
+
+// 辅助方法
+func parseParams(params interface{}, target interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+func createSuccessResponse(id interface{}, result interface{}) (*types.MCPResponse, error) {
+	return &types.MCPResponse{
+		MCPMessage: types.MCPMessage{
+			JSONRPC: "2.0",
+			ID:      id,
+		},
+		Result: result,
+	}, nil
+}
+
+func createErrorResponse(id interface{}, code int, message string) (*types.MCPResponse, error) {
+	return &types.MCPResponse{
+		MCPMessage: types.MCPMessage{
+			JSONRPC: "2.0",
+			ID:      id,
+		},
+		Error: &types.MCPError{
+			Code:    code,
+			Message: message,
+		},
+	}, nil
+}
+
+// createErrorResponseWithData 创建携带附加数据的错误响应，如参数校验的逐字段错误
+func createErrorResponseWithData(id interface{}, code int, message string, data interface{}) (*types.MCPResponse, error) {
+	response, err := createErrorResponse(id, code, message)
+	response.Error.Data = data
+	return response, err
+}
+
+// checkSessionState 检查会话状态是否允许执行该方法，无会话的无状态请求不做限制
+func checkSessionState(ctx context.Context, method string) error {
+	session := SessionFromContext(ctx)
+	if session == nil {
+		return nil
+	}
+
+	switch method {
+	case types.MCPMethodInitialize, types.MCPMethodPing:
+		return nil
+	}
+
+	if session.State() == SessionStateNew {
+		return ErrSessionNotInitialized
+	}
+	return nil
+}
+
+// userIDContextKey 上下文中当前用户ID的键
+type userIDContextKey struct{}
+
+// ContextWithUserID 在上下文中设置当前请求的用户ID，由传输层在认证后调用
+func ContextWithUserID(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, userIDContextKey{}, userID)
+}
+
+// UserIDFromContext 获取当前请求的用户ID，匿名请求返回uuid.Nil
+func UserIDFromContext(ctx context.Context) uuid.UUID {
+	if userID, ok := ctx.Value(userIDContextKey{}).(uuid.UUID); ok {
+		return userID
+	}
+	return uuid.Nil
+}
+
+func getSessionIDFromContext(ctx context.Context) string {
+	if session := SessionFromContext(ctx); session != nil {
+		return session.ID
+	}
+	if sessionID, ok := ctx.Value("session_id").(string); ok {
+		return sessionID
+	}
+	return ""
+}
+
+func getClientIDFromContext(ctx context.Context) string {
+	if session := SessionFromContext(ctx); session != nil {
+		return session.ID
+	}
+	if clientID, ok := ctx.Value("client_id").(string); ok {
+		return clientID
+	}
+	return "default-client"
+}
+
+func getRequestID(id interface{}) string {
+	if id == nil {
+		return ""
+	}
+	switch v := id.(type) {
+	case string:
+		return v
+	case int, int64:
+		return fmt.Sprintf("%v", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// containsString 检查切片是否包含指定字符串
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
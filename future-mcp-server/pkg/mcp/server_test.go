@@ -0,0 +1,208 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/spf13/viper"
+)
+
+func TestMain(m *testing.M) {
+	viper.Set("log.level", "error")
+	if err := logger.Init(); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// handle 以给定方法和参数调用MCP服务，id为nil时作为通知发送
+func handle(t *testing.T, s *Server, ctx context.Context, id interface{}, method string, params interface{}) *types.MCPResponse {
+	t.Helper()
+
+	response, err := s.HandleRequest(ctx, &types.MCPRequest{
+		MCPMessage: types.MCPMessage{JSONRPC: "2.0", ID: id},
+		Method:     method,
+		Params:     params,
+	})
+	if err != nil {
+		t.Fatalf("%s: %v", method, err)
+	}
+	return response
+}
+
+// errorCode 返回响应的错误码，成功响应返回0
+func errorCode(response *types.MCPResponse) int {
+	if response == nil || response.Error == nil {
+		return 0
+	}
+	return response.Error.Code
+}
+
+// callTool 调用工具，返回JSON-RPC响应
+func callTool(t *testing.T, ctx context.Context, s *Server, name string, arguments interface{}) *types.MCPResponse {
+	t.Helper()
+	return handle(t, s, ctx, 1, types.MCPMethodToolsCall, types.ToolsCallRequest{Name: name, Arguments: arguments})
+}
+
+// toolResult 取出工具调用的结果，JSON-RPC错误时测试失败
+func toolResult(t *testing.T, response *types.MCPResponse) *types.ToolsCallResponse {
+	t.Helper()
+
+	if response.Error != nil {
+		t.Fatalf("unexpected error: %+v", response.Error)
+	}
+	result, ok := response.Result.(*types.ToolsCallResponse)
+	if !ok {
+		t.Fatalf("result type = %T", response.Result)
+	}
+	return result
+}
+
+func TestInitializeNegotiatesProtocolVersion(t *testing.T) {
+	tests := []struct {
+		requested string
+		want      string
+	}{
+		{types.MCPProtocolVersion20241105, types.MCPProtocolVersion20241105},
+		{types.MCPProtocolVersion20250326, types.MCPProtocolVersion20250326},
+		{types.MCPProtocolVersion20250618, types.MCPProtocolVersion20250618},
+		{"2023-01-01", types.MCPProtocolVersion},
+		{"", types.MCPProtocolVersion},
+	}
+
+	s := NewServer(ServerConfig{})
+	for _, tt := range tests {
+		t.Run(tt.requested, func(t *testing.T) {
+			session := s.Sessions().Create()
+			ctx := ContextWithSession(context.Background(), session)
+
+			response := handle(t, s, ctx, 1, types.MCPMethodInitialize, map[string]interface{}{
+				"protocolVersion": tt.requested,
+				"clientInfo":      map[string]string{"name": "test", "version": "1.0"},
+			})
+			if response.Error != nil {
+				t.Fatalf("initialize error: %+v", response.Error)
+			}
+			result, ok := response.Result.(*types.InitializeResponse)
+			if !ok {
+				t.Fatalf("result type = %T", response.Result)
+			}
+			if result.ProtocolVersion != tt.want {
+				t.Fatalf("protocolVersion = %q, want %q", result.ProtocolVersion, tt.want)
+			}
+			if session.ProtocolVersion() != tt.want {
+				t.Fatalf("session protocolVersion = %q, want %q", session.ProtocolVersion(), tt.want)
+			}
+			if session.ClientInfo().Name != "test" {
+				t.Fatalf("session clientInfo = %+v", session.ClientInfo())
+			}
+		})
+	}
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	session := s.Sessions().Create()
+	ctx := ContextWithSession(context.Background(), session)
+	initParams := map[string]interface{}{
+		"protocolVersion": types.MCPProtocolVersion,
+		"clientInfo":      map[string]string{"name": "test", "version": "1.0"},
+	}
+
+	steps := []struct {
+		name      string
+		id        interface{}
+		method    string
+		params    interface{}
+		wantCode  int
+		wantState SessionState
+	}{
+		{"tools before initialize", 1, types.MCPMethodToolsList, nil, types.MCPInvalidRequest, SessionStateNew},
+		{"initialized before initialize", 2, types.MCPMethodNotificationsInitialized, nil, types.MCPInvalidRequest, SessionStateNew},
+		{"ping before initialize", 3, types.MCPMethodPing, nil, 0, SessionStateNew},
+		{"initialize", 4, types.MCPMethodInitialize, initParams, 0, SessionStateInitializing},
+		{"initialize twice", 5, types.MCPMethodInitialize, initParams, types.MCPInvalidRequest, SessionStateInitializing},
+		{"initialized notification", nil, types.MCPMethodNotificationsInitialized, nil, 0, SessionStateReady},
+		{"tools after initialized", 6, types.MCPMethodToolsList, nil, 0, SessionStateReady},
+	}
+	for _, step := range steps {
+		response := handle(t, s, ctx, step.id, step.method, step.params)
+		if step.id == nil && response != nil {
+			t.Fatalf("%s: notification returned response %+v", step.name, response)
+		}
+		if code := errorCode(response); code != step.wantCode {
+			t.Fatalf("%s: error code = %d, want %d", step.name, code, step.wantCode)
+		}
+		if state := session.State(); state != step.wantState {
+			t.Fatalf("%s: state = %s, want %s", step.name, state, step.wantState)
+		}
+	}
+}
+
+func TestStatelessRequestSkipsLifecycle(t *testing.T) {
+	s := NewServer(ServerConfig{})
+
+	// 无会话的无状态请求不要求先初始化
+	if response := handle(t, s, context.Background(), 1, types.MCPMethodToolsList, nil); response.Error != nil {
+		t.Fatalf("tools/list error: %+v", response.Error)
+	}
+}
+
+// readySession 创建已完成初始化握手的会话，返回携带该会话的上下文
+func readySession(t *testing.T, s *Server) (*Session, context.Context) {
+	t.Helper()
+
+	session := s.Sessions().Create()
+	t.Cleanup(session.Close)
+	if err := session.Initialize(types.MCPProtocolVersion, types.ImplementationInfo{Name: "test", Version: "1.0"}, types.ClientCapabilities{}); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	if err := session.MarkInitialized(); err != nil {
+		t.Fatalf("MarkInitialized: %v", err)
+	}
+	return session, ContextWithSession(context.Background(), session)
+}
+
+func TestResourcesSubscribeIdempotent(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	s.Resources().RegisterResource(&types.ResourceDefinition{URI: "test://syllabus", Name: "syllabus"})
+	session, ctx := readySession(t, s)
+	closeHandlers := func() int {
+		session.mu.RLock()
+		defer session.mu.RUnlock()
+		return len(session.onClose)
+	}
+
+	subscribe := map[string]string{"uri": "test://syllabus"}
+	if response := handle(t, s, ctx, 1, types.MCPMethodResourcesSubscribe, subscribe); response.Error != nil {
+		t.Fatalf("subscribe: %+v", response.Error)
+	}
+	handlers := closeHandlers()
+
+	// 重复订阅不再注册关闭回调，也不启动新的转发
+	if response := handle(t, s, ctx, 2, types.MCPMethodResourcesSubscribe, subscribe); response.Error != nil {
+		t.Fatalf("repeated subscribe: %+v", response.Error)
+	}
+	if got := closeHandlers(); got != handlers {
+		t.Fatalf("onClose handlers = %d after repeated subscribe, want %d", got, handlers)
+	}
+
+	s.NotifyResourceUpdated("test://syllabus")
+	select {
+	case event := <-session.Events():
+		if notification, ok := event.Message.(*types.MCPNotification); !ok || notification.Method != types.MCPMethodResourcesUpdated {
+			t.Fatalf("event = %+v, want resources/updated", event.Message)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no resources/updated notification")
+	}
+	select {
+	case event := <-session.Events():
+		t.Fatalf("duplicate notification %+v", event.Message)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
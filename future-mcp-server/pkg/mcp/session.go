@@ -0,0 +1,628 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultSessionIdleTimeout 会话默认空闲超时
+	defaultSessionIdleTimeout = 30 * time.Minute
+	// sessionEventBufferSize 会话待推送事件缓冲区大小
+	sessionEventBufferSize = 100
+	// sessionHistorySize 会话保留的历史事件数量（用于断线续传）
+	sessionHistorySize = 256
+	// defaultSessionLogLevel 客户端未设置日志级别时转发的最低级别
+	defaultSessionLogLevel = types.LoggingLevelWarning
+)
+
+// 会话错误
+var (
+	// ErrSessionClosed 会话已关闭
+	ErrSessionClosed = errors.New("session closed")
+	// ErrSessionNotInitialized 会话尚未完成初始化
+	ErrSessionNotInitialized = errors.New("session not initialized")
+	// ErrSessionAlreadyInitialized 会话已初始化
+	ErrSessionAlreadyInitialized = errors.New("session already initialized")
+	// ErrRequestCancelled 客户端通过notifications/cancelled取消了请求
+	ErrRequestCancelled = errors.New("request cancelled by client")
+	// ErrSessionOverflow 不支持补发的会话事件缓冲区已满，客户端消费过慢
+	ErrSessionOverflow = errors.New("session event buffer overflow")
+)
+
+// SessionState 会话生命周期状态
+type SessionState int
+
+const (
+	// SessionStateNew 已创建，尚未收到initialize请求
+	SessionStateNew SessionState = iota
+	// SessionStateInitializing 已响应initialize，等待客户端的initialized通知
+	SessionStateInitializing
+	// SessionStateReady 客户端已确认初始化，服务端可以发起请求和通知
+	SessionStateReady
+)
+
+// String 状态名称
+func (st SessionState) String() string {
+	switch st {
+	case SessionStateNew:
+		return "new"
+	case SessionStateInitializing:
+		return "initializing"
+	case SessionStateReady:
+		return "ready"
+	default:
+		return "unknown"
+	}
+}
+
+// SessionEvent 会话事件（服务端推送给客户端的消息）
+type SessionEvent struct {
+	ID      int64
+	Message interface{}
+}
+
+// Session MCP会话
+type Session struct {
+	ID        string
+	CreatedAt time.Time
+
+	events      chan *SessionEvent
+	history     []*SessionEvent
+	nextEventID int64
+	lastActive  time.Time
+	pinned      bool
+	noReplay    bool
+	closed      bool
+	closeErr    error
+	done        chan struct{}
+
+	// 服务端发起、等待客户端响应的请求
+	pending       map[string]chan *types.MCPResponse
+	nextRequestID int64
+
+	// 客户端发起、正在处理的请求
+	inflight map[string]*inflightRequest
+
+	// 初始化协商结果
+	state              SessionState
+	protocolVersion    string
+	clientInfo         types.ImplementationInfo
+	clientCapabilities types.ClientCapabilities
+
+	// 客户端通过logging/setLevel设置的日志级别
+	logLevel types.LoggingLevel
+
+	onClose []func(*Session)
+	mu      sync.RWMutex
+}
+
+// newSession 创建会话
+func newSession(id string) *Session {
+	now := time.Now()
+	return &Session{
+		ID:         id,
+		CreatedAt:  now,
+		events:     make(chan *SessionEvent, sessionEventBufferSize),
+		history:    make([]*SessionEvent, 0, sessionHistorySize),
+		lastActive: now,
+		done:       make(chan struct{}),
+		pending:    make(map[string]chan *types.MCPResponse),
+		inflight:   make(map[string]*inflightRequest),
+	}
+}
+
+// inflightRequest 正在处理的客户端请求
+type inflightRequest struct {
+	cancel context.CancelCauseFunc
+}
+
+// TrackRequest 登记正在处理的客户端请求
+// 返回的上下文在客户端取消请求或会话关闭时被取消，请求处理结束后必须调用done
+func (s *Session) TrackRequest(ctx context.Context, id interface{}) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	key := getRequestID(id)
+	entry := &inflightRequest{cancel: cancel}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		cancel(ErrSessionClosed)
+		return ctx, func() {}
+	}
+	s.inflight[key] = entry
+	s.mu.Unlock()
+
+	done := func() {
+		s.mu.Lock()
+		// 同一ID可能被客户端复用，只移除自己登记的条目
+		if s.inflight[key] == entry {
+			delete(s.inflight, key)
+		}
+		s.mu.Unlock()
+		cancel(nil)
+	}
+	return ctx, done
+}
+
+// CancelRequest 取消正在处理的客户端请求，请求不存在或已结束时返回false
+func (s *Session) CancelRequest(id interface{}) bool {
+	key := getRequestID(id)
+
+	s.mu.Lock()
+	entry, exists := s.inflight[key]
+	delete(s.inflight, key)
+	s.mu.Unlock()
+
+	if !exists {
+		return false
+	}
+	entry.cancel(ErrRequestCancelled)
+	return true
+}
+
+// Initialize 记录initialize协商结果，会话只能初始化一次
+func (s *Session) Initialize(protocolVersion string, clientInfo types.ImplementationInfo, capabilities types.ClientCapabilities) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state != SessionStateNew {
+		return ErrSessionAlreadyInitialized
+	}
+
+	s.state = SessionStateInitializing
+	s.protocolVersion = protocolVersion
+	s.clientInfo = clientInfo
+	s.clientCapabilities = capabilities
+	return nil
+}
+
+// MarkInitialized 收到客户端initialized通知后进入就绪状态
+func (s *Session) MarkInitialized() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state == SessionStateNew {
+		return ErrSessionNotInitialized
+	}
+
+	s.state = SessionStateReady
+	return nil
+}
+
+// State 获取会话状态
+func (s *Session) State() SessionState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+// IsReady 客户端是否已确认初始化
+func (s *Session) IsReady() bool {
+	return s.State() == SessionStateReady
+}
+
+// ProtocolVersion 获取协商后的协议版本，未初始化时为空
+func (s *Session) ProtocolVersion() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.protocolVersion
+}
+
+// ClientInfo 获取客户端实现信息
+func (s *Session) ClientInfo() types.ImplementationInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.clientInfo
+}
+
+// ClientCapabilities 获取客户端声明的能力
+func (s *Session) ClientCapabilities() types.ClientCapabilities {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.clientCapabilities
+}
+
+// SetLogLevel 设置转发给客户端的最低日志级别
+func (s *Session) SetLogLevel(level types.LoggingLevel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logLevel = level
+}
+
+// LogLevel 获取转发给客户端的最低日志级别，未设置时为defaultSessionLogLevel
+func (s *Session) LogLevel() types.LoggingLevel {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.logLevel == "" {
+		return defaultSessionLogLevel
+	}
+	return s.logLevel
+}
+
+// Send 向会话推送消息，返回事件ID，会话已关闭时返回0
+// 消息同时写入历史缓冲区，客户端断线重连后可通过Last-Event-ID补发。
+// 缓冲区已满时，支持补发的会话仅保留历史中的事件；不支持补发的会话被关闭以断开慢速客户端，返回0
+func (s *Session) Send(message interface{}) int64 {
+	s.mu.Lock()
+	event := s.recordEvent(message)
+	if event == nil {
+		s.mu.Unlock()
+		return 0
+	}
+
+	select {
+	case s.events <- event:
+		s.mu.Unlock()
+		return event.ID
+	default:
+	}
+
+	noReplay := s.noReplay
+	s.mu.Unlock()
+
+	if noReplay {
+		logger.Warn("Session event buffer overflow, closing session",
+			logger.Any("session_id", s.ID))
+		s.closeWithError(ErrSessionOverflow)
+		return 0
+	}
+	return event.ID
+}
+
+// SendWait 向会话推送消息，缓冲区已满时阻塞等待传输层消费
+// 用于订阅通知转发等场景，使慢速客户端的压力反馈到上游通道
+func (s *Session) SendWait(ctx context.Context, message interface{}) error {
+	s.mu.Lock()
+	event := s.recordEvent(message)
+	s.mu.Unlock()
+
+	if event == nil {
+		return ErrSessionClosed
+	}
+
+	select {
+	case s.events <- event:
+		return nil
+	case <-s.done:
+		return ErrSessionClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// recordEvent 分配事件ID并写入历史缓冲区，调用方需持有锁
+func (s *Session) recordEvent(message interface{}) *SessionEvent {
+	if s.closed {
+		return nil
+	}
+
+	s.nextEventID++
+	event := &SessionEvent{ID: s.nextEventID, Message: message}
+
+	if len(s.history) >= sessionHistorySize {
+		s.history = s.history[1:]
+	}
+	s.history = append(s.history, event)
+
+	return event
+}
+
+// Request 向客户端发送请求并等待响应
+// 上下文取消时向客户端发送notifications/cancelled
+func (s *Session) Request(ctx context.Context, method string, params interface{}) (*types.MCPResponse, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, ErrSessionClosed
+	}
+	s.nextRequestID++
+	id := fmt.Sprintf("srv-%d", s.nextRequestID)
+	ch := make(chan *types.MCPResponse, 1)
+	s.pending[id] = ch
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+	}()
+
+	// 与当前客户端请求相关的服务端请求优先通过请求级通道发送（如Streamable HTTP中POST请求的SSE响应流）
+	message := &types.MCPRequest{
+		MCPMessage: types.MCPMessage{
+			JSONRPC: "2.0",
+			ID:      id,
+		},
+		Method: method,
+		Params: params,
+	}
+	if notifier, ok := ctx.Value(requestNotifierContextKey{}).(RequestNotifier); ok && notifier != nil {
+		notifier(message)
+	} else {
+		s.Send(message)
+	}
+
+	select {
+	case response := <-ch:
+		return response, nil
+	case <-s.done:
+		return nil, ErrSessionClosed
+	case <-ctx.Done():
+		s.Send(&types.MCPNotification{
+			MCPMessage: types.MCPMessage{
+				JSONRPC: "2.0",
+			},
+			Method: types.MCPMethodCancelled,
+			Params: types.CancelledParams{
+				RequestID: id,
+				Reason:    ctx.Err().Error(),
+			},
+		})
+		return nil, ctx.Err()
+	}
+}
+
+// DeliverResponse 将客户端响应交付给等待中的服务端请求，未匹配时返回false
+func (s *Session) DeliverResponse(response *types.MCPResponse) bool {
+	id := getRequestID(response.ID)
+
+	s.mu.Lock()
+	ch, exists := s.pending[id]
+	if exists {
+		delete(s.pending, id)
+	}
+	s.mu.Unlock()
+
+	if !exists {
+		return false
+	}
+
+	ch <- response
+	return true
+}
+
+// Events 获取待推送事件通道
+func (s *Session) Events() <-chan *SessionEvent {
+	return s.events
+}
+
+// EventsAfter 获取指定事件ID之后的历史事件
+func (s *Session) EventsAfter(lastEventID int64) []*SessionEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []*SessionEvent
+	for _, event := range s.history {
+		if event.ID > lastEventID {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Done 会话关闭通知通道
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Touch 刷新会话活跃时间
+func (s *Session) Touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastActive = time.Now()
+}
+
+// LastActive 获取会话最后活跃时间
+func (s *Session) LastActive() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastActive
+}
+
+// Pin 固定会话，使其不参与空闲清理（用于stdio等单连接传输）
+func (s *Session) Pin() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pinned = true
+}
+
+// DisableReplay 标记会话不支持断线补发（用于WebSocket、stdio等没有Last-Event-ID机制的传输）
+// 此类会话的事件缓冲区写满时Send会关闭会话，而不是静默丢弃事件
+func (s *Session) DisableReplay() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.noReplay = true
+}
+
+// isIdle 检查会话是否超过空闲时间
+func (s *Session) isIdle(now time.Time, timeout time.Duration) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return !s.pinned && now.Sub(s.lastActive) > timeout
+}
+
+// OnClose 注册会话关闭回调
+func (s *Session) OnClose(fn func(*Session)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onClose = append(s.onClose, fn)
+}
+
+// Close 关闭会话
+func (s *Session) Close() {
+	s.closeWithError(ErrSessionClosed)
+}
+
+// closeWithError 关闭会话并记录关闭原因
+func (s *Session) closeWithError(err error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.closeErr = err
+	close(s.done)
+	callbacks := s.onClose
+	s.onClose = nil
+	inflight := s.inflight
+	s.inflight = make(map[string]*inflightRequest)
+	s.mu.Unlock()
+
+	// 中止仍在处理的请求
+	for _, entry := range inflight {
+		entry.cancel(ErrSessionClosed)
+	}
+
+	for _, fn := range callbacks {
+		fn(s)
+	}
+}
+
+// IsClosed 检查会话是否已关闭
+func (s *Session) IsClosed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.closed
+}
+
+// Err 获取会话关闭原因，未关闭时返回nil，事件缓冲区溢出时为ErrSessionOverflow
+func (s *Session) Err() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.closeErr
+}
+
+// SessionManager 会话管理器
+type SessionManager struct {
+	sessions    map[string]*Session
+	idleTimeout time.Duration
+	mu          sync.RWMutex
+
+	// 关闭管理器时停止空闲清理goroutine，stopped在其退出后关闭
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSessionManager 创建会话管理器
+func NewSessionManager(idleTimeout time.Duration) *SessionManager {
+	if idleTimeout <= 0 {
+		idleTimeout = defaultSessionIdleTimeout
+	}
+
+	sm := &SessionManager{
+		sessions:    make(map[string]*Session),
+		idleTimeout: idleTimeout,
+		done:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+	}
+
+	// 启动清理空闲会话的goroutine
+	go sm.cleanupIdle()
+
+	return sm
+}
+
+// Create 创建新会话
+func (sm *SessionManager) Create() *Session {
+	session := newSession(uuid.New().String())
+
+	sm.mu.Lock()
+	sm.sessions[session.ID] = session
+	sm.mu.Unlock()
+
+	session.OnClose(func(closed *Session) {
+		sm.mu.Lock()
+		defer sm.mu.Unlock()
+		delete(sm.sessions, closed.ID)
+	})
+
+	return session
+}
+
+// Get 获取会话
+func (sm *SessionManager) Get(id string) *Session {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.sessions[id]
+}
+
+// Remove 关闭并移除会话
+func (sm *SessionManager) Remove(id string) bool {
+	session := sm.Get(id)
+	if session == nil {
+		return false
+	}
+	session.Close()
+	return true
+}
+
+// List 列出所有会话
+func (sm *SessionManager) List() []*Session {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	sessions := make([]*Session, 0, len(sm.sessions))
+	for _, session := range sm.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+// Close 停止空闲清理并关闭所有会话，可重复调用
+func (sm *SessionManager) Close() {
+	sm.closeOnce.Do(func() {
+		close(sm.done)
+		<-sm.stopped
+		for _, session := range sm.List() {
+			session.Close()
+		}
+	})
+}
+
+// cleanupIdle 清理空闲会话，直到管理器关闭
+func (sm *SessionManager) cleanupIdle() {
+	defer close(sm.stopped)
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sm.done:
+			return
+		case now := <-ticker.C:
+			for _, session := range sm.List() {
+				if session.isIdle(now, sm.idleTimeout) {
+					session.Close()
+				}
+			}
+		}
+	}
+}
+
+// sessionContextKey 会话上下文键
+type sessionContextKey struct{}
+
+// ContextWithSession 将会话写入上下文
+func ContextWithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, session)
+}
+
+// SessionFromContext 从上下文获取会话
+func SessionFromContext(ctx context.Context) *Session {
+	if ctx == nil {
+		return nil
+	}
+	session, _ := ctx.Value(sessionContextKey{}).(*Session)
+	return session
+}
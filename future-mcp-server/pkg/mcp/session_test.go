@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fillSession 写满会话事件缓冲区
+func fillSession(t *testing.T, session *Session) {
+	t.Helper()
+
+	for i := 0; i < sessionEventBufferSize; i++ {
+		if id := session.Send(i); id == 0 {
+			t.Fatalf("Send #%d returned 0 before the buffer was full", i)
+		}
+	}
+}
+
+func TestSessionSendOverflow(t *testing.T) {
+	t.Run("replayable session keeps history", func(t *testing.T) {
+		session := newSession("replay")
+		fillSession(t, session)
+
+		id := session.Send("overflow")
+		if id == 0 {
+			t.Fatal("Send on a full replayable session returned 0")
+		}
+		if session.IsClosed() {
+			t.Fatal("replayable session should stay open")
+		}
+		// 溢出的事件可通过Last-Event-ID补发
+		events := session.EventsAfter(id - 1)
+		if len(events) != 1 || events[0].Message != "overflow" {
+			t.Fatalf("EventsAfter = %v, want the overflowed event", events)
+		}
+	})
+
+	t.Run("session without replay is closed", func(t *testing.T) {
+		session := newSession("no-replay")
+		session.DisableReplay()
+		closed := make(chan struct{})
+		session.OnClose(func(*Session) { close(closed) })
+		fillSession(t, session)
+
+		if id := session.Send("overflow"); id != 0 {
+			t.Fatalf("Send on a full session without replay returned %d, want 0", id)
+		}
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			t.Fatal("session without replay was not closed on overflow")
+		}
+		if !errors.Is(session.Err(), ErrSessionOverflow) {
+			t.Fatalf("Err = %v, want ErrSessionOverflow", session.Err())
+		}
+		if id := session.Send("after close"); id != 0 {
+			t.Fatalf("Send after close returned %d, want 0", id)
+		}
+	})
+
+	t.Run("SendWait blocks instead of closing", func(t *testing.T) {
+		session := newSession("wait")
+		session.DisableReplay()
+		fillSession(t, session)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := session.SendWait(ctx, "overflow"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("SendWait = %v, want DeadlineExceeded", err)
+		}
+		if session.IsClosed() {
+			t.Fatal("SendWait should not close the session")
+		}
+
+		<-session.Events()
+		if err := session.SendWait(context.Background(), "overflow"); err != nil {
+			t.Fatalf("SendWait after the client consumed an event: %v", err)
+		}
+	})
+
+	t.Run("Close records reason", func(t *testing.T) {
+		session := newSession("close")
+		if session.Err() != nil {
+			t.Fatalf("Err before close = %v, want nil", session.Err())
+		}
+		session.Close()
+		if !errors.Is(session.Err(), ErrSessionClosed) {
+			t.Fatalf("Err = %v, want ErrSessionClosed", session.Err())
+		}
+	})
+}
+
+func TestSessionManagerClose(t *testing.T) {
+	sm := NewSessionManager(time.Minute)
+	sessions := []*Session{sm.Create(), sm.Create()}
+
+	// Close等待空闲清理goroutine退出后关闭全部会话，重复调用无副作用
+	sm.Close()
+	sm.Close()
+
+	select {
+	case <-sm.stopped:
+	default:
+		t.Fatal("idle cleanup goroutine still running after Close")
+	}
+	for _, session := range sessions {
+		if !session.IsClosed() {
+			t.Fatalf("session %s still open after Close", session.ID)
+		}
+	}
+	if remaining := sm.List(); len(remaining) != 0 {
+		t.Fatalf("List after Close = %d sessions, want 0", len(remaining))
+	}
+}
@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+// structuredToolResult 构建结构化工具结果，同时以JSON文本填充Content以兼容旧客户端
+func structuredToolResult(result interface{}) (*types.ToolsCallResponse, error) {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.ToolsCallResponse{
+		Content: []types.Content{
+			{
+				Type: "text",
+				Text: string(data),
+			},
+		},
+		StructuredContent: result,
+		IsError:           false,
+	}, nil
+}
+
+// finalizeToolResult 校验工具结果的内容块与outputSchema，并为只返回结构化结果的处理器补充文本内容
+// 工具执行失败（isError）的结果不做校验
+func finalizeToolResult(tool *types.ToolDefinition, result *types.ToolsCallResponse) error {
+	if result == nil || result.IsError {
+		return nil
+	}
+
+	if result.StructuredContent != nil && len(result.Content) == 0 {
+		data, err := json.Marshal(result.StructuredContent)
+		if err != nil {
+			return fmt.Errorf("failed to encode structured content: %w", err)
+		}
+		result.Content = []types.Content{TextContent(string(data))}
+	}
+
+	for i, content := range result.Content {
+		if err := validateContent(content); err != nil {
+			return fmt.Errorf("tool %s returned invalid content[%d]: %w", tool.Name, i, err)
+		}
+	}
+
+	if tool.OutputSchema == nil {
+		return nil
+	}
+	if result.StructuredContent == nil {
+		return fmt.Errorf("tool %s declares an output schema but returned no structured content", tool.Name)
+	}
+	if err := validateJSONSchema(tool.OutputSchema, result.StructuredContent); err != nil {
+		return fmt.Errorf("tool %s returned output that does not match its output schema: %w", tool.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+// scoreOutputSchema 测试用的输出Schema
+var scoreOutputSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []string{"score", "level"},
+	"properties": map[string]interface{}{
+		"score": map[string]interface{}{"type": "integer"},
+		"level": map[string]interface{}{"type": "string", "enum": []string{"low", "high"}},
+		"tags":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		"note":  map[string]interface{}{"type": []string{"string", "null"}},
+	},
+}
+
+func TestValidateJSONSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantErr string
+	}{
+		{"valid", map[string]interface{}{"score": 3, "level": "low", "tags": []string{"a"}, "note": nil}, ""},
+		{"struct value", struct {
+			Score int    `json:"score"`
+			Level string `json:"level"`
+		}{90, "high"}, ""},
+		{"missing required", map[string]interface{}{"score": 1}, "level: is required"},
+		{"integer rejects fraction", map[string]interface{}{"score": 1.5, "level": "low"}, "score: expected integer, got number"},
+		{"enum", map[string]interface{}{"score": 1, "level": "mid"}, `level: must be one of ["low","high"]`},
+		{"array items", map[string]interface{}{"score": 1, "level": "low", "tags": []interface{}{"a", 2}}, "tags[1]: expected string, got integer"},
+		{"type list", map[string]interface{}{"score": 1, "level": "low", "note": 5}, "note: expected string or null, got integer"},
+		{"root type", []string{"x"}, "expected object, got array"},
+		{"multiple errors", map[string]interface{}{"score": "x", "level": "mid"}, `level: must be one of ["low","high"]; score: expected integer, got string`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateJSONSchema(scoreOutputSchema, tt.value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStructuredToolResult(t *testing.T) {
+	tests := []struct {
+		name         string
+		outputSchema interface{}
+		result       *types.ToolsCallResponse
+		wantCode     int
+		wantText     string
+	}{
+		{
+			name:         "structured content mirrored as text",
+			outputSchema: scoreOutputSchema,
+			result:       &types.ToolsCallResponse{StructuredContent: map[string]interface{}{"score": 1, "level": "low"}},
+			wantText:     `{"level":"low","score":1}`,
+		},
+		{
+			name:         "output violates schema",
+			outputSchema: scoreOutputSchema,
+			result:       &types.ToolsCallResponse{StructuredContent: map[string]interface{}{"score": 1}},
+			wantCode:     types.MCPInternalError,
+		},
+		{
+			name:         "schema declared but no structured content",
+			outputSchema: scoreOutputSchema,
+			result:       &types.ToolsCallResponse{Content: []types.Content{{Type: "text", Text: "plain"}}},
+			wantCode:     types.MCPInternalError,
+		},
+		{
+			name:         "error results are not validated",
+			outputSchema: scoreOutputSchema,
+			result:       &types.ToolsCallResponse{Content: []types.Content{{Type: "text", Text: "failed"}}, IsError: true},
+			wantText:     "failed",
+		},
+		{
+			name:     "no schema",
+			result:   &types.ToolsCallResponse{Content: []types.Content{{Type: "text", Text: "plain"}}},
+			wantText: "plain",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(ServerConfig{})
+			s.Tools().RegisterTool(&types.ToolDefinition{
+				Name:         "score",
+				OutputSchema: tt.outputSchema,
+				Handler: func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+					return tt.result, nil
+				},
+			})
+
+			response := handle(t, s, context.Background(), 1, types.MCPMethodToolsCall, map[string]interface{}{"name": "score"})
+			if code := errorCode(response); code != tt.wantCode {
+				t.Fatalf("error code = %d, want %d (%+v)", code, tt.wantCode, response.Error)
+			}
+			if tt.wantCode != 0 {
+				return
+			}
+
+			result := response.Result.(*types.ToolsCallResponse)
+			if len(result.Content) != 1 || result.Content[0].Text != tt.wantText {
+				t.Fatalf("content = %+v, want text %q", result.Content, tt.wantText)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// toolCacheKeyPrefix 工具结果缓存键前缀
+const toolCacheKeyPrefix = "mcp:tool-result:"
+
+// ToolResultStore 工具结果缓存的存储，ttl单位为秒，通常由业务的缓存服务实现
+type ToolResultStore interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, ttl int) error
+}
+
+// ToolCachePolicy 工具结果的缓存策略，仅适用于只读且结果由参数决定的工具
+type ToolCachePolicy struct {
+	// TTL 结果的缓存时间
+	TTL time.Duration
+	// Tags 失效标签，Invalidate任一标签时该工具已缓存的结果全部失效
+	Tags []string
+	// Scope 调用方的权限范围，不同范围的调用不共享结果，返回空字符串时不缓存；
+	// 为nil时按用户隔离，匿名调用不缓存
+	Scope func(call *ToolCall) string
+}
+
+// ToolCache 工具结果缓存，键由工具名、规范化后的参数与调用方权限范围组成
+// 失效通过递增标签版本实现：版本号写入缓存键，旧版本的结果不再命中并随TTL过期。
+// 标签版本保存在进程内，多实例共用缓存存储时，各实例需各自接收变更事件
+type ToolCache struct {
+	store ToolResultStore
+
+	mu       sync.RWMutex
+	versions map[string]uint64
+}
+
+// cachedToolResult 缓存中保存的工具结果
+type cachedToolResult struct {
+	StoredAt time.Time                `json:"storedAt"`
+	Result   *types.ToolsCallResponse `json:"result"`
+}
+
+// NewToolCache 创建工具结果缓存
+func NewToolCache(store ToolResultStore) *ToolCache {
+	return &ToolCache{
+		store:    store,
+		versions: make(map[string]uint64),
+	}
+}
+
+// Invalidate 使带有指定标签的缓存结果失效
+func (c *ToolCache) Invalidate(tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, tag := range tags {
+		c.versions[tag]++
+	}
+}
+
+// Middleware 按策略缓存工具结果的拦截器，配合ForTools按工具启用
+// 命中时不执行工具，结果的_meta.cache标明是否命中与结果的缓存时长（秒）；工具错误与isError结果不缓存
+func (c *ToolCache) Middleware(policy ToolCachePolicy) ToolMiddleware {
+	ttl := int(math.Ceil(policy.TTL.Seconds()))
+	return func(next ToolInvoker) ToolInvoker {
+		return func(call *ToolCall) (*types.ToolsCallResponse, error) {
+			key, ok, err := c.key(call, policy)
+			if err != nil || !ok || ttl <= 0 {
+				return next(call)
+			}
+
+			if cached, ok := c.load(call.Context, key); ok {
+				age := time.Since(cached.StoredAt)
+				return withCacheMeta(cached.Result, true, age), nil
+			}
+
+			result, err := next(call)
+			if err != nil || result == nil || result.IsError {
+				return result, err
+			}
+
+			entry := cachedToolResult{StoredAt: time.Now(), Result: result}
+			if data, err := json.Marshal(entry); err == nil {
+				if err := c.store.Set(call.Context, key, string(data), ttl); err != nil {
+					logger.Warn("Failed to cache tool result",
+						logger.Any("tool", call.Tool.Name),
+						logger.Any("error", err))
+				}
+			}
+			return withCacheMeta(result, false, 0), nil
+		}
+	}
+}
+
+// key 缓存键：参数经JSON序列化规范化（对象键有序），与权限范围一同取摘要
+// 无法确定权限范围（如匿名调用）时返回false，调用不缓存
+func (c *ToolCache) key(call *ToolCall, policy ToolCachePolicy) (string, bool, error) {
+	var scope string
+	if policy.Scope != nil {
+		scope = policy.Scope(call)
+	} else if call.Context.UserID != uuid.Nil {
+		scope = call.Context.UserID.String()
+	}
+	if scope == "" {
+		return "", false, nil
+	}
+
+	arguments, err := json.Marshal(call.Arguments)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to canonicalize arguments: %w", err)
+	}
+
+	digest := sha256.New()
+	digest.Write([]byte(scope))
+	digest.Write([]byte{0})
+	digest.Write(arguments)
+
+	return toolCacheKeyPrefix + call.Tool.Name + ":" + c.version(policy.Tags) + ":" + hex.EncodeToString(digest.Sum(nil)), true, nil
+}
+
+// version 策略标签的当前版本，写入缓存键
+func (c *ToolCache) version(tags []string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	versions := make([]string, len(tags))
+	for i, tag := range tags {
+		versions[i] = fmt.Sprintf("%d", c.versions[tag])
+	}
+	return strings.Join(versions, ".")
+}
+
+// load 读取缓存结果，缓存未命中或内容无法解析时返回false
+func (c *ToolCache) load(ctx context.Context, key string) (*cachedToolResult, bool) {
+	value, err := c.store.Get(ctx, key)
+	if err != nil || value == "" {
+		return nil, false
+	}
+	var cached cachedToolResult
+	if err := json.Unmarshal([]byte(value), &cached); err != nil || cached.Result == nil {
+		return nil, false
+	}
+	return &cached, true
+}
+
+// withCacheMeta 在结果的_meta中标明缓存命中情况，不修改已缓存的结果
+func withCacheMeta(result *types.ToolsCallResponse, hit bool, age time.Duration) *types.ToolsCallResponse {
+	annotated := *result
+	annotated.Meta = make(map[string]interface{}, len(result.Meta)+1)
+	for key, value := range result.Meta {
+		annotated.Meta[key] = value
+	}
+	annotated.Meta["cache"] = map[string]interface{}{
+		"hit": hit,
+		"age": int(age.Seconds()),
+	}
+	return &annotated
+}
@@ -0,0 +1,207 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// memoryResultStore 测试用内存结果存储，不处理过期
+type memoryResultStore struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (m *memoryResultStore) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.values[key]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return value, nil
+}
+
+func (m *memoryResultStore) Set(ctx context.Context, key string, value interface{}, ttl int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value.(string)
+	return nil
+}
+
+// newCachedEchoServer 注册一个返回调用用户的工具并为其启用缓存，返回工具被执行的次数
+func newCachedEchoServer(policy ToolCachePolicy) (*Server, *ToolCache, *int) {
+	s := NewServer(ServerConfig{})
+	calls := new(int)
+	s.Tools().RegisterTool(&types.ToolDefinition{
+		Name:        "echo",
+		InputSchema: map[string]interface{}{"type": "object"},
+		Handler: func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+			*calls++
+			return &types.ToolsCallResponse{Content: []types.Content{TextContent(ctx.UserID.String())}}, nil
+		},
+	})
+
+	cache := NewToolCache(&memoryResultStore{values: make(map[string]string)})
+	s.UseTool(ForTools(ToolNames("echo"), cache.Middleware(policy)))
+	return s, cache, calls
+}
+
+// cacheHit 取出结果_meta中的缓存命中标记
+func cacheHit(t *testing.T, result *types.ToolsCallResponse) bool {
+	t.Helper()
+
+	meta, ok := result.Meta["cache"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("result has no cache meta: %+v", result.Meta)
+	}
+	return meta["hit"].(bool)
+}
+
+func TestToolCacheMiddleware(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	calls := 0
+	s.Tools().RegisterTool(&types.ToolDefinition{
+		Name:        "search",
+		InputSchema: map[string]interface{}{"type": "object"},
+		Handler: func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+			calls++
+			query, _ := args.(map[string]interface{})["query"].(string)
+			return &types.ToolsCallResponse{Content: []types.Content{TextContent(query)}, IsError: query == "fail"}, nil
+		},
+	})
+	cache := NewToolCache(&memoryResultStore{values: make(map[string]string)})
+	s.UseTool(ForTools(ToolNames("search"), cache.Middleware(ToolCachePolicy{TTL: time.Minute, Tags: []string{"materials"}})))
+	ctx := ContextWithUserID(context.Background(), uuid.New())
+
+	tests := []struct {
+		name       string
+		query      string
+		invalidate bool
+		wantHit    bool
+		wantCalls  int
+	}{
+		{"first call executes", "方程", false, false, 1},
+		{"same arguments hit", "方程", false, true, 1},
+		{"other arguments miss", "函数", false, false, 2},
+		{"invalidated tag misses", "方程", true, false, 3},
+		{"cached again after invalidation", "方程", false, true, 3},
+		{"error results are not cached", "fail", false, false, 4},
+		{"error results executed again", "fail", false, false, 5},
+	}
+	for _, tt := range tests {
+		if tt.invalidate {
+			cache.Invalidate("materials")
+		}
+		result := toolResult(t, callTool(t, ctx, s, "search", map[string]interface{}{"query": tt.query}))
+		if tt.query == "fail" {
+			if _, cached := result.Meta["cache"]; cached || calls != tt.wantCalls {
+				t.Fatalf("%s: meta = %+v, calls = %d, want %d", tt.name, result.Meta, calls, tt.wantCalls)
+			}
+			continue
+		}
+		if hit := cacheHit(t, result); hit != tt.wantHit || calls != tt.wantCalls {
+			t.Fatalf("%s: hit = %v, calls = %d, want %v, %d", tt.name, hit, calls, tt.wantHit, tt.wantCalls)
+		}
+		if result.Content[0].Text != tt.query {
+			t.Fatalf("%s: result = %+v", tt.name, result)
+		}
+	}
+}
+
+func TestToolCacheDisabledWithoutTTL(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	calls := 0
+	s.Tools().RegisterTool(&types.ToolDefinition{
+		Name:        "search",
+		InputSchema: map[string]interface{}{"type": "object"},
+		Handler: func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+			calls++
+			return &types.ToolsCallResponse{Content: []types.Content{TextContent("ok")}}, nil
+		},
+	})
+	cache := NewToolCache(&memoryResultStore{values: make(map[string]string)})
+	s.UseTool(cache.Middleware(ToolCachePolicy{}))
+	ctx := ContextWithUserID(context.Background(), uuid.New())
+
+	for i := 0; i < 2; i++ {
+		toolResult(t, callTool(t, ctx, s, "search", nil))
+	}
+	if calls != 2 {
+		t.Fatalf("calls = %d, want 2", calls)
+	}
+}
+
+func TestToolCache(t *testing.T) {
+	alice := ContextWithUserID(context.Background(), uuid.New())
+	bob := ContextWithUserID(context.Background(), uuid.New())
+	anonymous := context.Background()
+	args := map[string]interface{}{"query": "方程"}
+
+	type call struct {
+		ctx     context.Context
+		invalid bool // 调用前使materials标签失效
+		wantHit bool
+	}
+	tests := []struct {
+		name  string
+		scope func(call *ToolCall) string
+		calls []call
+	}{
+		{
+			name:  "same user hits",
+			calls: []call{{ctx: alice}, {ctx: alice, wantHit: true}},
+		},
+		{
+			name:  "users do not share entries",
+			calls: []call{{ctx: alice}, {ctx: bob}, {ctx: bob, wantHit: true}, {ctx: alice, wantHit: true}},
+		},
+		{
+			name:  "anonymous calls are not cached",
+			calls: []call{{ctx: anonymous}, {ctx: anonymous}},
+		},
+		{
+			name:  "invalidate drops entries",
+			calls: []call{{ctx: alice}, {ctx: alice, invalid: true}, {ctx: alice, wantHit: true}},
+		},
+		{
+			name:  "explicit scope is shared",
+			scope: func(call *ToolCall) string { return "public" },
+			calls: []call{{ctx: alice}, {ctx: bob, wantHit: true}, {ctx: anonymous, wantHit: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, cache, executions := newCachedEchoServer(ToolCachePolicy{TTL: time.Minute, Tags: []string{"materials"}, Scope: tt.scope})
+
+			wantExecutions := 0
+			for i, c := range tt.calls {
+				if c.invalid {
+					cache.Invalidate("materials")
+				}
+				result := toolResult(t, callTool(t, c.ctx, s, "echo", args))
+				if !c.wantHit {
+					wantExecutions++
+				}
+
+				meta, _ := result.Meta["cache"].(map[string]interface{})
+				if hit := meta["hit"] == true; hit != c.wantHit {
+					t.Fatalf("call %d: hit = %v, want %v", i, hit, c.wantHit)
+				}
+				// 按用户隔离时命中的结果必须是该用户自己的
+				if tt.scope == nil && result.Content[0].Text != UserIDFromContext(c.ctx).String() {
+					t.Fatalf("call %d: got result of user %s", i, result.Content[0].Text)
+				}
+			}
+			if *executions != wantExecutions {
+				t.Fatalf("executions = %d, want %d", *executions, wantExecutions)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/future-mcp/future-mcp-server/pkg/logger"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrToolPermissionDenied 当前用户不具备调用工具所需的权限
+	ErrToolPermissionDenied = errors.New("permission denied")
+	// ErrToolTimeout 工具执行超过其超时时间
+	ErrToolTimeout = errors.New("tool execution timed out")
+)
+
+// defaultToolTimeout 未单独设置超时的工具的默认执行超时
+const defaultToolTimeout = 30 * time.Second
+
+// ToolPanicError 工具处理器发生panic，CorrelationID用于关联服务端日志中的堆栈
+type ToolPanicError struct {
+	Tool          string
+	CorrelationID string
+	Value         interface{}
+}
+
+// Error 实现error接口
+func (e *ToolPanicError) Error() string {
+	return fmt.Sprintf("tool %s panicked (correlation id %s): %v", e.Tool, e.CorrelationID, e.Value)
+}
+
+// Result 转换为工具错误结果，不向客户端暴露panic内容，仅返回关联ID
+func (e *ToolPanicError) Result() *types.ToolsCallResponse {
+	return &types.ToolsCallResponse{
+		Content: []types.Content{
+			TextContent(fmt.Sprintf("工具 %s 执行异常，请稍后重试；如问题持续，请提供关联ID %s", e.Tool, e.CorrelationID)),
+		},
+		Meta:    map[string]interface{}{"correlationId": e.CorrelationID},
+		IsError: true,
+	}
+}
+
+// toolTimeout 工具的执行超时，配置中按工具名设置的超时优先于工具定义
+func (s *Server) toolTimeout(tool *types.ToolDefinition) time.Duration {
+	if timeout := s.config.ToolTimeouts[tool.Name]; timeout > 0 {
+		return timeout
+	}
+	if tool.Timeout > 0 {
+		return tool.Timeout
+	}
+	if s.config.ToolTimeout > 0 {
+		return s.config.ToolTimeout
+	}
+	return defaultToolTimeout
+}
+
+// checkToolPermission 校验当前用户是否具备调用工具所需的权限，工具未声明权限或未配置权限校验时不校验
+func (s *Server) checkToolPermission(ctx context.Context, userID uuid.UUID, tool *types.ToolDefinition) error {
+	if tool.Permission == "" || s.config.Permissions == nil {
+		return nil
+	}
+
+	resource, action, _ := strings.Cut(tool.Permission, ":")
+	allowed, err := s.config.Permissions.ValidateUserPermission(ctx, userID, resource, action)
+	if err != nil {
+		return fmt.Errorf("failed to check permission %s: %w", tool.Permission, err)
+	}
+	if !allowed {
+		return fmt.Errorf("%w: tool %s requires %s", ErrToolPermissionDenied, tool.Name, tool.Permission)
+	}
+	return nil
+}
+
+// executeTool 占用执行槽位后在工具超时时间内执行处理器
+// 超时后立即返回ErrToolTimeout并取消ToolContext，未响应取消的处理器在后台结束，其结果被丢弃，
+// 槽位在处理器实际结束后才释放，避免不响应取消的处理器无限堆积
+func (s *Server) executeTool(toolContext *types.ToolContext, tool *types.ToolDefinition, args interface{}) (*types.ToolsCallResponse, error) {
+	parent := toolContext.Context
+	release, err := s.acquireToolSlot(parent, toolContext.UserID, tool)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := s.toolTimeout(tool)
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+	toolContext.Context = ctx
+
+	type outcome struct {
+		result *types.ToolsCallResponse
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer release()
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- outcome{err: toolPanic(tool, toolContext, recovered)}
+			}
+		}()
+		result, err := tool.Handler(toolContext, args)
+		done <- outcome{result: result, err: err}
+	}()
+
+	var result outcome
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result.err = ctx.Err()
+	}
+
+	// 区分工具自身超时与客户端取消
+	if result.err != nil && parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w after %s", ErrToolTimeout, timeout)
+	}
+	return result.result, result.err
+}
+
+// toolPanic 记录处理器panic的堆栈并生成关联ID
+func toolPanic(tool *types.ToolDefinition, toolContext *types.ToolContext, recovered interface{}) *ToolPanicError {
+	panicErr := &ToolPanicError{
+		Tool:          tool.Name,
+		CorrelationID: uuid.New().String(),
+		Value:         recovered,
+	}
+	logger.Error("Tool handler panicked",
+		logger.Any("tool", tool.Name),
+		logger.Any("correlation_id", panicErr.CorrelationID),
+		logger.Any("request_id", toolContext.RequestID),
+		logger.Any("panic", fmt.Sprint(recovered)),
+		logger.Any("stack", string(debug.Stack())))
+	return panicErr
+}
+
+// toolTimeoutResult 将工具超时转换为工具错误结果，便于调用方（模型）调整参数后重试
+func toolTimeoutResult(tool *types.ToolDefinition, timeout time.Duration) *types.ToolsCallResponse {
+	return &types.ToolsCallResponse{
+		Content: []types.Content{
+			TextContent(fmt.Sprintf("工具 %s 执行超时（超过%s），请缩小请求范围后重试", tool.Name, timeout)),
+		},
+		IsError: true,
+	}
+}
@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// permissionChecker 按"资源:操作"返回固定授权结果的权限校验器
+type permissionChecker struct {
+	granted map[string]bool
+	err     error
+}
+
+func (u *permissionChecker) ValidateUserPermission(ctx context.Context, userID uuid.UUID, resource string, action string) (bool, error) {
+	if u.err != nil {
+		return false, u.err
+	}
+	return u.granted[resource+":"+action], nil
+}
+
+func TestToolPermission(t *testing.T) {
+	okHandler := func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+		return &types.ToolsCallResponse{Content: []types.Content{TextContent("ok")}}, nil
+	}
+
+	tests := []struct {
+		name        string
+		permission  string
+		permissions PermissionChecker
+		wantCode    int
+	}{
+		{"no permission required", "", &permissionChecker{}, 0},
+		{"no checker configured", "content:generate", nil, 0},
+		{"granted", "content:generate", &permissionChecker{granted: map[string]bool{"content:generate": true}}, 0},
+		{"denied", "content:generate", &permissionChecker{granted: map[string]bool{"materials:read": true}}, types.MCPPermissionDenied},
+		{"lookup failure", "content:generate", &permissionChecker{err: errors.New("db down")}, types.MCPInternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(ServerConfig{Permissions: tt.permissions})
+			s.Tools().RegisterTool(&types.ToolDefinition{Name: "guarded", Permission: tt.permission, Handler: okHandler})
+
+			response := handle(t, s, context.Background(), 1, types.MCPMethodToolsCall, map[string]interface{}{"name": "guarded"})
+			if code := errorCode(response); code != tt.wantCode {
+				t.Fatalf("error code = %d, want %d (%+v)", code, tt.wantCode, response.Error)
+			}
+		})
+	}
+}
+
+func TestToolTimeout(t *testing.T) {
+	tests := []struct {
+		name          string
+		configTimeout time.Duration
+		toolTimeout   time.Duration
+		want          time.Duration
+	}{
+		{"default", 0, 0, defaultToolTimeout},
+		{"service default", time.Second, 0, time.Second},
+		{"tool override", time.Second, time.Minute, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(ServerConfig{ToolTimeout: tt.configTimeout})
+			if got := s.toolTimeout(&types.ToolDefinition{Timeout: tt.toolTimeout}); got != tt.want {
+				t.Fatalf("toolTimeout = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolTimeoutReturnsToolError(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	cancelled := make(chan struct{})
+	s.Tools().RegisterTool(&types.ToolDefinition{
+		Name:    "slow",
+		Timeout: 20 * time.Millisecond,
+		Handler: func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+			<-ctx.Context.Done()
+			close(cancelled)
+			return nil, ctx.Context.Err()
+		},
+	})
+
+	response := handle(t, s, context.Background(), 1, types.MCPMethodToolsCall, map[string]interface{}{"name": "slow"})
+	if response.Error != nil {
+		t.Fatalf("tools/call error: %+v", response.Error)
+	}
+	result := response.Result.(*types.ToolsCallResponse)
+	if !result.IsError || len(result.Content) != 1 || !strings.Contains(result.Content[0].Text, "执行超时") {
+		t.Fatalf("result = %+v, want timeout tool error", result)
+	}
+
+	// 超时后处理器的上下文被取消
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("handler context was not cancelled after timeout")
+	}
+}
+
+func TestExecuteToolParentCancelled(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	tool := &types.ToolDefinition{
+		Name:    "blocking",
+		Timeout: time.Minute,
+		Handler: func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+			<-ctx.Context.Done()
+			return nil, ctx.Context.Err()
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := s.executeTool(&types.ToolContext{Context: ctx}, tool, nil)
+	if errors.Is(err, ErrToolTimeout) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
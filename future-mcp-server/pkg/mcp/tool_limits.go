@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrToolBusy 工具的执行槽位已满，排队等待超时
+	ErrToolBusy = errors.New("tool is busy")
+	// ErrUserConcurrencyLimit 用户同时执行中的工具调用数已达配额上限
+	ErrUserConcurrencyLimit = errors.New("concurrent tool call limit exceeded")
+)
+
+const (
+	// defaultToolConcurrency 未单独设置并发上限的工具同时执行的默认调用数
+	defaultToolConcurrency = 16
+	// defaultToolQueueTimeout 工具槽位已满时默认的排队等待时间
+	defaultToolQueueTimeout = 5 * time.Second
+)
+
+// UserConcurrencyLimiter 提供用户同时执行工具调用的数量上限，通常按用户角色或配额实现
+// 匿名调用以uuid.Nil查询（通常返回访客配额），ctx为请求上下文，返回0或负数表示不限制
+type UserConcurrencyLimiter interface {
+	UserConcurrentLimit(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+// toolLimiter 按工具与按用户限制同时执行的工具调用数
+// 每个工具拥有独立的执行槽位，耗时的生成类工具占满自身槽位时不影响检索类工具
+type toolLimiter struct {
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+	users map[uuid.UUID]int
+}
+
+func newToolLimiter() *toolLimiter {
+	return &toolLimiter{
+		slots: make(map[string]chan struct{}),
+		users: make(map[uuid.UUID]int),
+	}
+}
+
+// toolConcurrency 工具同时执行的调用数上限
+func (s *Server) toolConcurrency(tool *types.ToolDefinition) int {
+	if limit := s.config.ToolConcurrencyLimits[tool.Name]; limit > 0 {
+		return limit
+	}
+	if tool.MaxConcurrency > 0 {
+		return tool.MaxConcurrency
+	}
+	if s.config.ToolConcurrency > 0 {
+		return s.config.ToolConcurrency
+	}
+	return defaultToolConcurrency
+}
+
+// toolQueueTimeout 工具槽位已满时的排队等待时间
+func (s *Server) toolQueueTimeout() time.Duration {
+	if s.config.ToolQueueTimeout > 0 {
+		return s.config.ToolQueueTimeout
+	}
+	return defaultToolQueueTimeout
+}
+
+// acquireToolSlot 占用用户与工具的执行槽位，返回的release须在处理器实际结束后调用
+// 用户已达并发上限时立即拒绝，工具槽位已满时排队等待，超过排队时间或请求被取消时放弃
+func (s *Server) acquireToolSlot(ctx context.Context, userID uuid.UUID, tool *types.ToolDefinition) (func(), error) {
+	releaseUser, err := s.acquireUserSlot(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := s.limiter.toolSlots(tool.Name, s.toolConcurrency(tool))
+	timer := time.NewTimer(s.toolQueueTimeout())
+	defer timer.Stop()
+
+	select {
+	case slots <- struct{}{}:
+		return func() {
+			<-slots
+			releaseUser()
+		}, nil
+	case <-timer.C:
+		releaseUser()
+		return nil, fmt.Errorf("%w: %s", ErrToolBusy, tool.Name)
+	case <-ctx.Done():
+		releaseUser()
+		return nil, context.Cause(ctx)
+	}
+}
+
+// acquireUserSlot 按用户配额占用一个并发名额，未配置配额时不限制
+// 匿名调用共用uuid.Nil名下的名额，避免绕过身份即可无限并发
+func (s *Server) acquireUserSlot(ctx context.Context, userID uuid.UUID) (func(), error) {
+	if s.config.UserLimits == nil {
+		return func() {}, nil
+	}
+
+	limit, err := s.config.UserLimits.UserConcurrentLimit(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get concurrent limit: %w", err)
+	}
+	if limit <= 0 {
+		return func() {}, nil
+	}
+
+	l := s.limiter
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.users[userID] >= limit {
+		return nil, fmt.Errorf("%w (%d)", ErrUserConcurrencyLimit, limit)
+	}
+	l.users[userID]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if l.users[userID]--; l.users[userID] <= 0 {
+				delete(l.users, userID)
+			}
+		})
+	}, nil
+}
+
+// toolSlots 工具的执行槽位，首次调用时按上限创建
+func (l *toolLimiter) toolSlots(name string, limit int) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	slots, ok := l.slots[name]
+	if !ok {
+		slots = make(chan struct{}, limit)
+		l.slots[name] = slots
+	}
+	return slots
+}
+
+// toolBusyResult 将并发受限转换为工具错误结果，调用方（模型）可稍后重试
+func toolBusyResult(tool *types.ToolDefinition, err error) *types.ToolsCallResponse {
+	message := fmt.Sprintf("工具 %s 当前繁忙，请稍后重试", tool.Name)
+	if errors.Is(err, ErrUserConcurrencyLimit) {
+		message = fmt.Sprintf("同时执行的工具调用过多，请等待之前的调用完成后重试（%s）", err)
+	}
+	return &types.ToolsCallResponse{
+		Content: []types.Content{TextContent(message)},
+		IsError: true,
+	}
+}
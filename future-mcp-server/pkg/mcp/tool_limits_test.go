@@ -0,0 +1,198 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// fixedUserLimits 为所有用户返回同一并发上限
+type fixedUserLimits int
+
+func (l fixedUserLimits) UserConcurrentLimit(ctx context.Context, userID uuid.UUID) (int, error) {
+	return int(l), nil
+}
+
+// registerGatedTool 注册在release关闭前阻塞的工具，每次开始执行时向started发送信号
+func registerGatedTool(s *Server, name string) (started chan struct{}, release chan struct{}) {
+	started = make(chan struct{}, 4)
+	release = make(chan struct{})
+	s.Tools().RegisterTool(&types.ToolDefinition{
+		Name:        name,
+		InputSchema: map[string]interface{}{"type": "object"},
+		Handler: func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+			started <- struct{}{}
+			<-release
+			return &types.ToolsCallResponse{Content: []types.Content{TextContent("done")}}, nil
+		},
+	})
+	return started, release
+}
+
+func TestToolConcurrencyLimit(t *testing.T) {
+	s := NewServer(ServerConfig{
+		ToolConcurrencyLimits: map[string]int{"slow": 1},
+		ToolQueueTimeout:      20 * time.Millisecond,
+	})
+	started, release := registerGatedTool(s, "slow")
+
+	first := make(chan *types.MCPResponse, 1)
+	go func() {
+		response, _ := s.HandleRequest(context.Background(), &types.MCPRequest{
+			MCPMessage: types.MCPMessage{JSONRPC: "2.0", ID: 1},
+			Method:     types.MCPMethodToolsCall,
+			Params:     types.ToolsCallRequest{Name: "slow"},
+		})
+		first <- response
+	}()
+	<-started
+
+	// 槽位已满，排队超时后以工具错误结果拒绝
+	result := toolResult(t, callTool(t, context.Background(), s, "slow", nil))
+	if !result.IsError || !strings.Contains(result.Content[0].Text, "当前繁忙") {
+		t.Fatalf("queued call result = %+v, want busy tool error", result)
+	}
+
+	close(release)
+	if result := toolResult(t, <-first); result.IsError {
+		t.Fatalf("first call should succeed, got %+v", result)
+	}
+}
+
+func TestToolConcurrency(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ServerConfig
+		tool   *types.ToolDefinition
+		want   int
+	}{
+		{"default", ServerConfig{}, &types.ToolDefinition{Name: "a"}, defaultToolConcurrency},
+		{"server default", ServerConfig{ToolConcurrency: 4}, &types.ToolDefinition{Name: "a"}, 4},
+		{"tool definition", ServerConfig{ToolConcurrency: 4}, &types.ToolDefinition{Name: "a", MaxConcurrency: 2}, 2},
+		{"per tool config", ServerConfig{ToolConcurrencyLimits: map[string]int{"a": 1}}, &types.ToolDefinition{Name: "a", MaxConcurrency: 2}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewServer(tt.config).toolConcurrency(tt.tool); got != tt.want {
+				t.Fatalf("toolConcurrency = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	s := NewServer(ServerConfig{ToolTimeouts: map[string]time.Duration{"a": time.Second}})
+	if got := s.toolTimeout(&types.ToolDefinition{Name: "a", Timeout: time.Minute}); got != time.Second {
+		t.Fatalf("per tool timeout = %s, want 1s", got)
+	}
+}
+
+func TestAcquireUserSlot(t *testing.T) {
+	s := NewServer(ServerConfig{UserLimits: fixedUserLimits(1)})
+	alice, bob := uuid.New(), uuid.New()
+
+	releaseAlice, err := s.acquireUserSlot(context.Background(), alice)
+	if err != nil {
+		t.Fatalf("first slot: %v", err)
+	}
+	if _, err := s.acquireUserSlot(context.Background(), alice); !errors.Is(err, ErrUserConcurrencyLimit) {
+		t.Fatalf("second slot err = %v, want ErrUserConcurrencyLimit", err)
+	}
+
+	// 其他用户不受影响
+	releaseBob, err := s.acquireUserSlot(context.Background(), bob)
+	if err != nil {
+		t.Fatalf("other user slot: %v", err)
+	}
+	releaseBob()
+
+	// 释放可重复调用，名额只归还一次
+	releaseAlice()
+	releaseAlice()
+	release, err := s.acquireUserSlot(context.Background(), alice)
+	if err != nil {
+		t.Fatalf("slot after release: %v", err)
+	}
+	if _, err := s.acquireUserSlot(context.Background(), alice); !errors.Is(err, ErrUserConcurrencyLimit) {
+		t.Fatalf("slot count after double release: err = %v", err)
+	}
+	release()
+}
+
+func TestAnonymousUserSlotsShared(t *testing.T) {
+	s := NewServer(ServerConfig{UserLimits: fixedUserLimits(2)})
+	started, release := registerGatedTool(s, "slow")
+
+	// 匿名调用共用同一份配额，第三个并发调用被拒绝
+	responses := make(chan *types.MCPResponse, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			response, _ := s.HandleRequest(context.Background(), &types.MCPRequest{
+				MCPMessage: types.MCPMessage{JSONRPC: "2.0", ID: 1},
+				Method:     types.MCPMethodToolsCall,
+				Params:     types.ToolsCallRequest{Name: "slow"},
+			})
+			responses <- response
+		}()
+		<-started
+	}
+
+	result := toolResult(t, callTool(t, context.Background(), s, "slow", nil))
+	if !result.IsError || !strings.Contains(result.Content[0].Text, "同时执行的工具调用过多") {
+		t.Fatalf("third anonymous call = %+v, want concurrency limit error", result)
+	}
+
+	close(release)
+	for i := 0; i < 2; i++ {
+		if result := toolResult(t, <-responses); result.IsError {
+			t.Fatalf("anonymous call %d = %+v", i, result)
+		}
+	}
+}
+
+func TestToolPanicIsolation(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	s.Tools().RegisterTool(&types.ToolDefinition{
+		Name:        "broken",
+		InputSchema: map[string]interface{}{"type": "object"},
+		Handler: func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+			panic("nil map")
+		},
+	})
+
+	result := toolResult(t, callTool(t, context.Background(), s, "broken", nil))
+	if !result.IsError || strings.Contains(result.Content[0].Text, "nil map") {
+		t.Fatalf("result = %+v, want tool error without panic value", result)
+	}
+	if id, _ := result.Meta["correlationId"].(string); id == "" || !strings.Contains(result.Content[0].Text, id) {
+		t.Fatalf("result meta = %+v, want correlation id in text", result.Meta)
+	}
+
+	// 工具panic后槽位已释放，服务端继续工作
+	if response := handle(t, s, context.Background(), 2, types.MCPMethodPing, nil); response.Error != nil {
+		t.Fatalf("ping after panic: %+v", response.Error)
+	}
+}
+
+func TestMethodPanicRecovered(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	s.Handle("test/panic", func(ctx context.Context, request *types.MCPRequest) (*types.MCPResponse, error) {
+		panic("boom")
+	})
+
+	response := handle(t, s, context.Background(), 1, "test/panic", nil)
+	if errorCode(response) != types.MCPInternalError {
+		t.Fatalf("response = %+v, want internal error", response)
+	}
+	if data, _ := response.Error.Data.(map[string]interface{}); data["correlationId"] == "" || data["correlationId"] == nil {
+		t.Fatalf("error data = %+v, want correlation id", response.Error.Data)
+	}
+
+	// 通知发生panic时不返回响应
+	if response := handle(t, s, context.Background(), nil, "test/panic", nil); response != nil {
+		t.Fatalf("notification response = %+v, want nil", response)
+	}
+}
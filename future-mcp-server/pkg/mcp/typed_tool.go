@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+// ErrInvalidToolArguments 工具参数无法解码为处理器声明的输入结构体，或包含未声明的字段
+var ErrInvalidToolArguments = errors.New("invalid tool arguments")
+
+// ToolError 工具执行失败的原因，以isError结果而非JSON-RPC错误返回，便于调用方（模型）据此调整后重试
+type ToolError struct {
+	Message string
+}
+
+// Error 实现error接口
+func (e *ToolError) Error() string {
+	return e.Message
+}
+
+// Result 转换为工具错误结果
+func (e *ToolError) Result() *types.ToolsCallResponse {
+	return &types.ToolsCallResponse{
+		Content: []types.Content{TextContent(e.Message)},
+		IsError: true,
+	}
+}
+
+// TypedToolHandler 强类型工具处理器，input为按输入Schema解码后的参数
+type TypedToolHandler[In, Out any] func(ctx *types.ToolContext, input *In) (*Out, error)
+
+// toolsCallResponseType 处理器输出类型为types.ToolsCallResponse时直接作为工具结果返回
+var toolsCallResponseType = reflect.TypeOf(types.ToolsCallResponse{})
+
+// RegisterTypedTool 注册强类型工具
+// InputSchema由In的字段标签生成，调用时参数解码为In并拒绝未声明的字段；
+// Out为结构化结果类型，未显式设置OutputSchema时由Out生成，结果同时以structuredContent和JSON文本返回。
+// Out为types.ToolsCallResponse时处理器自行构造工具结果，不生成OutputSchema
+func RegisterTypedTool[In, Out any](registry *ToolRegistry, definition *types.ToolDefinition, handler TypedToolHandler[In, Out]) error {
+	inputSchema, err := schemaForType(reflect.TypeOf((*In)(nil)).Elem(), schemaModeInput)
+	if err != nil {
+		return fmt.Errorf("tool %s: input schema: %w", definition.Name, err)
+	}
+	definition.InputSchema = inputSchema
+
+	outputType := reflect.TypeOf((*Out)(nil)).Elem()
+	structured := outputType != toolsCallResponseType
+	if structured && definition.OutputSchema == nil {
+		outputSchema, err := schemaForType(outputType, schemaModeOutput)
+		if err != nil {
+			return fmt.Errorf("tool %s: output schema: %w", definition.Name, err)
+		}
+		definition.OutputSchema = outputSchema
+	}
+
+	definition.Handler = func(ctx *types.ToolContext, args interface{}) (*types.ToolsCallResponse, error) {
+		input := new(In)
+		if err := decodeToolArguments(args, input); err != nil {
+			return nil, err
+		}
+
+		output, err := handler(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		if output == nil {
+			return nil, fmt.Errorf("tool %s returned no result", definition.Name)
+		}
+		if !structured {
+			return any(output).(*types.ToolsCallResponse), nil
+		}
+		return structuredToolResult(output)
+	}
+
+	registry.RegisterTool(definition)
+	return nil
+}
+
+// decodeToolArguments 将工具参数解码为输入结构体，参数缺省时视为空对象
+func decodeToolArguments(args interface{}, target interface{}) error {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+
+	data, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToolArguments, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToolArguments, err)
+	}
+	return nil
+}
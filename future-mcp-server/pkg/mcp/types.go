@@ -0,0 +1,113 @@
+package mcp
+
+import "github.com/future-mcp/future-mcp-server/internal/types"
+
+// 编写模块与使用客户端所需的协议类型别名
+// internal/types不能被本仓库之外的代码导入，其他团队通过这些别名定义工具、资源与提示模板，
+// 或通过pkg/mcp/client连接服务器
+type (
+	// 消息
+	Request      = types.MCPRequest
+	Response     = types.MCPResponse
+	Notification = types.MCPNotification
+	RPCError     = types.MCPError
+
+	// 工具
+	ToolDefinition    = types.ToolDefinition
+	ToolHandler       = types.ToolHandler
+	ToolContext       = types.ToolContext
+	ToolAnnotations   = types.ToolAnnotations
+	ToolCategory      = types.ToolCategory
+	ToolsCallResponse = types.ToolsCallResponse
+	Content           = types.Content
+
+	// 资源
+	ResourceDefinition         = types.ResourceDefinition
+	ResourceTemplateDefinition = types.ResourceTemplateDefinition
+	ResourceHandler            = types.ResourceHandler
+	ResourcesReadResponse      = types.ResourcesReadResponse
+	ResourceContent            = types.ResourceContent
+
+	// 提示模板
+	PromptDefinition         = types.PromptDefinition
+	PromptArgumentDefinition = types.PromptArgumentDefinition
+	PromptHandler            = types.PromptHandler
+	PromptsGetResponse       = types.PromptsGetResponse
+	PromptMessage            = types.PromptMessage
+
+	// 采样与征询
+	CreateMessageRequest = types.CreateMessageRequest
+	CreateMessageResult  = types.CreateMessageResult
+	SamplingMessage      = types.SamplingMessage
+	ElicitRequest        = types.ElicitRequest
+	ElicitResult         = types.ElicitResult
+
+	// 客户端
+	InitializeResponse    = types.InitializeResponse
+	ServerCapabilities    = types.ServerCapabilities
+	ClientCapabilities    = types.ClientCapabilities
+	SamplingCapability    = types.SamplingCapability
+	ElicitationCapability = types.ElicitationCapability
+	RootsCapability       = types.RootsCapability
+	Tool                  = types.Tool
+	ToolsListResponse     = types.ToolsListResponse
+	Resource              = types.Resource
+	ResourceTemplate      = types.ResourceTemplate
+	ResourcesListResponse = types.ResourcesListResponse
+	Prompt                = types.Prompt
+	PromptsListResponse   = types.PromptsListResponse
+	ProgressParams        = types.ProgressParams
+	LoggingMessageParams  = types.LoggingMessageParams
+
+	// 其他
+	ImplementationInfo = types.ImplementationInfo
+	LoggingLevel       = types.LoggingLevel
+)
+
+// 方法名，客户端通过Call、Notify与OnNotification使用
+const (
+	MethodInitialize              = types.MCPMethodInitialize
+	MethodPing                    = types.MCPMethodPing
+	MethodToolsList               = types.MCPMethodToolsList
+	MethodToolsCall               = types.MCPMethodToolsCall
+	MethodResourcesList           = types.MCPMethodResourcesList
+	MethodResourcesRead           = types.MCPMethodResourcesRead
+	MethodResourcesTemplatesList  = types.MCPMethodResourcesTemplatesList
+	MethodResourcesSubscribe      = types.MCPMethodResourcesSubscribe
+	MethodResourcesUnsubscribe    = types.MCPMethodResourcesUnsubscribe
+	MethodPromptsList             = types.MCPMethodPromptsList
+	MethodPromptsGet              = types.MCPMethodPromptsGet
+	MethodCompletionComplete      = types.MCPMethodCompletionComplete
+	MethodLoggingSetLevel         = types.MCPMethodLoggingSetLevel
+	MethodSamplingCreateMessage   = types.MCPMethodSamplingCreateMessage
+	MethodElicitationCreate       = types.MCPMethodElicitationCreate
+	MethodInitializedNotification = types.MCPMethodNotificationsInitialized
+	MethodProgress                = types.MCPMethodProgress
+	MethodCancelled               = types.MCPMethodCancelled
+	MethodLoggingMessage          = types.MCPMethodLoggingMessage
+	MethodResourcesUpdated        = types.MCPMethodResourcesUpdated
+	MethodToolsListChanged        = types.MCPMethodToolsChanged
+	MethodResourcesListChanged    = types.MCPMethodResourcesChanged
+	MethodPromptsListChanged      = types.MCPMethodPromptsChanged
+)
+
+// 日志级别
+const (
+	LoggingLevelDebug     = types.LoggingLevelDebug
+	LoggingLevelInfo      = types.LoggingLevelInfo
+	LoggingLevelNotice    = types.LoggingLevelNotice
+	LoggingLevelWarning   = types.LoggingLevelWarning
+	LoggingLevelError     = types.LoggingLevelError
+	LoggingLevelCritical  = types.LoggingLevelCritical
+	LoggingLevelAlert     = types.LoggingLevelAlert
+	LoggingLevelEmergency = types.LoggingLevelEmergency
+)
+
+// 内容类型
+const (
+	ContentTypeText         = types.ContentTypeText
+	ContentTypeImage        = types.ContentTypeImage
+	ContentTypeAudio        = types.ContentTypeAudio
+	ContentTypeResource     = types.ContentTypeResource
+	ContentTypeResourceLink = types.ContentTypeResourceLink
+)
@@ -0,0 +1,184 @@
+package mcp
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// URITemplate RFC 6570 URI模板
+// 支持的表达式：{var} 简单展开（不跨越"/"）、{+var} 保留字符展开（可包含"/"）、
+// {?var1,var2} 表单式查询参数（匹配时可缺省）。
+type URITemplate struct {
+	raw       string
+	pattern   *regexp.Regexp
+	variables []string
+	parts     []uriTemplatePart
+}
+
+// uriTemplatePart 模板片段：字面量或表达式
+type uriTemplatePart struct {
+	literal  string
+	operator byte
+	names    []string
+}
+
+// uriTemplateVarName 变量名语法
+var uriTemplateVarName = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// ParseURITemplate 解析URI模板
+func ParseURITemplate(raw string) (*URITemplate, error) {
+	tmpl := &URITemplate{raw: raw}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+
+	rest := raw
+	for rest != "" {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			tmpl.addLiteral(&pattern, rest)
+			break
+		}
+		if start > 0 {
+			tmpl.addLiteral(&pattern, rest[:start])
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed expression in uri template: %s", raw)
+		}
+		expression := rest[start+1 : start+end]
+		rest = rest[start+end+1:]
+
+		part := uriTemplatePart{}
+		if expression != "" && strings.ContainsRune("+?", rune(expression[0])) {
+			part.operator = expression[0]
+			expression = expression[1:]
+		}
+
+		for _, name := range strings.Split(expression, ",") {
+			if !uriTemplateVarName.MatchString(name) {
+				return nil, fmt.Errorf("invalid variable %q in uri template: %s", name, raw)
+			}
+			part.names = append(part.names, name)
+			tmpl.variables = append(tmpl.variables, name)
+		}
+
+		switch part.operator {
+		case 0:
+			if len(part.names) != 1 {
+				return nil, fmt.Errorf("multiple variables in simple expression are not supported: %s", raw)
+			}
+			pattern.WriteString(`([^/?#]+)`)
+		case '+':
+			if len(part.names) != 1 {
+				return nil, fmt.Errorf("multiple variables in reserved expression are not supported: %s", raw)
+			}
+			pattern.WriteString(`([^?#]+)`)
+		case '?':
+			if rest != "" {
+				return nil, fmt.Errorf("query expression must be at the end of uri template: %s", raw)
+			}
+			pattern.WriteString(`(?:\?([^#]*))?`)
+		}
+
+		tmpl.parts = append(tmpl.parts, part)
+	}
+
+	pattern.WriteString("$")
+
+	compiled, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile uri template %s: %w", raw, err)
+	}
+	tmpl.pattern = compiled
+
+	return tmpl, nil
+}
+
+// addLiteral 添加字面量片段
+func (t *URITemplate) addLiteral(pattern *strings.Builder, literal string) {
+	pattern.WriteString(regexp.QuoteMeta(literal))
+	t.parts = append(t.parts, uriTemplatePart{literal: literal})
+}
+
+// String 返回原始模板
+func (t *URITemplate) String() string {
+	return t.raw
+}
+
+// Variables 返回模板中的变量名
+func (t *URITemplate) Variables() []string {
+	return append([]string(nil), t.variables...)
+}
+
+// Match 匹配URI并提取变量，不匹配时返回false
+func (t *URITemplate) Match(uri string) (map[string]string, bool) {
+	matches := t.pattern.FindStringSubmatch(uri)
+	if matches == nil {
+		return nil, false
+	}
+
+	vars := make(map[string]string, len(t.variables))
+	group := 1
+	for _, part := range t.parts {
+		if part.names == nil {
+			continue
+		}
+
+		value := matches[group]
+		group++
+
+		switch part.operator {
+		case '?':
+			query, err := url.ParseQuery(value)
+			if err != nil {
+				return nil, false
+			}
+			for _, name := range part.names {
+				if v := query.Get(name); v != "" {
+					vars[name] = v
+				}
+			}
+		default:
+			decoded, err := url.PathUnescape(value)
+			if err != nil {
+				return nil, false
+			}
+			vars[part.names[0]] = decoded
+		}
+	}
+
+	return vars, true
+}
+
+// Expand 使用变量展开模板，缺失的变量按RFC 6570规则省略
+func (t *URITemplate) Expand(vars map[string]string) string {
+	var uri strings.Builder
+	for _, part := range t.parts {
+		if part.names == nil {
+			uri.WriteString(part.literal)
+			continue
+		}
+
+		switch part.operator {
+		case 0:
+			uri.WriteString(url.PathEscape(vars[part.names[0]]))
+		case '+':
+			uri.WriteString(vars[part.names[0]])
+		case '?':
+			separator := "?"
+			for _, name := range part.names {
+				value, ok := vars[name]
+				if !ok {
+					continue
+				}
+				uri.WriteString(separator + name + "=" + url.QueryEscape(value))
+				separator = "&"
+			}
+		}
+	}
+	return uri.String()
+}
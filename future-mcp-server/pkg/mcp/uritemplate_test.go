@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/future-mcp/future-mcp-server/internal/types"
+)
+
+func TestParseURITemplateErrors(t *testing.T) {
+	tests := []string{
+		"curriculum://grade-{grade",
+		"curriculum://grade-{}",
+		"curriculum://grade-{grade-level}",
+		"curriculum://{grade,subject}",
+		"curriculum://{+grade,subject}",
+		"search://{?q}/suffix",
+	}
+	for _, raw := range tests {
+		t.Run(raw, func(t *testing.T) {
+			if _, err := ParseURITemplate(raw); err == nil {
+				t.Fatalf("ParseURITemplate(%q) succeeded, want error", raw)
+			}
+		})
+	}
+}
+
+func TestURITemplateMatch(t *testing.T) {
+	tests := []struct {
+		template string
+		uri      string
+		want     map[string]string
+		wantOK   bool
+	}{
+		{"curriculum://grade-{grade}/subject-{subject}", "curriculum://grade-3/subject-math", map[string]string{"grade": "3", "subject": "math"}, true},
+		{"curriculum://grade-{grade}/subject-{subject}", "curriculum://grade-3/subject-math/extra", nil, false},
+		{"curriculum://grade-{grade}/subject-{subject}", "curriculum://grade-/subject-math", nil, false},
+		{"material://{id}", "material://a%20b", map[string]string{"id": "a b"}, true},
+		{"material://{id}", "material://a/b", nil, false},
+		{"file://{+path}", "file://docs/math/unit1.pdf", map[string]string{"path": "docs/math/unit1.pdf"}, true},
+		{"search://materials{?q,grade}", "search://materials", map[string]string{}, true},
+		{"search://materials{?q,grade}", "search://materials?q=%E5%88%86%E6%95%B0&grade=3", map[string]string{"q": "分数", "grade": "3"}, true},
+		{"search://materials{?q,grade}", "search://materials?grade=3", map[string]string{"grade": "3"}, true},
+		{"search://materials{?q,grade}", "search://other?q=x", nil, false},
+		{"a.b://{x}", "aXb://y", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template+" "+tt.uri, func(t *testing.T) {
+			tmpl, err := ParseURITemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParseURITemplate: %v", err)
+			}
+			got, ok := tmpl.Match(tt.uri)
+			if ok != tt.wantOK {
+				t.Fatalf("Match ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Match vars = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURITemplateExpand(t *testing.T) {
+	tests := []struct {
+		template string
+		vars     map[string]string
+		want     string
+	}{
+		{"curriculum://grade-{grade}/subject-{subject}", map[string]string{"grade": "3", "subject": "math"}, "curriculum://grade-3/subject-math"},
+		{"material://{id}", map[string]string{"id": "a b/c"}, "material://a%20b%2Fc"},
+		{"file://{+path}", map[string]string{"path": "docs/unit1.pdf"}, "file://docs/unit1.pdf"},
+		{"search://materials{?q,grade}", map[string]string{"grade": "3"}, "search://materials?grade=3"},
+		{"search://materials{?q,grade}", map[string]string{"q": "分 数", "grade": "3"}, "search://materials?q=%E5%88%86+%E6%95%B0&grade=3"},
+		{"search://materials{?q,grade}", nil, "search://materials"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			tmpl, err := ParseURITemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParseURITemplate: %v", err)
+			}
+			if got := tmpl.Expand(tt.vars); got != tt.want {
+				t.Fatalf("Expand = %q, want %q", got, tt.want)
+			}
+			if got, ok := tmpl.Match(tmpl.Expand(tt.vars)); !ok || len(got) != len(tt.vars) {
+				t.Fatalf("Match(Expand) = %v, %v", got, ok)
+			}
+		})
+	}
+}
+
+func TestResourcesReadTemplate(t *testing.T) {
+	s := NewServer(ServerConfig{})
+	err := s.Resources().RegisterTemplate(&types.ResourceTemplateDefinition{
+		URITemplate: "lesson://grade-{grade}/unit-{unit}",
+		Name:        "课时",
+		Handler: func(ctx context.Context, uri string, vars map[string]string) (*types.ResourcesReadResponse, error) {
+			if vars["grade"] != "7" && vars["grade"] != "8" {
+				return nil, &InvalidResourceError{URI: uri, Reason: "unknown grade"}
+			}
+			return &types.ResourcesReadResponse{
+				Contents: []types.ResourceContent{{URI: uri, Text: vars["grade"] + "/" + vars["unit"]}},
+			}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterTemplate: %v", err)
+	}
+
+	tests := []struct {
+		uri      string
+		wantCode int
+		wantText string
+	}{
+		{"lesson://grade-8/unit-3", 0, "8/3"},
+		{"lesson://grade-9/unit-3", types.MCPInvalidParams, ""},
+		{"lesson://grade-8", types.MCPInvalidParams, ""},
+		{"unknown://resource", types.MCPInvalidParams, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			response := handle(t, s, context.Background(), 1, types.MCPMethodResourcesRead, map[string]string{"uri": tt.uri})
+			if code := errorCode(response); code != tt.wantCode {
+				t.Fatalf("error code = %d, want %d (%+v)", code, tt.wantCode, response.Error)
+			}
+			if tt.wantCode != 0 {
+				return
+			}
+			contents := response.Result.(*types.ResourcesReadResponse).Contents
+			if len(contents) != 1 || contents[0].Text != tt.wantText {
+				t.Fatalf("contents = %+v, want %q", contents, tt.wantText)
+			}
+		})
+	}
+}